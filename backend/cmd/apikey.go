@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+// apiKeyCommand for managing the API keys which scripts authenticate with.
+func apiKeyCommand(cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-key",
+		Short: "Manage API keys for calling the API from scripts",
+	}
+
+	var create APIKeyConfig
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key, which is only shown once",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateAPIKey(cmd.Context(), cfg, &create, cmd.OutOrStdout())
+		},
+	}
+
+	createCmd.Flags().StringVar(&create.User, "user", "", "Name of the user who the key authenticates as")
+	createCmd.Flags().StringVar(&create.Name, "name", "", "Name describing what the key is used for")
+	createCmd.Flags().StringSliceVar(&create.Scopes, "scope", []string{string(todo.ScopeListsRead)}, "Scopes granted to the key: lists:read, lists:write or admin")
+	createCmd.Flags().DurationVar(&create.Expiry, "expiry", 90*24*time.Hour, "How long until the key expires, where 0 never expires")
+	createCmd.MarkFlagRequired("user")
+	createCmd.MarkFlagRequired("name")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List API keys, including those which have expired or been revoked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListAPIKeys(cmd.Context(), cfg, cmd.OutOrStdout())
+		},
+	}
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key, so that it can no longer be used",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RevokeAPIKey(cmd.Context(), cfg, args[0], cmd.OutOrStdout())
+		},
+	}
+
+	cmd.AddCommand(createCmd, listCmd, revokeCmd)
+
+	return cmd
+}
+
+type APIKeyConfig struct {
+	Expiry time.Duration
+	Name   string
+	Scopes []string
+	User   string
+}
+
+func CreateAPIKey(ctx context.Context, cfg *Config, keyCfg *APIKeyConfig, stdout io.Writer) error {
+	if strings.TrimSpace(keyCfg.User) == "" {
+		return errors.New("user must not be blank")
+	}
+
+	if strings.TrimSpace(keyCfg.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+
+	if keyCfg.Expiry < 0 {
+		return fmt.Errorf("expiry must not be negative: %s", keyCfg.Expiry)
+	}
+
+	if len(keyCfg.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+
+	scopes := make(todo.Scopes, len(keyCfg.Scopes))
+	for i, s := range keyCfg.Scopes {
+		sc, err := todo.ParseScope(s)
+		if err != nil {
+			return err
+		}
+
+		scopes[i] = sc
+	}
+
+	var expires *time.Time
+	if keyCfg.Expiry > 0 {
+		e := time.Now().Add(keyCfg.Expiry)
+		expires = &e
+	}
+
+	k, err := todo.NewAPIKey()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg.DBConn)
+	if err != nil {
+		return fmt.Errorf("unable open DB: %w", err)
+	}
+
+	defer db.Close()
+
+	users := database.NewUserRepository(db)
+
+	key, err := users.CreateAPIKey(ctx, todo.APIKeyCreate{
+		User:    keyCfg.User,
+		Name:    keyCfg.Name,
+		Scopes:  scopes,
+		Expires: expires,
+		Hash:    todo.HashAPIKey(k),
+	})
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(stdout, fmt.Sprintf("Created API key %s %q for user %q, with scopes: %s\n", key.ID, key.Name, key.User.Name, key.Scopes))
+	io.WriteString(stdout, "Send the key in the X-API-Key header. It cannot be shown again:\n\n")
+	io.WriteString(stdout, k+"\n")
+
+	return nil
+}
+
+func ListAPIKeys(ctx context.Context, cfg *Config, stdout io.Writer) error {
+	db, err := openDB(cfg.DBConn)
+	if err != nil {
+		return fmt.Errorf("unable open DB: %w", err)
+	}
+
+	defer db.Close()
+
+	keys, err := database.NewUserRepository(db).APIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSER\tNAME\tSCOPES\tCREATED\tEXPIRES\tSTATUS")
+
+	for _, k := range keys {
+		expires := "never"
+		if k.Expires != nil {
+			expires = k.Expires.Format(time.RFC3339)
+		}
+
+		status := "active"
+		switch {
+		case k.Revoked != nil:
+			status = "revoked"
+		case !k.Active(now):
+			status = "expired"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.User.Name, k.Name, k.Scopes, k.Created.Format(time.RFC3339), expires, status)
+	}
+
+	return tw.Flush()
+}
+
+func RevokeAPIKey(ctx context.Context, cfg *Config, keyID string, stdout io.Writer) error {
+	db, err := openDB(cfg.DBConn)
+	if err != nil {
+		return fmt.Errorf("unable open DB: %w", err)
+	}
+
+	defer db.Close()
+
+	key, err := database.NewUserRepository(db).RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(stdout, fmt.Sprintf("Revoked API key %s %q of user %q\n", key.ID, key.Name, key.User.Name))
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"golang.org/x/exp/slog"
+)
+
+// setupMetrics as the global meter provider, where metrics are exported to the Prometheus registry when it is scraped.
+func setupMetrics(ctx context.Context, reg prometheus.Registerer, logger *slog.Logger) (shutdown func(), err error) {
+	r, err := newResource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, fmt.Errorf("creating Prometheus metric exporter: %w", err)
+	}
+
+	mp := metric.NewMeterProvider(
+		metric.WithReader(exp),
+		metric.WithResource(r),
+	)
+
+	shutdown = func() {
+		if err := mp.Shutdown(context.Background()); err != nil {
+			logger.Error("Failed to shutdown meter provider", slog.String("error", err.Error()))
+		}
+	}
+
+	otel.SetMeterProvider(mp)
+
+	return shutdown, nil
+}
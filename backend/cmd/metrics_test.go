@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/exp/slog"
+)
+
+func TestSetupMetrics(t *testing.T) {
+	// The meter provider is global, so the test cannot be run in parallel
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reg := prometheus.NewRegistry()
+
+	shutdown, err := setupMetrics(ctx, reg, logger)
+	require.NoError(t, err, "Setup Error")
+
+	defer shutdown()
+
+	counter, err := otel.Meter("test").Int64Counter("test.requests")
+	require.NoError(t, err, "Counter Error")
+
+	counter.Add(ctx, 3)
+
+	s := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer s.Close()
+
+	res, err := http.Get(s.URL)
+	require.NoError(t, err, "Scrape Error")
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err, "Body Read Error")
+
+	assert.Regexp(t, `(?m)^test_requests_total\{otel_scope_name="test",otel_scope_version=""\} 3$`, string(body), "Counter")
+	assert.Regexp(t, `(?m)^target_info\{.*service_name="todo-list-api".*\} 1$`, string(body), "Resource")
+}
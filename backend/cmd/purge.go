@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// trashPurger which permanently removes lists and items once they have been in the trash longer than the retention.
+type trashPurger interface {
+	PurgeTrash(ctx context.Context, retention time.Duration) (lists, items int64, err error)
+}
+
+// purgeTrash immediately, and then every interval, until the context is done. Failures are logged, and retried at the
+// next interval.
+func purgeTrash(ctx context.Context, p trashPurger, retention, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		lists, items, err := p.PurgeTrash(ctx, retention)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			logger.Error("Failed to purge trash", slog.String("error", err.Error()))
+		case lists > 0 || items > 0:
+			logger.Info("Purged trash", slog.Int64("lists", lists), slog.Int64("items", items), slog.Duration("retention", retention))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
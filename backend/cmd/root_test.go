@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Flags      []string
+		RemoteAddr string
+	}
+
+	type want struct {
+		Error string
+		User  string
+	}
+
+	testTable := map[string]struct {
+		Args args
+		Want want
+	}{
+		"Spoofed User Header by default": {
+			Args: args{},
+			Want: want{Error: "authentication required"},
+		},
+		"User Header from the Proxy": {
+			Args: args{Flags: []string{"--user-header", "X-Forwarded-User", "--user-header-proxy", "192.0.2.1"}},
+			Want: want{User: "alice"},
+		},
+		"User Header from a Proxy range": {
+			Args: args{Flags: []string{"--user-header", "X-Forwarded-User", "--user-header-proxy", "10.0.0.0/8,192.0.2.0/24"}},
+			Want: want{User: "alice"},
+		},
+		"Spoofed User Header, not from the Proxy": {
+			Args: args{Flags: []string{"--user-header", "X-Forwarded-User", "--user-header-proxy", "10.0.0.0/8"}},
+			Want: want{Error: "authentication required"},
+		},
+		"IPv4 mapped IPv6 address of the Proxy": {
+			Args: args{Flags: []string{"--user-header", "X-Forwarded-User", "--user-header-proxy", "10.1.2.3"}, RemoteAddr: "[::ffff:10.1.2.3]:41234"},
+			Want: want{User: "alice"},
+		},
+		"Invalid Proxy address": {
+			Args: args{Flags: []string{"--user-header", "X-Forwarded-User", "--user-header-proxy", "proxy.local"}},
+			Want: want{Error: `invalid user header proxy address "proxy.local"`},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root := Root(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			require.NoError(t, root.ParseFlags(tt.Args.Flags), "Flags")
+
+			header, err := root.PersistentFlags().GetString("user-header")
+			require.NoError(t, err, "User Header flag")
+
+			proxyAddrs, err := root.PersistentFlags().GetStringSlice("user-header-proxy")
+			require.NoError(t, err, "User Header Proxy flag")
+
+			proxies, err := parseProxies(proxyAddrs)
+			if err != nil {
+				assert.ErrorContains(t, err, tt.Want.Error, "Proxies error")
+				return
+			}
+
+			auth := newAuthenticator(&Config{UserHeader: header}, proxies, authRepo{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lists", http.NoBody)
+			req.Header.Set("X-Forwarded-User", "alice")
+
+			if tt.Args.RemoteAddr != "" {
+				req.RemoteAddr = tt.Args.RemoteAddr
+			}
+
+			id, err := auth.Authenticate(req)
+
+			if tt.Want.Error != "" {
+				assert.EqualError(t, err, tt.Want.Error, "Authenticate error")
+				return
+			}
+
+			require.NoError(t, err, "Authenticate error")
+			assert.Equal(t, tt.Want.User, id.User.Name, "User")
+		})
+	}
+}
+
+// authRepo which provisions every user, and has no API keys.
+type authRepo struct{}
+
+func (authRepo) ProvisionUser(_ context.Context, name string) (*todo.User, error) {
+	return &todo.User{ID: "7", Name: name}, nil
+}
+
+func (authRepo) APIKey(context.Context, []byte) (*todo.APIKey, error) {
+	return nil, todo.NotFoundError("api key does not exist")
+}
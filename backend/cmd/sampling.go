@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Samplers which may be configured, named as they are for the OTEL_TRACES_SAMPLER environment variable.
+const (
+	samplerAlwaysOn                = "always_on"
+	samplerAlwaysOff               = "always_off"
+	samplerTraceIDRatio            = "traceidratio"
+	samplerParentBasedAlwaysOn     = "parentbased_always_on"
+	samplerParentBasedAlwaysOff    = "parentbased_always_off"
+	samplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+)
+
+// defaultSampler from the OTEL_TRACES_SAMPLER environment variable, otherwise sampling every trace unless the parent
+// span was not sampled, as the SDK does.
+func defaultSampler() string {
+	if s := os.Getenv("OTEL_TRACES_SAMPLER"); s != "" {
+		return s
+	}
+
+	return samplerParentBasedAlwaysOn
+}
+
+// newSampler by its name, with the argument of ratio based samplers, which defaults to sampling every trace. Routes
+// may be sampled at their own ratios, overriding the sampler for the spans of requests to them. Such spans are named
+// by the method and route, as registered by routes.Handler, such as "GET /api/v1/lists". Parent based samplers still
+// defer to the parent span, so that traces are never broken up.
+func newSampler(name, arg string, routes map[string]string) (trace.Sampler, error) {
+	ratio := 1.0
+
+	if arg != "" && (name == samplerTraceIDRatio || name == samplerParentBasedTraceIDRatio) {
+		var err error
+		if ratio, err = parseRatio(arg); err != nil {
+			return nil, fmt.Errorf("invalid trace sampler arg: %w", err)
+		}
+	}
+
+	var (
+		root        trace.Sampler
+		parentBased bool
+	)
+
+	switch name {
+	case samplerAlwaysOn:
+		root = trace.AlwaysSample()
+	case samplerAlwaysOff:
+		root = trace.NeverSample()
+	case samplerTraceIDRatio:
+		root = trace.TraceIDRatioBased(ratio)
+	case samplerParentBasedAlwaysOn:
+		root, parentBased = trace.AlwaysSample(), true
+	case samplerParentBasedAlwaysOff:
+		root, parentBased = trace.NeverSample(), true
+	case samplerParentBasedTraceIDRatio:
+		root, parentBased = trace.TraceIDRatioBased(ratio), true
+	default:
+		return nil, fmt.Errorf("unknown trace sampler %q, must be one of: %s", name, strings.Join([]string{
+			samplerAlwaysOn, samplerAlwaysOff, samplerTraceIDRatio,
+			samplerParentBasedAlwaysOn, samplerParentBasedAlwaysOff, samplerParentBasedTraceIDRatio,
+		}, ", "))
+	}
+
+	if len(routes) > 0 {
+		rs := routeSampler{routes: make(map[string]trace.Sampler, len(routes)), fallback: root}
+
+		for route, arg := range routes {
+			ratio, err := parseRatio(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trace sample ratio for route %q: %w", route, err)
+			}
+
+			rs.routes[route] = trace.TraceIDRatioBased(ratio)
+		}
+
+		root = rs
+	}
+
+	if parentBased {
+		return trace.ParentBased(root), nil
+	}
+
+	return root, nil
+}
+
+func parseRatio(s string) (float64, error) {
+	ratio, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("ratio %q must be a number between 0 and 1", s)
+	}
+
+	return ratio, nil
+}
+
+// routeSampler of the spans of requests to routes by their own samplers, falling back to another sampler for all other
+// spans.
+type routeSampler struct {
+	routes   map[string]trace.Sampler
+	fallback trace.Sampler
+}
+
+func (s routeSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if sampler, ok := s.routes[spanRoute(p)]; ok {
+		return sampler.ShouldSample(p)
+	}
+
+	return s.fallback.ShouldSample(p)
+}
+
+func (s routeSampler) Description() string {
+	routes := make([]string, 0, len(s.routes))
+	for route, sampler := range s.routes {
+		routes = append(routes, route+"="+sampler.Description())
+	}
+
+	sort.Strings(routes)
+
+	return fmt.Sprintf("RouteSampler{routes:{%s},fallback:%s}", strings.Join(routes, ","), s.fallback.Description())
+}
+
+// spanRoute which the span is for, from its http.route attribute, or otherwise its name without the method.
+func spanRoute(p trace.SamplingParameters) string {
+	for _, attr := range p.Attributes {
+		if attr.Key == semconv.HTTPRouteKey {
+			return attr.Value.AsString()
+		}
+	}
+
+	if _, route, ok := strings.Cut(p.Name, " "); ok {
+		return route
+	}
+
+	return p.Name
+}
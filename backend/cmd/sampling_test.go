@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestNewSampler(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Name   string
+		Arg    string
+		Routes map[string]string
+	}
+
+	type span struct {
+		Name          string
+		ParentSampled *bool
+		Route         string
+	}
+
+	type want struct {
+		Error   string
+		Sampled map[string]bool
+	}
+
+	spans := map[string]span{
+		"Root":                  {Name: "GET /api/v1/lists"},
+		"Root Ping":             {Name: "GET /ping"},
+		"Root Route Attribute":  {Name: "HTTP GET", Route: "/ping"},
+		"Sampled Parent":        {Name: "GET /api/v1/lists", ParentSampled: ptr(true)},
+		"Unsampled Parent":      {Name: "GET /api/v1/lists", ParentSampled: ptr(false)},
+		"Sampled Parent Ping":   {Name: "GET /ping", ParentSampled: ptr(true)},
+		"Unsampled Parent Ping": {Name: "GET /ping", ParentSampled: ptr(false)},
+	}
+
+	testTable := map[string]struct {
+		Args args
+		Want want
+	}{
+		"Always On": {
+			Args: args{Name: samplerAlwaysOn},
+			Want: want{Sampled: map[string]bool{"Root": true, "Unsampled Parent": true}},
+		},
+		"Always Off": {
+			Args: args{Name: samplerAlwaysOff},
+			Want: want{Sampled: map[string]bool{"Root": false, "Sampled Parent": false}},
+		},
+		"Trace ID Ratio - None": {
+			Args: args{Name: samplerTraceIDRatio, Arg: "0"},
+			Want: want{Sampled: map[string]bool{"Root": false, "Sampled Parent": false}},
+		},
+		"Trace ID Ratio - Default": {
+			Args: args{Name: samplerTraceIDRatio},
+			Want: want{Sampled: map[string]bool{"Root": true, "Unsampled Parent": true}},
+		},
+		"Parent Based": {
+			Args: args{Name: samplerParentBasedAlwaysOn},
+			Want: want{Sampled: map[string]bool{"Root": true, "Sampled Parent": true, "Unsampled Parent": false}},
+		},
+		"Parent Based Always Off": {
+			Args: args{Name: samplerParentBasedAlwaysOff},
+			Want: want{Sampled: map[string]bool{"Root": false, "Sampled Parent": true, "Unsampled Parent": false}},
+		},
+		"Parent Based Trace ID Ratio": {
+			Args: args{Name: samplerParentBasedTraceIDRatio, Arg: "0.0"},
+			Want: want{Sampled: map[string]bool{"Root": false, "Sampled Parent": true}},
+		},
+		"Route Overrides": {
+			Args: args{Name: samplerAlwaysOn, Routes: map[string]string{"/ping": "0"}},
+			Want: want{
+				Sampled: map[string]bool{
+					"Root":                 true,
+					"Root Ping":            false,
+					"Root Route Attribute": false,
+					"Sampled Parent Ping":  false,
+				},
+			},
+		},
+		"Route Overrides - Parent Based": {
+			Args: args{Name: samplerParentBasedAlwaysOff, Routes: map[string]string{"/ping": "0", "/api/v1/lists": "1"}},
+			Want: want{
+				Sampled: map[string]bool{
+					"Root":                  true,
+					"Root Ping":             false,
+					"Sampled Parent Ping":   true,
+					"Unsampled Parent":      false,
+					"Unsampled Parent Ping": false,
+				},
+			},
+		},
+		"Unknown Sampler": {
+			Args: args{Name: "sometimes"},
+			Want: want{Error: `unknown trace sampler "sometimes", must be one of: always_on, always_off, traceidratio, parentbased_always_on, parentbased_always_off, parentbased_traceidratio`},
+		},
+		"Invalid Ratio": {
+			Args: args{Name: samplerTraceIDRatio, Arg: "half"},
+			Want: want{Error: `invalid trace sampler arg: ratio "half" must be a number between 0 and 1`},
+		},
+		"Ratio out of Range": {
+			Args: args{Name: samplerParentBasedTraceIDRatio, Arg: "1.5"},
+			Want: want{Error: `invalid trace sampler arg: ratio "1.5" must be a number between 0 and 1`},
+		},
+		"Invalid Route Ratio": {
+			Args: args{Name: samplerAlwaysOn, Routes: map[string]string{"/ping": "-1"}},
+			Want: want{Error: `invalid trace sample ratio for route "/ping": ratio "-1" must be a number between 0 and 1`},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sampler, err := newSampler(tt.Args.Name, tt.Args.Arg, tt.Args.Routes)
+
+			if tt.Want.Error != "" {
+				assert.EqualError(t, err, tt.Want.Error, "Sampler Error")
+				return
+			}
+
+			require.NoError(t, err, "Sampler Error")
+
+			for spanName, want := range tt.Want.Sampled {
+				s := spans[spanName]
+
+				p := sdktrace.SamplingParameters{
+					ParentContext: context.Background(),
+					TraceID:       trace.TraceID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+					Name:          s.Name,
+				}
+
+				if s.Route != "" {
+					p.Attributes = append(p.Attributes, semconv.HTTPRoute(s.Route))
+				}
+
+				if s.ParentSampled != nil {
+					var flags trace.TraceFlags
+					if *s.ParentSampled {
+						flags = trace.FlagsSampled
+					}
+
+					parent := trace.NewSpanContext(trace.SpanContextConfig{
+						TraceID:    p.TraceID,
+						SpanID:     trace.SpanID{1},
+						TraceFlags: flags,
+						Remote:     true,
+					})
+
+					p.ParentContext = trace.ContextWithRemoteSpanContext(p.ParentContext, parent)
+				}
+
+				res := sampler.ShouldSample(p)
+
+				assert.Equal(t, want, res.Decision == sdktrace.RecordAndSample, "%s: Sampled", spanName)
+			}
+		})
+	}
+}
+
+func TestNewSampler_Handler(t *testing.T) {
+	// The tracer provider is global, so the test cannot be run in parallel
+
+	sampler, err := newSampler(samplerAlwaysOn, "", map[string]string{"/ping": "0"})
+	require.NoError(t, err, "Sampler Error")
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(sr))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	otel.SetTracerProvider(tp)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := routes.Handler(routes.NewListAPI(nil), routes.Authenticators{}, logger, nil)
+
+	for _, path := range []string{"/ping", "/api/v1/lists", "/metrics"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	}
+
+	var names []string
+	for _, s := range sr.Ended() {
+		names = append(names, s.Name())
+	}
+
+	assert.Equal(t, []string{"GET /api/v1/lists", "GET /metrics"}, names, "Sampled Spans")
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	traceExporterOTLPHTTP = "otlp-http"
+	traceExporterOTLPGRPC = "otlp-grpc"
+	traceExporterJaeger   = "jaeger"
+	traceExporterStdout   = "stdout"
+	traceExporterNone     = "none"
+)
+
+// traceExporters which traces may be exported with.
+var traceExporters = []string{traceExporterOTLPHTTP, traceExporterOTLPGRPC, traceExporterJaeger, traceExporterStdout, traceExporterNone}
+
+func validTraceExporter(name string) bool {
+	for _, e := range traceExporters {
+		if e == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultTraceExporter is OTLP, over the protocol within the standard environment variables. HTTP is used when no
+// protocol is set, as it is for the OTLP exporters of other languages.
+func defaultTraceExporter() string {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+
+	if protocol == "grpc" {
+		return traceExporterOTLPGRPC
+	}
+
+	return traceExporterOTLPHTTP
+}
+
+// setupTracing with the named exporter as the global tracer provider, where spans are sampled by the sampler. Spans are
+// exported in batches, which are flushed by the shutdown func. Stdout is written to by the stdout exporter.
+func setupTracing(ctx context.Context, exporter string, sampler trace.Sampler, logger *slog.Logger, stdout io.Writer) (shutdown func(), err error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if exporter == traceExporterNone {
+		return func() {}, nil
+	}
+
+	r, err := newResource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := newTraceExporter(ctx, exporter, stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == traceExporterJaeger {
+		logger.Warn("The Jaeger trace exporter is deprecated, Jaeger accepts OTLP which should be used instead")
+	}
+
+	tp := trace.NewTracerProvider(
+		trace.WithBatcher(exp),
+		trace.WithResource(r),
+		trace.WithSampler(sampler),
+	)
+
+	shutdown = func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			logger.Error("Failed to shutdown tracing provider", slog.String("error", err.Error()))
+		}
+	}
+
+	otel.SetTracerProvider(tp)
+
+	return shutdown, nil
+}
+
+// newResource describing the service, which its traces and metrics are from.
+func newResource(ctx context.Context) (*resource.Resource, error) {
+	r, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithFromEnv(),
+		resource.WithProcess(),
+		resource.WithOS(),
+		resource.WithContainer(),
+		resource.WithHost(),
+		resource.WithAttributes(
+			semconv.ServiceName("todo-list-api"),
+			semconv.ServiceVersion("v0.1.0"),
+			attribute.String("environment", "demo"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating telemetry resource: %w", err)
+	}
+
+	return r, nil
+}
+
+// newTraceExporter by its name. Where spans are exported to is configured by the standard environment variables, such as
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_JAEGER_ENDPOINT, except for stdout.
+func newTraceExporter(ctx context.Context, name string, stdout io.Writer) (trace.SpanExporter, error) {
+	var (
+		exp trace.SpanExporter
+		err error
+	)
+
+	switch name {
+	case traceExporterOTLPHTTP:
+		exp, err = otlptracehttp.New(ctx)
+	case traceExporterOTLPGRPC:
+		exp, err = otlptracegrpc.New(ctx)
+	case traceExporterJaeger:
+		exp, err = jaeger.New(jaeger.WithCollectorEndpoint())
+	case traceExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(stdout))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("creating %s trace exporter: %w", name, err)
+	}
+
+	return exp, nil
+}
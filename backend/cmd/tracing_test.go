@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewTraceExporter(t *testing.T) {
+	// Exporters are configured by environment variables, so the tests cannot be run in parallel
+
+	testTable := map[string]struct {
+		Receiver func(t *testing.T, spans *receivedSpans) map[string]string
+	}{
+		traceExporterOTLPHTTP: {
+			Receiver: func(t *testing.T, spans *receivedSpans) map[string]string {
+				s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != http.MethodPost || r.URL.Path != "/v1/traces" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err, "Request Body Read Error")
+
+					var req coltracepb.ExportTraceServiceRequest
+					require.NoError(t, proto.Unmarshal(b, &req), "Request Unmarshal Error")
+
+					spans.addRequest(&req)
+
+					res, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+					w.Header().Set("Content-Type", "application/x-protobuf")
+					w.Write(res)
+				}))
+				t.Cleanup(s.Close)
+
+				return map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": s.URL}
+			},
+		},
+		traceExporterOTLPGRPC: {
+			Receiver: func(t *testing.T, spans *receivedSpans) map[string]string {
+				lis, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err, "Listen Error")
+
+				s := grpc.NewServer()
+				coltracepb.RegisterTraceServiceServer(s, &grpcTraceReceiver{spans: spans})
+
+				go s.Serve(lis)
+				t.Cleanup(s.Stop)
+
+				return map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://" + lis.Addr().String(), "OTEL_EXPORTER_OTLP_INSECURE": "true"}
+			},
+		},
+		traceExporterJaeger: {
+			Receiver: func(t *testing.T, spans *receivedSpans) map[string]string {
+				s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err, "Request Body Read Error")
+
+					// Spans are thrift encoded, in which their names are kept as is
+					if r.URL.Path == "/api/traces" && bytes.Contains(b, []byte("test-span")) {
+						spans.add("test-span")
+					}
+				}))
+				t.Cleanup(s.Close)
+
+				return map[string]string{"OTEL_EXPORTER_JAEGER_ENDPOINT": s.URL + "/api/traces"}
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			var spans receivedSpans
+
+			for k, v := range tt.Receiver(t, &spans) {
+				t.Setenv(k, v)
+			}
+
+			ctx := context.Background()
+
+			exp, err := newTraceExporter(ctx, name, io.Discard)
+			require.NoError(t, err, "Exporter Error")
+
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+			_, span := tp.Tracer("test").Start(ctx, "test-span")
+			span.End()
+
+			require.NoError(t, tp.Shutdown(ctx), "Shutdown Error")
+
+			assert.Equal(t, []string{"test-span"}, spans.names(), "Received Spans")
+		})
+	}
+}
+
+func TestNewTraceExporter_Stdout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+
+	exp, err := newTraceExporter(ctx, traceExporterStdout, &buf)
+	require.NoError(t, err, "Exporter Error")
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+	_, span := tp.Tracer("test").Start(ctx, "test-span")
+	span.End()
+
+	require.NoError(t, tp.Shutdown(ctx), "Shutdown Error")
+
+	assert.Contains(t, buf.String(), `"Name":"test-span"`, "Exported Span")
+}
+
+func TestNewTraceExporter_Unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTraceExporter(context.Background(), "zipkin", io.Discard)
+
+	assert.EqualError(t, err, `unknown trace exporter "zipkin"`, "Exporter Error")
+	assert.False(t, validTraceExporter("zipkin"), "Valid Exporter")
+}
+
+func TestDefaultTraceExporter(t *testing.T) {
+	testTable := map[string]struct {
+		Env  map[string]string
+		Want string
+	}{
+		"No Protocol": {
+			Want: traceExporterOTLPHTTP,
+		},
+		"gRPC": {
+			Env:  map[string]string{"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"},
+			Want: traceExporterOTLPGRPC,
+		},
+		"HTTP": {
+			Env:  map[string]string{"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf"},
+			Want: traceExporterOTLPHTTP,
+		},
+		"Traces Protocol Override": {
+			Env:  map[string]string{"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL": "grpc"},
+			Want: traceExporterOTLPGRPC,
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "")
+
+			for k, v := range tt.Env {
+				t.Setenv(k, v)
+			}
+
+			assert.Equal(t, tt.Want, defaultTraceExporter(), "Trace Exporter")
+		})
+	}
+}
+
+func TestSetupTracing(t *testing.T) {
+	// The tracer provider is global, so the test cannot be run in parallel
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var buf bytes.Buffer
+
+	shutdown, err := setupTracing(ctx, traceExporterStdout, sdktrace.AlwaysSample(), logger, &buf)
+	require.NoError(t, err, "Setup Error")
+
+	_, span := otel.Tracer("test").Start(ctx, "test-span")
+	span.End()
+
+	// Spans are batched, and only exported once flushed
+	shutdown()
+
+	assert.Contains(t, buf.String(), `"Name":"test-span"`, "Exported Span")
+	assert.Contains(t, buf.String(), `"Value":"todo-list-api"`, "Service Name")
+}
+
+// receivedSpans by a receiver, by their names.
+type receivedSpans struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+func (r *receivedSpans) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, name)
+}
+
+func (r *receivedSpans) addRequest(req *coltracepb.ExportTraceServiceRequest) {
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				r.add(s.GetName())
+			}
+		}
+	}
+}
+
+func (r *receivedSpans) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.spans
+}
+
+// grpcTraceReceiver of OTLP spans, in place of a collector.
+type grpcTraceReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+	spans *receivedSpans
+}
+
+func (g *grpcTraceReceiver) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	g.spans.addRequest(req)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
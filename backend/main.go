@@ -6,10 +6,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// Embedded, as the release image has no timezone database for scheduling recurring items
+	_ "time/tzdata"
 
 	"golang.org/x/exp/slog"
 
 	"github.com/dackroyd/todo-list/backend/cmd"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
 )
 
 func main() {
@@ -18,7 +21,7 @@ func main() {
 
 	var logLevel slog.LevelVar
 
-	h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel})
+	h := routes.NewTraceLogHandler(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel}))
 	logger := slog.New(h)
 	slog.SetDefault(logger)
 
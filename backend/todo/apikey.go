@@ -0,0 +1,102 @@
+package todo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scope of access granted to an API key.
+type Scope string
+
+const (
+	// ScopeListsRead allows TODO lists, and everything within them, to be read.
+	ScopeListsRead Scope = "lists:read"
+	// ScopeListsWrite allows TODO lists, and everything within them, to be changed.
+	ScopeListsWrite Scope = "lists:write"
+	// ScopeAdmin allows everything, as though every other scope was granted.
+	ScopeAdmin Scope = "admin"
+)
+
+// ParseScope of an API key, which must be one of the known scopes.
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(s); sc {
+	case ScopeListsRead, ScopeListsWrite, ScopeAdmin:
+		return sc, nil
+	default:
+		return "", InvalidArgumentError(fmt.Sprintf("unknown scope %q, must be one of %q, %q or %q", s, ScopeListsRead, ScopeListsWrite, ScopeAdmin))
+	}
+}
+
+// Scopes granted for access to the API.
+type Scopes []Scope
+
+// Allow the scope when it has been granted, or admin access has been granted.
+func (s Scopes) Allow(scope Scope) bool {
+	for _, sc := range s {
+		if sc == scope || sc == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Scopes) String() string {
+	ss := make([]string, len(s))
+	for i, sc := range s {
+		ss[i] = string(sc)
+	}
+
+	return strings.Join(ss, " ")
+}
+
+// APIKey which authenticates scripts as the user who the key belongs to, with access limited to its scopes. Only a
+// hash of the key is stored, so the key itself cannot be retrieved after it has been created.
+type APIKey struct {
+	ID      string
+	User    User
+	Name    string
+	Scopes  Scopes
+	Created time.Time
+	Expires *time.Time
+	Revoked *time.Time
+}
+
+// Active when the key has neither been revoked nor expired at the time.
+func (k APIKey) Active(at time.Time) bool {
+	return k.Revoked == nil && (k.Expires == nil || at.Before(*k.Expires))
+}
+
+// APIKeyCreate is the definition of a new API key.
+type APIKeyCreate struct {
+	// User who the key belongs to, by name
+	User    string
+	Name    string
+	Scopes  Scopes
+	Expires *time.Time
+	// Hash of the key, from HashAPIKey
+	Hash []byte
+}
+
+// apiKeyPrefix identifies API keys, such as when they are accidentally committed to source control.
+const apiKeyPrefix = "todo_"
+
+// NewAPIKey which is randomly generated.
+func NewAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIKey for storage and lookup. Keys are random with high entropy, so a fast hash is sufficient, unlike passwords.
+func HashAPIKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
@@ -0,0 +1,61 @@
+package todo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+func TestParseScope(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []todo.Scope{todo.ScopeListsRead, todo.ScopeListsWrite, todo.ScopeAdmin} {
+		sc, err := todo.ParseScope(string(s))
+		require.NoError(t, err, "Parse error")
+		assert.Equal(t, s, sc, "Scope")
+	}
+
+	_, err := todo.ParseScope("lists:delete")
+	assert.EqualError(t, err, `unknown scope "lists:delete", must be one of "lists:read", "lists:write" or "admin"`, "Parse error")
+}
+
+func TestScopes_Allow(t *testing.T) {
+	t.Parallel()
+
+	testTable := map[string]struct {
+		Scopes todo.Scopes
+		Scope  todo.Scope
+		Want   bool
+	}{
+		"No Scopes": {
+			Scope: todo.ScopeListsRead,
+		},
+		"Granted": {
+			Scopes: todo.Scopes{todo.ScopeListsRead, todo.ScopeListsWrite},
+			Scope:  todo.ScopeListsWrite,
+			Want:   true,
+		},
+		"Not Granted": {
+			Scopes: todo.Scopes{todo.ScopeListsRead},
+			Scope:  todo.ScopeListsWrite,
+		},
+		"Admin": {
+			Scopes: todo.Scopes{todo.ScopeAdmin},
+			Scope:  todo.ScopeListsWrite,
+			Want:   true,
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.Want, tt.Scopes.Allow(tt.Scope), "Allowed")
+		})
+	}
+}
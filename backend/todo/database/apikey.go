@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// CreateAPIKey for a user, who is provisioned if they have not been seen before.
+func (r *UserRepository) CreateAPIKey(ctx context.Context, create todo.APIKeyCreate) (*todo.APIKey, error) {
+	query := `
+		-- Name: Create API Key
+		INSERT INTO api_keys (user_id, name, key_hash, scopes, expires)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id,
+		          name,
+		          scopes,
+		          created,
+		          expires,
+		          revoked
+	`
+
+	user, err := r.ProvisionUser(ctx, create.User)
+	if err != nil {
+		return nil, err
+	}
+
+	var expires *time.Time
+	if create.Expires != nil {
+		e := create.Expires.UTC()
+		expires = &e
+	}
+
+	key, err := queryRow(ctx, r.db, createdAPIKeyCols, query, user.ID, create.Name, create.Hash, pq.Array(create.Scopes), expires)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key %q for user %q: %w", create.Name, create.User, err)
+	}
+
+	key.User = *user
+
+	return key, nil
+}
+
+// APIKeys of every user, including those which have expired or been revoked.
+func (r *UserRepository) APIKeys(ctx context.Context) ([]todo.APIKey, error) {
+	query := `
+		-- Name: API Keys
+		SELECT k.id,
+		       u.id,
+		       u.name,
+		       k.name,
+		       k.scopes,
+		       k.created,
+		       k.expires,
+		       k.revoked
+		  FROM api_keys k
+		  JOIN users u ON u.id = k.user_id
+		 ORDER BY k.id
+	`
+
+	keys, err := queryRows(ctx, r.db, apiKeyCols, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// APIKey with the hash, from todo.HashAPIKey. Keys are found even once expired or revoked, so they must be checked
+// before use.
+func (r *UserRepository) APIKey(ctx context.Context, hash []byte) (*todo.APIKey, error) {
+	query := `
+		-- Name: API Key
+		SELECT k.id,
+		       u.id,
+		       u.name,
+		       k.name,
+		       k.scopes,
+		       k.created,
+		       k.expires,
+		       k.revoked
+		  FROM api_keys k
+		  JOIN users u ON u.id = k.user_id
+		 WHERE k.key_hash = $1
+	`
+
+	key, err := queryRow(ctx, r.db, apiKeyCols, query, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, todo.NotFoundError("api key does not exist")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// RevokeAPIKey so that it can no longer be used. Keys which have already been revoked are not found.
+func (r *UserRepository) RevokeAPIKey(ctx context.Context, keyID string) (*todo.APIKey, error) {
+	query := `
+		-- Name: Revoke API Key
+		WITH revoked AS (
+		     UPDATE api_keys
+		        SET revoked = now()
+		      WHERE id = $1
+		        AND revoked IS NULL
+		     RETURNING *
+		)
+		SELECT k.id,
+		       u.id,
+		       u.name,
+		       k.name,
+		       k.scopes,
+		       k.created,
+		       k.expires,
+		       k.revoked
+		  FROM revoked k
+		  JOIN users u ON u.id = k.user_id
+	`
+
+	key, err := queryRow(ctx, r.db, apiKeyCols, query, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, todo.NotFoundError(fmt.Sprintf("unrevoked api key with id %q does not exist", keyID))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke api key %q: %w", keyID, err)
+	}
+
+	return key, nil
+}
+
+func apiKeyCols(k *todo.APIKey) []any {
+	return []any{&k.ID, &k.User.ID, &k.User.Name, &k.Name, scopesScanner{s: &k.Scopes}, &k.Created, &k.Expires, &k.Revoked}
+}
+
+func createdAPIKeyCols(k *todo.APIKey) []any {
+	return []any{&k.ID, &k.Name, scopesScanner{s: &k.Scopes}, &k.Created, &k.Expires, &k.Revoked}
+}
+
+type scopesScanner struct {
+	s *todo.Scopes
+}
+
+func (s scopesScanner) Scan(src any) error {
+	var ss pq.StringArray
+	if err := ss.Scan(src); err != nil {
+		return fmt.Errorf("unable to scan scopes: %w", err)
+	}
+
+	scopes := make(todo.Scopes, len(ss))
+	for i, sc := range ss {
+		scopes[i] = todo.Scope(sc)
+	}
+
+	*s.s = scopes
+
+	return nil
+}
@@ -0,0 +1,370 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Create todo.APIKeyCreate
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Key   *todo.APIKey
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	created := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+	expires := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	hash := todo.HashAPIKey("todo_key")
+
+	create := todo.APIKeyCreate{User: "alice", Name: "backup", Scopes: todo.Scopes{todo.ScopeListsRead}, Expires: &expires, Hash: hash}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Provisioning failure": {
+			Args: args{Create: create},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Query failure": {
+			Args: args{Create: create},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "alice"))
+					mockCreateAPIKeyQuery(mock, "7", "backup", hash, `{"lists:read"}`, &expires).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Created": {
+			Args: args{Create: create},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "alice"))
+					mockCreateAPIKeyQuery(mock, "7", "backup", hash, `{"lists:read"}`, &expires).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "scopes", "created", "expires", "revoked"}).
+							AddRow("3", "backup", "{lists:read}", created, expires, nil),
+					)
+				},
+			},
+			Want: want{
+				Key: &todo.APIKey{
+					ID:      "3",
+					User:    todo.User{ID: "7", Name: "alice"},
+					Name:    "backup",
+					Scopes:  todo.Scopes{todo.ScopeListsRead},
+					Created: created,
+					Expires: &expires,
+				},
+			},
+		},
+		"Created without Expiry": {
+			Args: args{Create: todo.APIKeyCreate{User: "alice", Name: "sync", Scopes: todo.Scopes{todo.ScopeListsRead, todo.ScopeListsWrite}, Hash: hash}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "alice"))
+					mockCreateAPIKeyQuery(mock, "7", "sync", hash, `{"lists:read","lists:write"}`, nil).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "scopes", "created", "expires", "revoked"}).
+							AddRow("4", "sync", "{lists:read,lists:write}", created, nil, nil),
+					)
+				},
+			},
+			Want: want{
+				Key: &todo.APIKey{
+					ID:      "4",
+					User:    todo.User{ID: "7", Name: "alice"},
+					Name:    "sync",
+					Scopes:  todo.Scopes{todo.ScopeListsRead, todo.ScopeListsWrite},
+					Created: created,
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewUserRepository(db)
+
+			tt.Fields.MockExpectations(mock)
+
+			key, err := repo.CreateAPIKey(context.Background(), tt.Args.Create)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Create error")
+				return
+			}
+
+			require.NoError(t, err, "Create error")
+			assert.Equal(t, tt.Want.Key, key, "API Key")
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Key   *todo.APIKey
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	created := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+	revoked := time.Date(2023, time.July, 4, 9, 0, 0, 0, time.UTC)
+	hash := todo.HashAPIKey("todo_key")
+
+	testTable := map[string]struct {
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockAPIKeyQuery(mock, hash).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Not Found": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockAPIKeyQuery(mock, hash).WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+				},
+			},
+			Want: want{Error: todo.NotFoundError("api key does not exist")},
+		},
+		"Revoked": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockAPIKeyQuery(mock, hash).WillReturnRows(
+						sqlmock.NewRows(apiKeyColumns).AddRow("3", "7", "alice", "backup", "{admin}", created, nil, revoked),
+					)
+				},
+			},
+			Want: want{
+				Key: &todo.APIKey{
+					ID:      "3",
+					User:    todo.User{ID: "7", Name: "alice"},
+					Name:    "backup",
+					Scopes:  todo.Scopes{todo.ScopeAdmin},
+					Created: created,
+					Revoked: &revoked,
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewUserRepository(db)
+
+			tt.Fields.MockExpectations(mock)
+
+			key, err := repo.APIKey(context.Background(), hash)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "API Key error")
+				return
+			}
+
+			require.NoError(t, err, "API Key error")
+			assert.Equal(t, tt.Want.Key, key, "API Key")
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Key   *todo.APIKey
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	created := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+	revoked := time.Date(2023, time.July, 4, 9, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockRevokeAPIKeyQuery(mock, "3").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Not Found or already Revoked": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockRevokeAPIKeyQuery(mock, "3").WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`unrevoked api key with id "3" does not exist`)},
+		},
+		"Revoked": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockRevokeAPIKeyQuery(mock, "3").WillReturnRows(
+						sqlmock.NewRows(apiKeyColumns).AddRow("3", "7", "alice", "backup", "{lists:read}", created, nil, revoked),
+					)
+				},
+			},
+			Want: want{
+				Key: &todo.APIKey{
+					ID:      "3",
+					User:    todo.User{ID: "7", Name: "alice"},
+					Name:    "backup",
+					Scopes:  todo.Scopes{todo.ScopeListsRead},
+					Created: created,
+					Revoked: &revoked,
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewUserRepository(db)
+
+			tt.Fields.MockExpectations(mock)
+
+			key, err := repo.RevokeAPIKey(context.Background(), "3")
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Revoke error")
+				return
+			}
+
+			require.NoError(t, err, "Revoke error")
+			assert.Equal(t, tt.Want.Key, key, "API Key")
+		})
+	}
+}
+
+var apiKeyColumns = []string{"id", "user_id", "user_name", "name", "scopes", "created", "expires", "revoked"}
+
+func mockCreateAPIKeyQuery(mock sqlmock.Sqlmock, userID, name string, hash []byte, scopes string, expires *time.Time) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Create API Key
+		INSERT INTO api_keys (user_id, name, key_hash, scopes, expires)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id,
+		          name,
+		          scopes,
+		          created,
+		          expires,
+		          revoked
+	`
+
+	var e any
+	if expires != nil {
+		e = *expires
+	}
+
+	return mock.ExpectQuery(q).WithArgs(userID, name, hash, scopes, e)
+}
+
+func mockAPIKeyQuery(mock sqlmock.Sqlmock, hash []byte) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: API Key
+		SELECT k.id,
+		       u.id,
+		       u.name,
+		       k.name,
+		       k.scopes,
+		       k.created,
+		       k.expires,
+		       k.revoked
+		  FROM api_keys k
+		  JOIN users u ON u.id = k.user_id
+		 WHERE k.key_hash = $1
+	`
+
+	return mock.ExpectQuery(q).WithArgs(hash)
+}
+
+func mockRevokeAPIKeyQuery(mock sqlmock.Sqlmock, keyID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Revoke API Key
+		WITH revoked AS (
+		     UPDATE api_keys
+		        SET revoked = now()
+		      WHERE id = $1
+		        AND revoked IS NULL
+		     RETURNING *
+		)
+		SELECT k.id,
+		       u.id,
+		       u.name,
+		       k.name,
+		       k.scopes,
+		       k.created,
+		       k.expires,
+		       k.revoked
+		  FROM revoked k
+		  JOIN users u ON u.id = k.user_id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(keyID)
+}
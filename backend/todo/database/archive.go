@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// ArchiveList so that it is hidden, without deleting it. Archiving a list which is already archived keeps when it was
+// originally archived.
+func (r *ListRepository) ArchiveList(ctx context.Context, listID string) (*todo.List, error) {
+	query := `
+		-- Name: Archive TODO List
+		UPDATE lists
+		   SET archived = COALESCE(archived, now())
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return r.updateListState(ctx, listID, query, "archive")
+}
+
+// RestoreList which was archived or deleted, so that it is no longer hidden.
+func (r *ListRepository) RestoreList(ctx context.Context, listID string) (*todo.List, error) {
+	query := `
+		-- Name: Restore TODO List
+		UPDATE lists
+		   SET archived = NULL,
+		       deleted = NULL
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return r.updateListState(ctx, listID, query, "restore")
+}
+
+func (r *ListRepository) updateListState(ctx context.Context, listID, query, op string) (*todo.List, error) {
+	var list *todo.List
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleOwner, hiddenLists); err != nil {
+			return err
+		}
+
+		var err error
+		list, err = queryRow(ctx, tx, listCols, query, listID)
+
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s todo list %q: %w", op, listID, err)
+	}
+
+	return list, nil
+}
+
+// ArchiveItem so that it is hidden, along with its subtasks, without deleting them. Items which are already archived
+// keep when they were originally archived.
+func (r *ListRepository) ArchiveItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	query := `
+		-- Name: Archive TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items
+		   SET archived = COALESCE(archived, now())
+		 WHERE id IN (SELECT id FROM subtree)
+	`
+
+	return r.updateItemState(ctx, listID, itemID, query, "archive")
+}
+
+// RestoreItem which was archived or deleted, so that it is no longer hidden. Subtasks are restored along with it when
+// they were archived or deleted at the same time as the item was, while those which were hidden separately remain so.
+func (r *ListRepository) RestoreItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	query := `
+		-- Name: Restore TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id,
+		            archived,
+		            deleted
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id,
+		            s.archived,
+		            s.deleted
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items i
+		   SET archived = CASE WHEN i.archived = s.archived THEN NULL ELSE i.archived END,
+		       deleted = CASE WHEN i.deleted = s.deleted THEN NULL ELSE i.deleted END
+		  FROM subtree s
+		 WHERE i.id = s.id
+	`
+
+	return r.updateItemState(ctx, listID, itemID, query, "restore")
+}
+
+// updateItemState of an item and its subtasks with the statement, returning the item as it is afterwards.
+func (r *ListRepository) updateItemState(ctx context.Context, listID, itemID, query, op string) (*todo.Item, error) {
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, itemID, listID)
+		if err != nil {
+			return fmt.Errorf("failed to %s item %q of todo list %q: %w", op, itemID, listID, err)
+		}
+
+		if err := expectAffected(res, itemNotFound(listID, itemID)); err != nil {
+			return err
+		}
+
+		// The item is returned as it is now, whether or not it is archived or deleted
+		item, err = r.item(ctx, tx, listID, itemID, todo.Include{Archived: true, Deleted: true})
+
+		return err
+	})
+
+	return item, err
+}
+
+// PurgeTrash by permanently removing lists and items which were deleted longer ago than the retention period, along
+// with all items of such lists. The number of lists and items removed are returned.
+func (r *ListRepository) PurgeTrash(ctx context.Context, retention time.Duration) (lists, items int64, err error) {
+	itemsQuery := `
+		-- Name: Purge TODO List Items
+		DELETE FROM items
+		 WHERE deleted < now() - make_interval(secs => $1)
+		    OR list_id IN (SELECT id FROM lists WHERE deleted < now() - make_interval(secs => $1))
+	`
+
+	listsQuery := `
+		-- Name: Purge TODO Lists
+		DELETE FROM lists
+		 WHERE deleted < now() - make_interval(secs => $1)
+	`
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Items must be removed first, as they reference their list
+		res, err := tx.ExecContext(ctx, itemsQuery, retention.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to purge deleted todo list items: %w", err)
+		}
+
+		if items, err = res.RowsAffected(); err != nil {
+			return fmt.Errorf("unable to determine purged todo list items: %w", err)
+		}
+
+		res, err = tx.ExecContext(ctx, listsQuery, retention.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to purge deleted todo lists: %w", err)
+		}
+
+		if lists, err = res.RowsAffected(); err != nil {
+			return fmt.Errorf("unable to determine purged todo lists: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lists, items, nil
+}
@@ -0,0 +1,583 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestArchiveList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		List  *todo.List
+	}
+
+	queryErr := errors.New("failed to execute query")
+	archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockArchiveListQuery(mock, "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Not found": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockArchiveListQuery(mock, "1").WillReturnRows(mockListRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Archived": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockArchiveListQuery(mock, "2").WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Holiday", Archived: &archived}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "2", Description: "Holiday", Archived: &archived}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			list, err := repo.ArchiveList(userContext(), tt.Args.ListID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Archive error")
+				return
+			}
+
+			require.NoError(t, err, "Archive error")
+			assert.Equal(t, tt.Want.List, list, "List")
+		})
+	}
+}
+
+func TestRestoreList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		List  *todo.List
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRestoreListQuery(mock, "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Not found": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRestoreListQuery(mock, "1").WillReturnRows(mockListRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Restored": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockRestoreListQuery(mock, "2").WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Holiday"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "2", Description: "Holiday"}},
+		},
+		"Restored from Trash": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockHiddenList(mock, "2", false, true)
+					mockRestoreListQuery(mock, "2").WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Holiday"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "2", Description: "Holiday"}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			list, err := repo.RestoreList(userContext(), tt.Args.ListID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Restore error")
+				return
+			}
+
+			require.NoError(t, err, "Restore error")
+			assert.Equal(t, tt.Want.List, list, "List")
+		})
+	}
+}
+
+func TestArchiveItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	execErr := errors.New("failed to execute statement")
+	archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Archive failure": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockArchiveItemQuery(mock, "2", "1").WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Item does not exist": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockArchiveItemQuery(mock, "2", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Archived with subtasks": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockArchiveItemQuery(mock, "2", "1").WillReturnResult(sqlmock.NewResult(0, 3))
+					mockItemQuery(mock, "2", "1", todo.Include{Archived: true, Deleted: true}).WillReturnRows(mockItemRows(todo.Item{ID: "2", Description: "Washing", Archived: &archived}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "2", Description: "Washing", Archived: &archived}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.ArchiveItem(userContext(), tt.Args.ListID, tt.Args.ItemID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Archive error")
+				return
+			}
+
+			require.NoError(t, err, "Archive error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestRestoreItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	execErr := errors.New("failed to execute statement")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Restore failure": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRestoreItemQuery(mock, "2", "1").WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Item does not exist": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRestoreItemQuery(mock, "2", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Restored": {
+			Args: args{ListID: "1", ItemID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRestoreItemQuery(mock, "2", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockItemQuery(mock, "2", "1", todo.Include{Archived: true, Deleted: true}).WillReturnRows(mockItemRows(todo.Item{ID: "2", Description: "Washing"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "2", Description: "Washing"}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.RestoreItem(userContext(), tt.Args.ListID, tt.Args.ItemID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Restore error")
+				return
+			}
+
+			require.NoError(t, err, "Restore error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Retention time.Duration
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Items int64
+		Lists int64
+	}
+
+	execErr := errors.New("failed to execute statement")
+	retention := 30 * 24 * time.Hour
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Items failure": {
+			Args: args{Retention: retention},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockPurgeItemsQuery(mock, retention).WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Lists failure": {
+			Args: args{Retention: retention},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockPurgeItemsQuery(mock, retention).WillReturnResult(sqlmock.NewResult(0, 4))
+					mockPurgeListsQuery(mock, retention).WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Nothing to purge": {
+			Args: args{Retention: retention},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockPurgeItemsQuery(mock, retention).WillReturnResult(sqlmock.NewResult(0, 0))
+					mockPurgeListsQuery(mock, retention).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectCommit()
+				},
+			},
+		},
+		"Purged": {
+			Args: args{Retention: retention},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockPurgeItemsQuery(mock, retention).WillReturnResult(sqlmock.NewResult(0, 4))
+					mockPurgeListsQuery(mock, retention).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Items: 4, Lists: 1},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			lists, items, err := repo.PurgeTrash(context.Background(), tt.Args.Retention)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Purge error")
+				return
+			}
+
+			require.NoError(t, err, "Purge error")
+			assert.Equal(t, tt.Want.Lists, lists, "Lists")
+			assert.Equal(t, tt.Want.Items, items, "Items")
+		})
+	}
+}
+
+func mockArchiveListQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Archive TODO List
+		UPDATE lists
+		   SET archived = COALESCE(archived, now())
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
+
+func mockRestoreListQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Restore TODO List
+		UPDATE lists
+		   SET archived = NULL,
+		       deleted = NULL
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
+
+func mockArchiveItemQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Archive TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items
+		   SET archived = COALESCE(archived, now())
+		 WHERE id IN (SELECT id FROM subtree)
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, listID)
+}
+
+func mockRestoreItemQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Restore TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id,
+		            archived,
+		            deleted
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id,
+		            s.archived,
+		            s.deleted
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items i
+		   SET archived = CASE WHEN i.archived = s.archived THEN NULL ELSE i.archived END,
+		       deleted = CASE WHEN i.deleted = s.deleted THEN NULL ELSE i.deleted END
+		  FROM subtree s
+		 WHERE i.id = s.id
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, listID)
+}
+
+func mockPurgeItemsQuery(mock sqlmock.Sqlmock, retention time.Duration) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Purge TODO List Items
+		DELETE FROM items
+		 WHERE deleted < now() - make_interval(secs => $1)
+		    OR list_id IN (SELECT id FROM lists WHERE deleted < now() - make_interval(secs => $1))
+	`
+
+	return mock.ExpectExec(q).WithArgs(retention.Seconds())
+}
+
+func mockPurgeListsQuery(mock sqlmock.Sqlmock, retention time.Duration) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Purge TODO Lists
+		DELETE FROM lists
+		 WHERE deleted < now() - make_interval(secs => $1)
+	`
+
+	return mock.ExpectExec(q).WithArgs(retention.Seconds())
+}
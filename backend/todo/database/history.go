@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// History of changes to a TODO list and all of its items, or to only one of its items, most recent first. The key of
+// the last change is returned when there are further pages. Changes are recorded against the list which the item was
+// in at the time.
+func (r *ListRepository) History(ctx context.Context, listID string, filter todo.HistoryFilter, page todo.Page) ([]todo.Change, string, error) {
+	query := `
+		-- Name: TODO List History
+		SELECT id,
+		       list_id,
+		       item_id,
+		       actor,
+		       changed,
+		       operation,
+		       fields
+		  FROM history
+		 WHERE list_id = $1
+		   AND ($2::int IS NULL OR item_id = $2)
+		   AND ($3::timestamp IS NULL OR changed >= $3)
+		   AND ($4::timestamp IS NULL OR changed < $4)
+		   AND ($5::bigint IS NULL OR id < $5)
+		 ORDER BY id DESC
+		 LIMIT $6
+	`
+
+	if err := r.checkAccess(ctx, r.db, listID, todo.RoleViewer, hiddenLists); err != nil {
+		return nil, "", err
+	}
+
+	after, err := pageKeyID(page.After)
+	if err != nil {
+		return nil, "", err
+	}
+
+	changes, err := queryRows(ctx, r.db, changeCols, query, listID, nullIfEmpty(filter.ItemID), utcTime(filter.Since), utcTime(filter.Until), after, page.Limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query history of todo list %q: %w", listID, err)
+	}
+
+	changes, next := nextPage(changes, page.Limit, func(c todo.Change) string { return c.ID })
+
+	return changes, next, nil
+}
+
+func changeCols(c *todo.Change) []any {
+	return []any{&c.ID, &c.ListID, &c.ItemID, &c.Actor, &c.Time, &c.Operation, fieldChangesScanner{f: &c.Fields}}
+}
+
+// historyFields of lists and items, by the column which they are recorded as, with the name of the field in the API.
+// Columns which are not listed are recorded with the same name.
+var historyFields = map[string]string{
+	"auto_complete": "autoComplete",
+	"due_horizon":   "dueHorizon",
+	"list_id":       "listId",
+	"owner_id":      "ownerId",
+	"parent_id":     "parentId",
+}
+
+// fieldChangesScanner scans the fields of a change, recorded as JSON of the old and new values of each column, into
+// field changes with the values as they are represented by the API.
+type fieldChangesScanner struct {
+	f *map[string]todo.FieldChange
+}
+
+func (s fieldChangesScanner) Scan(src any) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for change fields", src)
+	}
+
+	var cols map[string]struct {
+		Old json.RawMessage `json:"old"`
+		New json.RawMessage `json:"new"`
+	}
+
+	if err := json.Unmarshal(b, &cols); err != nil {
+		return fmt.Errorf("unable to parse change fields: %w", err)
+	}
+
+	fields := make(map[string]todo.FieldChange, len(cols))
+
+	for col, change := range cols {
+		var fc todo.FieldChange
+		var err error
+
+		if fc.Old, err = historyValue(col, change.Old); err != nil {
+			return err
+		}
+
+		if fc.New, err = historyValue(col, change.New); err != nil {
+			return err
+		}
+
+		name, ok := historyFields[col]
+		if !ok {
+			name = col
+		}
+
+		fields[name] = fc
+	}
+
+	*s.f = fields
+
+	return nil
+}
+
+// historyValue of a column as it is represented by the API, such as priorities by name rather than number. Nil when
+// the column had no value.
+func historyValue(col string, raw json.RawMessage) (any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	switch col {
+	case "due", "completed", "archived", "deleted":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("unable to parse %s of change: %w", col, err)
+		}
+
+		// Timestamps are recorded without a zone, and are in UTC
+		t, err := time.Parse("2006-01-02T15:04:05.999999", s)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s of change: %w", col, err)
+		}
+
+		return t, nil
+	case "priority":
+		var p todo.Priority
+		if err := json.Unmarshal(raw, (*int)(&p)); err != nil {
+			return nil, fmt.Errorf("unable to parse %s of change: %w", col, err)
+		}
+
+		return p, nil
+	case "due_horizon":
+		var secs float64
+		if err := json.Unmarshal(raw, &secs); err != nil {
+			return nil, fmt.Errorf("unable to parse %s of change: %w", col, err)
+		}
+
+		return todo.Duration(time.Duration(secs * float64(time.Second))), nil
+	case "list_id", "owner_id", "parent_id":
+		var id int64
+		if err := json.Unmarshal(raw, &id); err != nil {
+			return nil, fmt.Errorf("unable to parse %s of change: %w", col, err)
+		}
+
+		return strconv.FormatInt(id, 10), nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,336 @@
+package database_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+		Filter todo.HistoryFilter
+		Page   todo.Page
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Changes []todo.Change
+		Error   error
+		ErrorAs any
+		Next    string
+	}
+
+	queryErr := errors.New("failed to execute query")
+	changed := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+	since := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2023, time.July, 4, 17, 30, 0, 0, time.UTC)
+	horizon := todo.Duration(48 * time.Hour)
+	sinceOffset := since.In(time.FixedZone("AEST", 10*60*60))
+	untilOffset := until.In(time.FixedZone("AEST", 10*60*60))
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+					mockHistoryQuery(mock, "1", nil, nil, nil, nil, 101).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Tampered Page Key": {
+			Args: args{ListID: "1", Page: todo.Page{After: "abc", Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError("page key is not valid")},
+		},
+		"List owned by another User": {
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockNoListAccess(mock, "1")
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Invalid fields": {
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+					rows := mockHistoryRows().AddRow("5", "1", nil, nil, changed, "update", []byte(`{"priority": {"old": "high", "new": 1}}`))
+					mockHistoryQuery(mock, "1", nil, nil, nil, nil, 101).WillReturnRows(rows)
+				},
+			},
+			Want: want{ErrorAs: new(*json.UnmarshalTypeError)},
+		},
+		"No Changes": {
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+					mockHistoryQuery(mock, "1", nil, nil, nil, nil, 101).WillReturnRows(mockHistoryRows())
+				},
+			},
+		},
+		"List Changes": {
+			Args: args{ListID: "2", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					rows := mockHistoryRows().
+						AddRow("7", "2", "4", "alice", changed, "update", []byte(`{"due": {"old": null, "new": "2023-07-04T17:30:00"}, "priority": {"old": 0, "new": 1}, "parent_id": {"old": 3, "new": null}}`)).
+						AddRow("6", "2", "4", "alice", changed, "tag", []byte(`{"tags": {"old": null, "new": "home"}}`)).
+						AddRow("5", "2", nil, nil, changed, "update", []byte(`{"due_horizon": {"old": null, "new": 172800}, "description": {"old": "Chores", "new": "Holiday"}}`))
+					mockHistoryQuery(mock, "2", nil, nil, nil, nil, 101).WillReturnRows(rows)
+				},
+			},
+			Want: want{
+				Changes: []todo.Change{
+					{
+						ID: "7", ListID: "2", ItemID: ptr("4"), Actor: ptr("alice"), Time: changed, Operation: todo.ChangeUpdate,
+						Fields: map[string]todo.FieldChange{
+							"due":      {New: due},
+							"priority": {Old: todo.PriorityNormal, New: todo.PriorityHigh},
+							"parentId": {Old: "3"},
+						},
+					},
+					{
+						ID: "6", ListID: "2", ItemID: ptr("4"), Actor: ptr("alice"), Time: changed, Operation: todo.ChangeTag,
+						Fields: map[string]todo.FieldChange{
+							"tags": {New: json.RawMessage(`"home"`)},
+						},
+					},
+					{
+						ID: "5", ListID: "2", Time: changed, Operation: todo.ChangeUpdate,
+						Fields: map[string]todo.FieldChange{
+							"dueHorizon":  {New: horizon},
+							"description": {Old: json.RawMessage(`"Chores"`), New: json.RawMessage(`"Holiday"`)},
+						},
+					},
+				},
+			},
+		},
+		"Filtered with offsets": {
+			Args: args{
+				ListID: "2",
+				Filter: todo.HistoryFilter{Since: &sinceOffset, Until: &untilOffset},
+				Page:   todo.Page{Limit: 100},
+			},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockHistoryQuery(mock, "2", nil, since, until, nil, 101).WillReturnRows(mockHistoryRows())
+				},
+			},
+		},
+		"Item Changes - Filtered, with more pages": {
+			Args: args{
+				ListID: "2",
+				Filter: todo.HistoryFilter{ItemID: "4", Since: &since, Until: &until},
+				Page:   todo.Page{After: "9", Limit: 1},
+			},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					rows := mockHistoryRows().
+						AddRow("8", "2", "4", nil, changed, "create", []byte(`{"description": {"old": null, "new": "Pack"}}`)).
+						AddRow("7", "2", "4", nil, changed, "untag", []byte(`{"tags": {"old": "home", "new": null}}`))
+					mockHistoryQuery(mock, "2", "4", since, until, "9", 2).WillReturnRows(rows)
+				},
+			},
+			Want: want{
+				Changes: []todo.Change{
+					{
+						ID: "8", ListID: "2", ItemID: ptr("4"), Time: changed, Operation: todo.ChangeCreate,
+						Fields: map[string]todo.FieldChange{
+							"description": {New: json.RawMessage(`"Pack"`)},
+						},
+					},
+				},
+				Next: "8",
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			changes, next, err := repo.History(userContext(), tt.Args.ListID, tt.Args.Filter, tt.Args.Page)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "History error")
+				return
+			}
+
+			if tt.Want.ErrorAs != nil {
+				assert.ErrorAs(t, err, tt.Want.ErrorAs, "History error")
+				return
+			}
+
+			require.NoError(t, err, "History error")
+			assert.Equal(t, tt.Want.Changes, changes, "Changes")
+			assert.Equal(t, tt.Want.Next, next, "Next")
+		})
+	}
+}
+
+func TestHistoryActor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Actor string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Set Actor failure": {
+			Args: args{Actor: "alice"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockSetActorQuery(mock, "alice").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Actor": {
+			Args: args{Actor: "alice"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockSetActorQuery(mock, "alice").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockListAccess(mock, "1")
+					mockDeleteListQuery(mock, "1").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+		"No Actor": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteListQuery(mock, "1").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			ctx := userContext()
+			if tt.Args.Actor != "" {
+				ctx = todo.WithActor(ctx, tt.Args.Actor)
+			}
+
+			err := repo.DeleteList(ctx, "1")
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+			assert.ErrorIs(t, err, tt.Want.Error, "Delete error")
+		})
+	}
+}
+
+func mockHistoryRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "list_id", "item_id", "actor", "changed", "operation", "fields"})
+}
+
+func mockHistoryQuery(mock sqlmock.Sqlmock, listID string, itemID, since, until, after driver.Value, limit int) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List History
+		SELECT id,
+		       list_id,
+		       item_id,
+		       actor,
+		       changed,
+		       operation,
+		       fields
+		  FROM history
+		 WHERE list_id = $1
+		   AND ($2::int IS NULL OR item_id = $2)
+		   AND ($3::timestamp IS NULL OR changed >= $3)
+		   AND ($4::timestamp IS NULL OR changed < $4)
+		   AND ($5::bigint IS NULL OR id < $5)
+		 ORDER BY id DESC
+		 LIMIT $6
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID, itemID, since, until, after, limit)
+}
+
+func mockPauseHistoryQuery(mock sqlmock.Sqlmock, paused string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Pause History
+		SELECT set_config('todo.history_paused', $1, true)
+	`
+
+	return mock.ExpectExec(q).WithArgs(paused)
+}
+
+func mockSetActorQuery(mock sqlmock.Sqlmock, actor string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Set History Actor
+		SELECT set_config('todo.actor', $1, true)
+	`
+
+	return mock.ExpectExec(q).WithArgs(actor)
+}
@@ -0,0 +1,687 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// CreateItem on a TODO list, positioned after all of its other items. When the item has a parent it is created as a
+// subtask, which must not exceed the maximum depth of subtasks.
+func (r *ListRepository) CreateItem(ctx context.Context, listID string, item todo.Item) (*todo.Item, error) {
+	if err := r.checkAccess(ctx, r.db, listID, todo.RoleEditor, todo.Include{}); err != nil {
+		return nil, err
+	}
+
+	if item.ParentID != nil {
+		if err := r.checkParent(ctx, listID, *item.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		-- Name: Create TODO List Item
+		INSERT INTO items (list_id, description, due, priority, recurrence, parent_id, auto_complete, position)
+		SELECT id,
+		       $2,
+		       $3,
+		       $4,
+		       $5,
+		       $6,
+		       $7,
+		       (SELECT COALESCE(max(position), 0) + $8 FROM items WHERE list_id = lists.id)
+		  FROM lists
+		 WHERE id = $1
+		RETURNING ` + itemColumns + `
+	`
+
+	var created *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		created, err = queryRow(ctx, tx, itemCols, query, listID, item.Description, utcTime(item.Due), item.Priority, recurrenceRule(item.Recurrence), item.ParentID, item.AutoComplete, positionGap)
+
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item for todo list %q: %w", listID, err)
+	}
+
+	return created, nil
+}
+
+func (r *ListRepository) UpdateItem(ctx context.Context, listID, itemID string, update todo.ItemUpdate) (*todo.Item, error) {
+	query := `
+		-- Name: Update TODO List Item
+		UPDATE items
+		   SET description = COALESCE($3, description),
+		       due = CASE WHEN $4::boolean THEN $5 ELSE due END,
+		       priority = COALESCE($6, priority),
+		       recurrence = CASE WHEN $7::boolean THEN $8 ELSE recurrence END,
+		       auto_complete = COALESCE($9, auto_complete)
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING ` + itemColumns + `
+	`
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		var err error
+		item, err = queryRow(ctx, tx, itemCols, query, itemID, listID, update.Description, update.SetDue, utcTime(update.Due), update.Priority, update.SetRecurrence, recurrenceRule(update.Recurrence), update.AutoComplete)
+
+		return err
+	})
+
+	return itemResult(item, err, listID, itemID, "update")
+}
+
+// CompleteItem as of now. When the item recurs, the next occurrence is created along with it, having the same
+// description, priority, recurrence and tags, and positioned after all other items. Parents which auto-complete are
+// completed once the last of their subtasks is, which in turn may complete their own parents. Completing an item which
+// is already complete has no effect, while archived and deleted items are not found.
+func (r *ListRepository) CompleteItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	complete := `
+		-- Name: Complete TODO List Item
+		UPDATE items
+		   SET completed = now()
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND completed IS NULL
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING ` + itemColumns + `
+	`
+
+	createNext := `
+		-- Name: Create Next TODO List Item Occurrence
+		WITH next AS (
+		     INSERT INTO items (list_id, description, due, priority, recurrence, parent_id, auto_complete, position)
+		     SELECT list_id,
+		            description,
+		            $2,
+		            priority,
+		            recurrence,
+		            parent_id,
+		            auto_complete,
+		            (SELECT max(p.position) + $3 FROM items p WHERE p.list_id = items.list_id)
+		       FROM items
+		      WHERE id = $1
+		     RETURNING id
+		)
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT next.id,
+		       it.tag_id
+		  FROM next,
+		       item_tags it
+		 WHERE it.item_id = $1
+	`
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		completed, err := queryRow(ctx, tx, itemCols, complete, itemID, listID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Either the item does not exist, or it is already complete and remains as it was
+			item, err = r.item(ctx, tx, listID, itemID, todo.Include{})
+			return err
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to complete item %q of todo list %q: %w", itemID, listID, err)
+		}
+
+		item = completed
+
+		if item.Recurrence != nil {
+			// No occurrence is created when the recurrence has no further occurrences
+			if due, ok := item.Recurrence.Next(item.Due, *item.Completed, r.loc); ok {
+				if _, err := tx.ExecContext(ctx, createNext, item.ID, due.UTC(), positionGap); err != nil {
+					return fmt.Errorf("failed to create next occurrence of item %q of todo list %q: %w", itemID, listID, err)
+				}
+			}
+		}
+
+		if item.ParentID == nil {
+			return nil
+		}
+
+		return r.autoCompleteParents(ctx, tx, item)
+	})
+
+	return item, err
+}
+
+// autoCompleteParents of the subtask, and their parents in turn, which auto-complete and have no open subtasks left.
+func (r *ListRepository) autoCompleteParents(ctx context.Context, tx *sql.Tx, subtask *todo.Item) error {
+	query := `
+		-- Name: Auto-Complete TODO List Item Parent
+		UPDATE items p
+		   SET completed = now()
+		  FROM items c
+		 WHERE c.id = $1
+		   AND p.id = c.parent_id
+		   AND p.auto_complete
+		   AND p.completed IS NULL
+		   AND NOT EXISTS (SELECT 1 FROM items s WHERE s.parent_id = p.id AND s.completed IS NULL AND s.deleted IS NULL)
+		RETURNING p.id,
+		          p.parent_id
+	`
+
+	cols := func(i *todo.Item) []any {
+		return []any{&i.ID, &i.ParentID}
+	}
+
+	for child := subtask; child.ParentID != nil; {
+		parent, err := queryRow(ctx, tx, cols, query, child.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// The parent does not auto-complete, or still has subtasks to be done
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to auto-complete parent of item %q: %w", child.ID, err)
+		}
+
+		child = parent
+	}
+
+	return nil
+}
+
+// ReopenItem which has been completed. Parents which were auto-completed are reopened along with it, since they once
+// again have a subtask to be done.
+func (r *ListRepository) ReopenItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	reopen := `
+		-- Name: Reopen TODO List Item
+		UPDATE items
+		   SET completed = NULL
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING ` + itemColumns + `
+	`
+
+	reopenParents := `
+		-- Name: Reopen Auto-Completed TODO List Item Parents
+		WITH RECURSIVE ancestors AS (
+		     SELECT id,
+		            parent_id
+		       FROM items
+		      WHERE id = $1
+		        AND auto_complete
+		      UNION ALL
+		     SELECT p.id,
+		            p.parent_id
+		       FROM items p
+		       JOIN ancestors a ON a.parent_id = p.id
+		      WHERE p.auto_complete
+		)
+		UPDATE items
+		   SET completed = NULL
+		 WHERE id IN (SELECT id FROM ancestors)
+	`
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		reopened, err := queryRow(ctx, tx, itemCols, reopen, itemID, listID)
+		if item, err = itemResult(reopened, err, listID, itemID, "reopen"); err != nil {
+			return err
+		}
+
+		if item.ParentID == nil {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, reopenParents, *item.ParentID); err != nil {
+			return fmt.Errorf("failed to reopen auto-completed parents of item %q of todo list %q: %w", itemID, listID, err)
+		}
+
+		return nil
+	})
+
+	return item, err
+}
+
+// MoveItem to a new position, immediately before or after another item of the same list. Positions are ranks with
+// gaps between them, so that moving an item usually only changes its own position. Once there is no gap left where
+// the item is being moved to, the positions of all items of the list are renumbered to restore the gaps. Renumbering
+// is not recorded in the history, only the move of the item itself.
+func (r *ListRepository) MoveItem(ctx context.Context, listID, itemID string, move todo.ItemMove) (*todo.Item, error) {
+	anchorID := move.Before
+	if anchorID == "" {
+		anchorID = move.After
+	}
+
+	if anchorID == itemID {
+		return nil, todo.InvalidArgumentError("an item cannot be moved relative to itself")
+	}
+
+	bounds := `
+		-- Name: TODO List Item Neighbour Positions
+		SELECT a.position,
+		       (SELECT max(p.position) FROM items p WHERE p.list_id = a.list_id AND p.id <> $3 AND p.position < a.position),
+		       (SELECT min(n.position) FROM items n WHERE n.list_id = a.list_id AND n.id <> $3 AND n.position > a.position)
+		  FROM items a
+		 WHERE a.id = $1
+		   AND a.list_id = $2
+		   AND a.archived IS NULL
+		   AND a.deleted IS NULL
+	`
+
+	renumber := `
+		-- Name: Renumber TODO List Item Positions
+		UPDATE items i
+		   SET position = r.rank * $2
+		  FROM (SELECT id, row_number() OVER (ORDER BY position, id) AS rank FROM items WHERE list_id = $1) r
+		 WHERE i.id = r.id
+	`
+
+	query := `
+		-- Name: Move TODO List Item
+		UPDATE items
+		   SET position = $3
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING ` + itemColumns + `
+	`
+
+	type neighbours struct {
+		Anchor int64
+		Prev   *int64
+		Next   *int64
+	}
+
+	cols := func(n *neighbours) []any {
+		return []any{&n.Anchor, &n.Prev, &n.Next}
+	}
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		var position int64
+
+		for renumbered := false; ; renumbered = true {
+			n, err := queryRow(ctx, tx, cols, bounds, anchorID, listID, itemID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return itemNotFound(listID, anchorID)
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to query positions around item %q of todo list %q: %w", anchorID, listID, err)
+			}
+
+			lower, upper := n.Prev, &n.Anchor
+			if move.Before == "" {
+				lower, upper = &n.Anchor, n.Next
+			}
+
+			var ok bool
+			if position, ok = between(lower, upper); ok {
+				break
+			}
+
+			if renumbered {
+				return fmt.Errorf("no position available between items of todo list %q after renumbering", listID)
+			}
+
+			err = withoutHistory(ctx, tx, func() error {
+				if _, err := tx.ExecContext(ctx, renumber, listID, positionGap); err != nil {
+					return fmt.Errorf("failed to renumber item positions of todo list %q: %w", listID, err)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		moved, err := queryRow(ctx, tx, itemCols, query, itemID, listID, position)
+		item, err = itemResult(moved, err, listID, itemID, "move")
+
+		return err
+	})
+
+	return item, err
+}
+
+// positionGap between the positions of items, leaving room for items to be moved between them.
+const positionGap = 1024
+
+// between the lower and upper positions, where one may be nil when there is no item on that side. Not ok when there is
+// no gap left between them.
+func between(lower, upper *int64) (int64, bool) {
+	switch {
+	case lower == nil:
+		return *upper - positionGap, true
+	case upper == nil:
+		return *lower + positionGap, true
+	case *upper-*lower < 2:
+		return 0, false
+	default:
+		return *lower + (*upper-*lower)/2, true
+	}
+}
+
+// DeleteItem by moving it to the trash, along with its subtasks, until it is restored or purged. Items which are already
+// in the trash keep when they were originally deleted.
+func (r *ListRepository) DeleteItem(ctx context.Context, listID, itemID string) error {
+	query := `
+		-- Name: Delete TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items
+		   SET deleted = COALESCE(deleted, now())
+		 WHERE id IN (SELECT id FROM subtree)
+	`
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, itemID, listID)
+		if err != nil {
+			return fmt.Errorf("failed to delete item %q of todo list %q: %w", itemID, listID, err)
+		}
+
+		return expectAffected(res, itemNotFound(listID, itemID))
+	})
+}
+
+// itemOrder clauses for each of the supported sorts. The ID is always included, so that the order is stable, which
+// keyset pagination relies upon.
+var itemOrder = map[todo.ItemSort]string{
+	todo.ItemSortPosition:    "position, id",
+	todo.ItemSortID:          "id",
+	todo.ItemSortDue:         "due NULLS LAST, id",
+	todo.ItemSortDueDesc:     "due DESC NULLS LAST, id",
+	todo.ItemSortDescription: "description, id",
+}
+
+// itemFilterConditions for the WHERE clause of an items query. Values are bound as query args via arg, which returns
+// the placeholder to use.
+func itemFilterConditions(filter todo.ItemFilter, arg func(any) string) []string {
+	var conds []string
+
+	if !filter.Include.Archived {
+		conds = append(conds, "archived IS NULL")
+	}
+
+	if !filter.Include.Deleted {
+		conds = append(conds, "deleted IS NULL")
+	}
+
+	switch filter.Status {
+	case todo.ItemStatusOpen:
+		conds = append(conds, "completed IS NULL")
+	case todo.ItemStatusCompleted:
+		conds = append(conds, "completed IS NOT NULL")
+	case todo.ItemStatusOverdue:
+		conds = append(conds, "completed IS NULL", "due < now()")
+	}
+
+	if filter.DueBefore != nil {
+		conds = append(conds, "due < "+arg(filter.DueBefore.UTC()))
+	}
+
+	if filter.DueAfter != nil {
+		conds = append(conds, "due > "+arg(filter.DueAfter.UTC()))
+	}
+
+	if len(filter.Tags) > 0 {
+		tagged := "SELECT it.item_id FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE t.name = ANY(" + arg(pq.Array(filter.Tags)) + ")"
+
+		if filter.TagMatch == todo.TagMatchAll {
+			// Tags are distinct, so an item having as many of them as were requested has every one of them
+			tagged += " GROUP BY it.item_id HAVING count(*) = " + arg(len(filter.Tags))
+		}
+
+		conds = append(conds, "id IN ("+tagged+")")
+	}
+
+	return conds
+}
+
+// sortKey of the last item of a page, which identifies where the next page continues from for a particular sort.
+type sortKey struct {
+	Sort        todo.ItemSort `json:"sort"`
+	ID          string        `json:"id"`
+	Due         *time.Time    `json:"due,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Position    int64         `json:"position,omitempty"`
+}
+
+// itemKey of the item for keyset pagination. Sorting by ID only requires the ID, while other sorts require the sorted
+// value as well.
+func itemKey(sort todo.ItemSort, i todo.Item) string {
+	if sort == todo.ItemSortID {
+		return i.ID
+	}
+
+	k := sortKey{Sort: sort, ID: i.ID}
+
+	switch sort {
+	case todo.ItemSortDue, todo.ItemSortDueDesc:
+		k.Due = i.Due
+	case todo.ItemSortDescription:
+		k.Description = i.Description
+	case todo.ItemSortPosition:
+		k.Position = i.Position
+	}
+
+	b, _ := json.Marshal(&k)
+
+	return string(b)
+}
+
+// itemKeysetCondition which selects only those items which follow the item identified by the key, in the sort order.
+func itemKeysetCondition(sort todo.ItemSort, key string, arg func(any) string) (string, error) {
+	if sort == todo.ItemSortID {
+		if !validID(key) {
+			return "", todo.InvalidArgumentError(fmt.Sprintf("page key is not valid for sort %q", sort))
+		}
+
+		return "id > " + arg(key), nil
+	}
+
+	var k sortKey
+	if err := json.Unmarshal([]byte(key), &k); err != nil || k.Sort != sort || !validID(k.ID) {
+		return "", todo.InvalidArgumentError(fmt.Sprintf("page key is not valid for sort %q", sort))
+	}
+
+	switch {
+	case sort == todo.ItemSortPosition:
+		return fmt.Sprintf("(position, id) > (%s, %s)", arg(k.Position), arg(k.ID)), nil
+	case sort == todo.ItemSortDescription:
+		return fmt.Sprintf("(description, id) > (%s, %s)", arg(k.Description), arg(k.ID)), nil
+	case k.Due == nil:
+		// Items without a due date are sorted last, so only those remain
+		return fmt.Sprintf("(due IS NULL AND id > %s)", arg(k.ID)), nil
+	case sort == todo.ItemSortDue:
+		due := arg(*k.Due)
+		return fmt.Sprintf("(due > %[1]s OR (due = %[1]s AND id > %[2]s) OR due IS NULL)", due, arg(k.ID)), nil
+	default:
+		due := arg(*k.Due)
+		return fmt.Sprintf("(due < %[1]s OR (due = %[1]s AND id > %[2]s) OR due IS NULL)", due, arg(k.ID)), nil
+	}
+}
+
+// checkParent which a subtask is to be created under. The parent must be an item of the same list, which is not already
+// nested as deeply as subtasks are allowed to be.
+func (r *ListRepository) checkParent(ctx context.Context, listID, parentID string) error {
+	query := `
+		-- Name: TODO List Item Depth
+		WITH RECURSIVE ancestors AS (
+		     SELECT id,
+		            parent_id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT i.id,
+		            i.parent_id
+		       FROM items i
+		       JOIN ancestors a ON a.parent_id = i.id
+		)
+		SELECT count(*)
+		  FROM ancestors
+	`
+
+	depth, err := queryRow(ctx, r.db, func(n *int) []any { return []any{n} }, query, parentID, listID)
+	if err != nil {
+		return fmt.Errorf("failed to determine depth of item %q of todo list %q: %w", parentID, listID, err)
+	}
+
+	switch {
+	case *depth == 0:
+		return todo.NotFoundError(fmt.Sprintf("parent item with id %q does not exist in list %q", parentID, listID))
+	case *depth > r.maxDepth:
+		return todo.InvalidArgumentError(fmt.Sprintf("subtasks must not be nested more than %d deep", r.maxDepth))
+	}
+
+	return nil
+}
+
+// subtasks of the items, including those nested within other subtasks, ordered by position. Archived and deleted
+// subtasks are excluded unless included, along with any nested within them.
+func (r *ListRepository) subtasks(ctx context.Context, itemIDs []string, include todo.Include) ([]todo.Item, error) {
+	query := `
+		-- Name: TODO List Item Subtasks
+		WITH RECURSIVE subtasks AS (
+		     SELECT id
+		       FROM items
+		      WHERE parent_id = ANY($1::int[])
+		        AND ($2::boolean OR archived IS NULL)
+		        AND ($3::boolean OR deleted IS NULL)
+		      UNION ALL
+		     SELECT i.id
+		       FROM items i
+		       JOIN subtasks s ON i.parent_id = s.id
+		      WHERE ($2::boolean OR i.archived IS NULL)
+		        AND ($3::boolean OR i.deleted IS NULL)
+		)
+		SELECT ` + itemColumns + `
+		  FROM items
+		 WHERE id IN (SELECT id FROM subtasks)
+		 ORDER BY position, id
+	`
+
+	subtasks, err := queryRows(ctx, r.db, itemCols, query, pq.Array(itemIDs), include.Archived, include.Deleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query for subtasks of %d items: %w", len(itemIDs), err)
+	}
+
+	return subtasks, nil
+}
+
+// nestSubtasks within the items which they belong to, at any depth.
+func nestSubtasks(items, subtasks []todo.Item) {
+	children := make(map[string][]todo.Item)
+	for _, s := range subtasks {
+		children[*s.ParentID] = append(children[*s.ParentID], s)
+	}
+
+	var nest func(items []todo.Item)
+	nest = func(items []todo.Item) {
+		for i := range items {
+			items[i].Subtasks = children[items[i].ID]
+			nest(items[i].Subtasks)
+		}
+	}
+
+	nest(items)
+}
+
+// itemColumns queried for each item, in the order scanned by itemCols. The items table must not be aliased, as the
+// tags and progress of each item are queried by its name.
+const itemColumns = `id,
+		description,
+		due,
+		completed,
+		priority,
+		recurrence,
+		ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		parent_id,
+		auto_complete,
+		(SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		position,
+		archived,
+		deleted`
+
+func itemCols(i *todo.Item) []any {
+	return []any{
+		&i.ID,
+		&i.Description,
+		&i.Due,
+		&i.Completed,
+		&i.Priority,
+		recurrenceScanner{r: &i.Recurrence},
+		(*pq.StringArray)(&i.Tags),
+		&i.ParentID,
+		&i.AutoComplete,
+		progressScanner{p: &i.Progress},
+		&i.Position,
+		&i.Archived,
+		&i.Deleted,
+	}
+}
+
+func itemNotFound(listID, itemID string) todo.NotFoundError {
+	return todo.NotFoundError(fmt.Sprintf("item with id %q does not exist in list %q", itemID, listID))
+}
+
+// itemResult of a statement which modifies a single item, where no resulting row means the item does not exist.
+func itemResult(item *todo.Item, err error, listID, itemID, op string) (*todo.Item, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, itemNotFound(listID, itemID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s item %q of todo list %q: %w", op, itemID, listID, err)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,1305 @@
+package database_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestCreateItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Item   todo.Item
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+	due := time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)
+	dueOffset := due.In(time.FixedZone("AEST", 10*60*60))
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{Item: todo.Item{Description: "Washing"}, ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+					mock.ExpectBegin()
+					mockCreateItemQuery(mock, "1", todo.Item{Description: "Washing"}).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"List owned by another User": {
+			Args: args{Item: todo.Item{Description: "Washing"}, ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockNoListAccess(mock, "1")
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Archived List": {
+			Args: args{Item: todo.Item{Description: "Washing"}, ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockHiddenList(mock, "1", true, false)
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"List does not exist": {
+			Args: args{Item: todo.Item{Description: "Washing"}, ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "1")
+					mock.ExpectBegin()
+					mockCreateItemQuery(mock, "1", todo.Item{Description: "Washing"}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Created": {
+			Args: args{Item: todo.Item{Description: "Attend & Present", Due: &due}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mock.ExpectBegin()
+					mockCreateItemQuery(mock, "2", todo.Item{Description: "Attend & Present", Due: &due}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "7", Description: "Attend & Present", Due: &due}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "7", Description: "Attend & Present", Due: &due}},
+		},
+		"Created with Due Offset": {
+			Args: args{Item: todo.Item{Description: "Attend & Present", Due: &dueOffset}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mock.ExpectBegin()
+					// The column stores no offset, so the same instant must be stored in UTC
+					mockCreateItemQuery(mock, "2", todo.Item{Description: "Attend & Present", Due: &due}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "7", Description: "Attend & Present", Due: &due}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "7", Description: "Attend & Present", Due: &due}},
+		},
+		"Created with Priority": {
+			Args: args{Item: todo.Item{Description: "Renew Passport", Priority: todo.PriorityUrgent}, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "3")
+					mock.ExpectBegin()
+					mockCreateItemQuery(mock, "3", todo.Item{Description: "Renew Passport", Priority: todo.PriorityUrgent}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "8", Description: "Renew Passport", Priority: todo.PriorityUrgent}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "8", Description: "Renew Passport", Priority: todo.PriorityUrgent}},
+		},
+		"Depth Query failure": {
+			Args: args{Item: todo.Item{ParentID: ptr("7"), Description: "Slides"}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemDepthQuery(mock, "7", "2").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Parent does not exist": {
+			Args: args{Item: todo.Item{ParentID: ptr("7"), Description: "Slides"}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemDepthQuery(mock, "7", "2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`parent item with id "7" does not exist in list "2"`)},
+		},
+		"Parent nested too deeply": {
+			Args: args{Item: todo.Item{ParentID: ptr("7"), Description: "Slides"}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemDepthQuery(mock, "7", "2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(maxSubtaskDepth + 1))
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError("subtasks must not be nested more than 2 deep")},
+		},
+		"Created Subtask": {
+			Args: args{Item: todo.Item{ParentID: ptr("7"), Description: "Slides", AutoComplete: true}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemDepthQuery(mock, "7", "2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(maxSubtaskDepth))
+					mock.ExpectBegin()
+					mockCreateItemQuery(mock, "2", todo.Item{ParentID: ptr("7"), Description: "Slides", AutoComplete: true}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "9", ParentID: ptr("7"), Description: "Slides", AutoComplete: true}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "9", ParentID: ptr("7"), Description: "Slides", AutoComplete: true}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.CreateItem(userContext(), tt.Args.ListID, tt.Args.Item)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Creation error")
+				return
+			}
+
+			require.NoError(t, err, "Creation error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		Update todo.ItemUpdate
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+	due := time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)
+	dueOffset := due.In(time.FixedZone("AEST", 10*60*60))
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Description: ptr("Washing")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{Description: ptr("Washing")}).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Deleted List": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Description: ptr("Washing")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockHiddenList(mock, "1", false, true)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"No Result": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{SetDue: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{SetDue: true}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Deleted Item": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Description: ptr("Ironing")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					// Deleted items are not matched by the update
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{Description: ptr("Ironing")}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Updated": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Due: &due, SetDue: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{Due: &due, SetDue: true}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Due: &due}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Due: &due}},
+		},
+		"Updated with Due Offset": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Due: &dueOffset, SetDue: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{Due: &due, SetDue: true}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Due: &due}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Due: &due}},
+		},
+		"Priority Updated": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{Priority: ptr(todo.PriorityLow)}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{Priority: ptr(todo.PriorityLow)}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Priority: todo.PriorityLow}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Priority: todo.PriorityLow}},
+		},
+		"Auto-Complete Updated": {
+			Args: args{ItemID: "3", ListID: "1", Update: todo.ItemUpdate{AutoComplete: ptr(true)}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateItemQuery(mock, "3", "1", todo.ItemUpdate{AutoComplete: ptr(true)}).
+						WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", AutoComplete: true, Progress: &todo.Progress{Done: 1, Total: 3}}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", AutoComplete: true, Progress: &todo.Progress{Done: 1, Total: 3}}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.UpdateItem(userContext(), tt.Args.ListID, tt.Args.ItemID, tt.Args.Update)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Update error")
+				return
+			}
+
+			require.NoError(t, err, "Update error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestCompleteItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		// Loc of the repository, which defaults to UTC
+		Loc *time.Location
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+	done := time.Date(2023, time.June, 29, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2023, time.June, 28, 9, 0, 0, 0, time.UTC)
+	weekly := &todo.Recurrence{Frequency: todo.FrequencyWeekly, Interval: 1}
+
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err, "Loading location")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"No Result": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows())
+					mockItemQuery(mock, "3", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Archived Item": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					// Archived items are neither completed, nor found afterwards, so no occurrence is created
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows())
+					mockItemQuery(mock, "3", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Already Completed": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows())
+					// A recurring item which is already complete does not create another occurrence
+					mockItemQuery(mock, "3", "1", todo.Include{}).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Due: &due, Completed: &done, Recurrence: weekly}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Due: &due, Completed: &done, Recurrence: weekly}},
+		},
+		"Completed": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Completed: &done}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Completed: &done}},
+		},
+		"Recurring": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Due: &due, Completed: &done, Recurrence: weekly}))
+					mockCreateNextItemQuery(mock, "3", time.Date(2023, time.July, 5, 9, 0, 0, 0, time.UTC)).WillReturnResult(sqlmock.NewResult(0, 2))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Due: &due, Completed: &done, Recurrence: weekly}},
+		},
+		"Recurring across Daylight Saving": {
+			// 9am on Saturday 1 April 2023 in Sydney, before daylight saving ends on Sunday 2 April
+			Args: args{ItemID: "3", ListID: "1", Loc: sydney},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows(todo.Item{
+						ID:          "3",
+						Description: "Washing",
+						Due:         ptr(time.Date(2023, time.March, 31, 22, 0, 0, 0, time.UTC)),
+						Completed:   ptr(time.Date(2023, time.March, 31, 23, 0, 0, 0, time.UTC)),
+						Recurrence:  &todo.Recurrence{Frequency: todo.FrequencyDaily, Interval: 1},
+					}))
+					// Still 9am in Sydney, which is an hour later in UTC once daylight saving has ended
+					mockCreateNextItemQuery(mock, "3", time.Date(2023, time.April, 1, 23, 0, 0, 0, time.UTC)).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{
+				Item: &todo.Item{
+					ID:          "3",
+					Description: "Washing",
+					Due:         ptr(time.Date(2023, time.March, 31, 22, 0, 0, 0, time.UTC)),
+					Completed:   ptr(time.Date(2023, time.March, 31, 23, 0, 0, 0, time.UTC)),
+					Recurrence:  &todo.Recurrence{Frequency: todo.FrequencyDaily, Interval: 1},
+				},
+			},
+		},
+		"Subtask": {
+			Args: args{ItemID: "5", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "5", "1").WillReturnRows(mockItemRows(todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites", Completed: &done}))
+					// The parent does not auto-complete, or has other subtasks still to be done
+					mockAutoCompleteParentQuery(mock, "5").WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites", Completed: &done}},
+		},
+		"Subtask Auto-Completing Parents": {
+			Args: args{ItemID: "5", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "5", "1").WillReturnRows(mockItemRows(todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites", Completed: &done}))
+					mockAutoCompleteParentQuery(mock, "5").WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow("3", "2"))
+					// The top-level item has no parent of its own, so auto-completion stops there
+					mockAutoCompleteParentQuery(mock, "3").WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id"}).AddRow("2", nil))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites", Completed: &done}},
+		},
+		"Parent Auto-Complete failure": {
+			Args: args{ItemID: "5", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "5", "1").WillReturnRows(mockItemRows(todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites", Completed: &done}))
+					mockAutoCompleteParentQuery(mock, "5").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Next Occurrence failure": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCompleteItemQuery(mock, "3", "1").WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Due: &due, Completed: &done, Recurrence: weekly}))
+					mockCreateNextItemQuery(mock, "3", time.Date(2023, time.July, 5, 9, 0, 0, 0, time.UTC)).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loc := tt.Args.Loc
+			if loc == nil {
+				loc = time.UTC
+			}
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), loc, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.CompleteItem(userContext(), tt.Args.ListID, tt.Args.ItemID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Completion error")
+				return
+			}
+
+			require.NoError(t, err, "Completion error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestReopenItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockReopenItemQuery(mock, "3", "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"No Result": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockReopenItemQuery(mock, "3", "1").WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Deleted Item": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					// Deleted items are not matched by the reopening
+					mockReopenItemQuery(mock, "3", "1").WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Reopened": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockReopenItemQuery(mock, "3", "1").WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing"}},
+		},
+		"Subtask Reopened": {
+			Args: args{ItemID: "5", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockReopenItemQuery(mock, "5", "1").WillReturnRows(mockItemRows(todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites"}))
+					mockReopenParentsQuery(mock, "3").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites"}},
+		},
+		"Parent Reopen failure": {
+			Args: args{ItemID: "5", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockReopenItemQuery(mock, "5", "1").WillReturnRows(mockItemRows(todo.Item{ID: "5", ParentID: ptr("3"), Description: "Whites"}))
+					mockReopenParentsQuery(mock, "3").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.ReopenItem(userContext(), tt.Args.ListID, tt.Args.ItemID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Reopen error")
+				return
+			}
+
+			require.NoError(t, err, "Reopen error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		Move   todo.ItemMove
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Relative to itself": {
+			Args:   args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "3"}},
+			Fields: fields{MockExpectations: func(sqlmock.Sqlmock) {}},
+			Want:   want{Error: todo.InvalidArgumentError("an item cannot be moved relative to itself")},
+		},
+		"Positions Query failure": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Other Item does not exist": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "5" does not exist in list "1"`)},
+		},
+		"Item does not exist": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(2048, 1024, 3072))
+					mockMoveItemQuery(mock, "3", "1", 1536).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Archived Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(2048, 1024, 3072))
+					// Archived items are not matched by the move
+					mockMoveItemQuery(mock, "3", "1", 2560).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"After a Deleted Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					// Deleted items are not found to position others around
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "5" does not exist in list "1"`)},
+		},
+		"Before another Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(2048, 1024, 3072))
+					mockMoveItemQuery(mock, "3", "1", 1536).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Position: 1536}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Position: 1536}},
+		},
+		"Before the first Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{Before: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(1024, nil, 2048))
+					mockMoveItemQuery(mock, "3", "1", 0).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing"}},
+		},
+		"After another Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(2048, 1024, 2560))
+					mockMoveItemQuery(mock, "3", "1", 2304).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Position: 2304}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Position: 2304}},
+		},
+		"After the last Item": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(3072, 2048, nil))
+					mockMoveItemQuery(mock, "3", "1", 4096).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Position: 4096}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Position: 4096}},
+		},
+		"Renumbered when there is no gap": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(1536, 1024, 1537))
+					mockPauseHistoryQuery(mock, "on").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockRenumberPositionsQuery(mock, "1").WillReturnResult(sqlmock.NewResult(0, 4))
+					mockPauseHistoryQuery(mock, "off").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(2048, 1024, 4096))
+					mockMoveItemQuery(mock, "3", "1", 3072).WillReturnRows(mockItemRows(todo.Item{ID: "3", Description: "Washing", Position: 3072}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "3", Description: "Washing", Position: 3072}},
+		},
+		"Pause History failure": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(1536, 1024, 1537))
+					mockPauseHistoryQuery(mock, "on").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Renumber failure": {
+			Args: args{ItemID: "3", ListID: "1", Move: todo.ItemMove{After: "5"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNeighbourPositionsQuery(mock, "5", "1", "3").WillReturnRows(mockNeighbourPositionRows(1536, 1024, 1537))
+					mockPauseHistoryQuery(mock, "on").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockRenumberPositionsQuery(mock, "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.MoveItem(userContext(), tt.Args.ListID, tt.Args.ItemID, tt.Args.Move)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Move error")
+				return
+			}
+
+			require.NoError(t, err, "Move error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestDeleteItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteItemQuery(mock, "3", "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"No Result": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteItemQuery(mock, "3", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Deleted": {
+			Args: args{ItemID: "3", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteItemQuery(mock, "3", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			err := repo.DeleteItem(userContext(), tt.Args.ListID, tt.Args.ItemID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Deletion error")
+				return
+			}
+
+			require.NoError(t, err, "Deletion error")
+		})
+	}
+}
+
+func mockCreateItemQuery(mock sqlmock.Sqlmock, listID string, item todo.Item) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Create TODO List Item
+		INSERT INTO items (list_id, description, due, priority, recurrence, parent_id, auto_complete, position)
+		SELECT id,
+		       $2,
+		       $3,
+		       $4,
+		       $5,
+		       $6,
+		       $7,
+		       (SELECT COALESCE(max(position), 0) + $8 FROM items WHERE list_id = lists.id)
+		  FROM lists
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          due,
+		          completed,
+		          priority,
+		          recurrence,
+		          ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		          parent_id,
+		          auto_complete,
+		          (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		          position,
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID, item.Description, item.Due, item.Priority, recurrenceValue(item.Recurrence), item.ParentID, item.AutoComplete, 1024)
+}
+
+func mockUpdateItemQuery(mock sqlmock.Sqlmock, itemID, listID string, update todo.ItemUpdate) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Update TODO List Item
+		UPDATE items
+		   SET description = COALESCE($3, description),
+		       due = CASE WHEN $4::boolean THEN $5 ELSE due END,
+		       priority = COALESCE($6, priority),
+		       recurrence = CASE WHEN $7::boolean THEN $8 ELSE recurrence END,
+		       auto_complete = COALESCE($9, auto_complete)
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING id,
+		          description,
+		          due,
+		          completed,
+		          priority,
+		          recurrence,
+		          ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		          parent_id,
+		          auto_complete,
+		          (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		          position,
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID, update.Description, update.SetDue, update.Due, update.Priority, update.SetRecurrence, recurrenceValue(update.Recurrence), update.AutoComplete)
+}
+
+func mockCompleteItemQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Complete TODO List Item
+		UPDATE items
+		   SET completed = now()
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND completed IS NULL
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING id,
+		          description,
+		          due,
+		          completed,
+		          priority,
+		          recurrence,
+		          ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		          parent_id,
+		          auto_complete,
+		          (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		          position,
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID)
+}
+
+func mockCreateNextItemQuery(mock sqlmock.Sqlmock, itemID string, due time.Time) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Create Next TODO List Item Occurrence
+		WITH next AS (
+		     INSERT INTO items (list_id, description, due, priority, recurrence, parent_id, auto_complete, position)
+		     SELECT list_id,
+		            description,
+		            $2,
+		            priority,
+		            recurrence,
+		            parent_id,
+		            auto_complete,
+		            (SELECT max(p.position) + $3 FROM items p WHERE p.list_id = items.list_id)
+		       FROM items
+		      WHERE id = $1
+		     RETURNING id
+		)
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT next.id,
+		       it.tag_id
+		  FROM next,
+		       item_tags it
+		 WHERE it.item_id = $1
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, due, 1024)
+}
+
+func mockAutoCompleteParentQuery(mock sqlmock.Sqlmock, itemID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Auto-Complete TODO List Item Parent
+		UPDATE items p
+		   SET completed = now()
+		  FROM items c
+		 WHERE c.id = $1
+		   AND p.id = c.parent_id
+		   AND p.auto_complete
+		   AND p.completed IS NULL
+		   AND NOT EXISTS (SELECT 1 FROM items s WHERE s.parent_id = p.id AND s.completed IS NULL AND s.deleted IS NULL)
+		RETURNING p.id,
+		          p.parent_id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID)
+}
+
+func mockReopenItemQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Reopen TODO List Item
+		UPDATE items
+		   SET completed = NULL
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING id,
+		          description,
+		          due,
+		          completed,
+		          priority,
+		          recurrence,
+		          ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		          parent_id,
+		          auto_complete,
+		          (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		          position,
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID)
+}
+
+func mockReopenParentsQuery(mock sqlmock.Sqlmock, parentID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Reopen Auto-Completed TODO List Item Parents
+		WITH RECURSIVE ancestors AS (
+		     SELECT id,
+		            parent_id
+		       FROM items
+		      WHERE id = $1
+		        AND auto_complete
+		      UNION ALL
+		     SELECT p.id,
+		            p.parent_id
+		       FROM items p
+		       JOIN ancestors a ON a.parent_id = p.id
+		      WHERE p.auto_complete
+		)
+		UPDATE items
+		   SET completed = NULL
+		 WHERE id IN (SELECT id FROM ancestors)
+	`
+
+	return mock.ExpectExec(q).WithArgs(parentID)
+}
+
+func mockItemDepthQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Item Depth
+		WITH RECURSIVE ancestors AS (
+		     SELECT id,
+		            parent_id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT i.id,
+		            i.parent_id
+		       FROM items i
+		       JOIN ancestors a ON a.parent_id = i.id
+		)
+		SELECT count(*)
+		  FROM ancestors
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID)
+}
+
+func mockNeighbourPositionsQuery(mock sqlmock.Sqlmock, anchorID, listID, itemID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Item Neighbour Positions
+		SELECT a.position,
+		       (SELECT max(p.position) FROM items p WHERE p.list_id = a.list_id AND p.id <> $3 AND p.position < a.position),
+		       (SELECT min(n.position) FROM items n WHERE n.list_id = a.list_id AND n.id <> $3 AND n.position > a.position)
+		  FROM items a
+		 WHERE a.id = $1
+		   AND a.list_id = $2
+		   AND a.archived IS NULL
+		   AND a.deleted IS NULL
+	`
+
+	return mock.ExpectQuery(q).WithArgs(anchorID, listID, itemID)
+}
+
+// mockNeighbourPositionRows with the position of the item being moved relative to, and those of the items either side
+// of it. No row is included when no positions are provided.
+func mockNeighbourPositionRows(positions ...driver.Value) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"position", "prev", "next"})
+
+	if len(positions) > 0 {
+		rows.AddRow(positions...)
+	}
+
+	return rows
+}
+
+func mockRenumberPositionsQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Renumber TODO List Item Positions
+		UPDATE items i
+		   SET position = r.rank * $2
+		  FROM (SELECT id, row_number() OVER (ORDER BY position, id) AS rank FROM items WHERE list_id = $1) r
+		 WHERE i.id = r.id
+	`
+
+	return mock.ExpectExec(q).WithArgs(listID, 1024)
+}
+
+func mockMoveItemQuery(mock sqlmock.Sqlmock, itemID, listID string, position int64) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Move TODO List Item
+		UPDATE items
+		   SET position = $3
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		RETURNING id,
+		          description,
+		          due,
+		          completed,
+		          priority,
+		          recurrence,
+		          ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		          parent_id,
+		          auto_complete,
+		          (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		          position,
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID, position)
+}
+
+func mockDeleteItemQuery(mock sqlmock.Sqlmock, itemID, listID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Delete TODO List Item
+		WITH RECURSIVE subtree AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = $1
+		        AND list_id = $2
+		      UNION ALL
+		     SELECT c.id
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		)
+		UPDATE items
+		   SET deleted = COALESCE(deleted, now())
+		 WHERE id IN (SELECT id FROM subtree)
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, listID)
+}
@@ -5,55 +5,127 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/dackroyd/todo-list/backend/todo"
 )
 
 type ListRepository struct {
 	db *sql.DB
+
+	// dueHorizon for items to be considered due soon, for lists which do not have their own horizon.
+	dueHorizon time.Duration
+
+	// loc in which dates are calculated, such as the next occurrences of recurring items, and the due dates of items
+	// created from templates.
+	loc *time.Location
+
+	// maxDepth to which subtasks may be nested. Top-level items are at a depth of 0, so 0 disallows subtasks.
+	maxDepth int
 }
 
-func NewListRepository(db *sql.DB) *ListRepository {
-	return &ListRepository{db: db}
+func NewListRepository(db *sql.DB, dueHorizon time.Duration, loc *time.Location, maxDepth int) *ListRepository {
+	return &ListRepository{db: db, dueHorizon: dueHorizon, loc: loc, maxDepth: maxDepth}
 }
 
-func (r *ListRepository) Items(ctx context.Context, listID string) ([]todo.Item, error) {
-	query := `
+// Items of a TODO list matching the filter, in the requested page. The key of the last item is returned when there are
+// further pages. When retrieving a tree, pages consist of top-level items, with their subtasks nested within them.
+// Archived and deleted lists are not found, unless included along with such items.
+func (r *ListRepository) Items(ctx context.Context, listID string, filter todo.ItemFilter, page todo.Page) ([]todo.Item, string, error) {
+	if err := r.checkAccess(ctx, r.db, listID, todo.RoleViewer, filter.Include); err != nil {
+		return nil, "", err
+	}
+
+	args := []any{listID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := append([]string{"list_id = $1"}, itemFilterConditions(filter, arg)...)
+
+	if filter.Tree {
+		conds = append(conds, "parent_id IS NULL")
+	}
+
+	sort := filter.Sort
+	if sort == "" {
+		sort = todo.ItemSortPosition
+	}
+
+	if _, ok := itemOrder[sort]; !ok {
+		return nil, "", todo.InvalidArgumentError(fmt.Sprintf("unsupported sort %q", sort))
+	}
+
+	if page.After != "" {
+		cond, err := itemKeysetCondition(sort, page.After, arg)
+		if err != nil {
+			return nil, "", err
+		}
+
+		conds = append(conds, cond)
+	}
+
+	query := fmt.Sprintf(`
 		-- Name: TODO List Items
-		SELECT id,
-		       description,
-		       due,
-		       completed
+		SELECT %s
 		  FROM items
-		 WHERE list_id = $1
-	 `
+		 WHERE %s
+		 ORDER BY %s
+		 LIMIT %s
+	 `, itemColumns, strings.Join(conds, "\n\t\t   AND "), itemOrder[sort], arg(page.Limit+1))
+
+	items, err := queryRows(ctx, r.db, itemCols, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query for list items: %w", err)
+	}
+
+	items, next := nextPage(items, page.Limit, func(i todo.Item) string { return itemKey(sort, i) })
 
-	cols := func(i *todo.Item) []any {
-		return []any{&i.ID, &i.Description, &i.Due, &i.Completed}
+	if !filter.Tree || len(items) == 0 {
+		return items, next, nil
 	}
 
-	items, err := queryRows(ctx, r.db, cols, query, listID)
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	subtasks, err := r.subtasks(ctx, ids, filter.Include)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query for list items: %w", err)
+		return nil, "", err
 	}
 
-	return items, nil
+	nestSubtasks(items, subtasks)
+
+	return items, next, nil
 }
 
-func (r *ListRepository) List(ctx context.Context, listID string) (*todo.DueList, error) {
+// List with the items which are due soon. The horizon overrides that of the list, and the server default, when not nil.
+// Archived and deleted lists are not found, unless included.
+func (r *ListRepository) List(ctx context.Context, listID string, horizon *time.Duration, include todo.Include) (*todo.DueList, error) {
 	query := `
 		-- Name: TODO List
 		SELECT id,
-		       description
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon),
+		       archived,
+		       deleted
 		  FROM lists
 		  WHERE id = $1
+		    AND ($2::boolean OR archived IS NULL)
+		    AND ($3::boolean OR deleted IS NULL)
 	`
 
-	cols := func(l *todo.List) []any {
-		return []any{&l.ID, &l.Description}
+	access, err := r.listAccess(ctx, r.db, listID)
+	if err != nil {
+		return nil, err
 	}
 
-	list, err := queryRow(ctx, r.db, cols, query, listID)
+	list, err := queryRow(ctx, r.db, listCols, query, listID, include.Archived, include.Deleted)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
 	}
@@ -62,70 +134,277 @@ func (r *ListRepository) List(ctx context.Context, listID string) (*todo.DueList
 		return nil, fmt.Errorf("failed to query todo list: %w", err)
 	}
 
-	due, err := r.dueItems(ctx, listID)
+	due, err := r.dueItems(ctx, horizon, list.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &todo.DueList{DueItems: due, List: *list}, nil
+	dueList := r.dueList(*list, due[list.ID], horizon)
+	dueList.Role = access.Role
+
+	return dueList, nil
 }
 
-func (r *ListRepository) Lists(ctx context.Context) ([]todo.DueList, error) {
+// Lists of TODO items owned by the authenticated user, or shared with them, in the requested page. The key of the last list is returned when
+// there are further pages. The horizon overrides that of each list, and the server default, when not nil. Archived and
+// deleted lists are excluded, unless included.
+func (r *ListRepository) Lists(ctx context.Context, page todo.Page, horizon *time.Duration, include todo.Include) ([]todo.DueList, string, error) {
 	query := `
 		-- Name: TODO Lists
-		SELECT id,
-		       description
-		  FROM lists
+		SELECT l.id,
+		       l.description,
+		       EXTRACT(EPOCH FROM l.due_horizon),
+		       l.archived,
+		       l.deleted,
+		       CASE WHEN l.owner_id = $5 THEN 'owner' ELSE m.role END,
+		       l.owner_id <> $5
+		  FROM lists l
+		  LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $5
+		 WHERE ($1::int IS NULL OR l.id > $1)
+		   AND ($3::boolean OR l.archived IS NULL)
+		   AND ($4::boolean OR l.deleted IS NULL)
+		   AND (l.owner_id = $5 OR m.user_id IS NOT NULL)
+		 ORDER BY l.id
+		 LIMIT $2
 	`
 
-	cols := func(l *todo.List) []any {
-		return []any{&l.ID, &l.Description}
+	after, err := pageKeyID(page.After)
+	if err != nil {
+		return nil, "", err
+	}
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, "", err
 	}
 
-	lists, err := queryRows(ctx, r.db, cols, query)
+	lists, err := queryRows(ctx, r.db, memberListCols, query, after, page.Limit+1, include.Archived, include.Deleted, uid)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query todo lists: %w", err)
+		return nil, "", fmt.Errorf("failed to query todo lists: %w", err)
 	}
 
 	if len(lists) == 0 {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	dueList := make([]todo.DueList, len(lists))
+	lists, next := nextPage(lists, page.Limit, func(l memberList) string { return l.List.ID })
+
+	ids := make([]string, len(lists))
 	for i, l := range lists {
-		due, err := r.dueItems(ctx, l.ID)
-		if err != nil {
-			return nil, err
+		ids[i] = l.List.ID
+	}
+
+	// Due items for all lists are fetched at once, rather than per-list, so that the number of queries is fixed
+	due, err := r.dueItems(ctx, horizon, ids...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dueLists := make([]todo.DueList, len(lists))
+	for i, l := range lists {
+		dueLists[i] = *r.dueList(l.List, due[l.List.ID], horizon)
+		dueLists[i].Role = l.Role
+		dueLists[i].Shared = l.Shared
+	}
+
+	return dueLists, next, nil
+}
+
+// CreateList owned by the authenticated user.
+func (r *ListRepository) CreateList(ctx context.Context, list todo.List) (*todo.List, error) {
+	query := `
+		-- Name: Create TODO List
+		INSERT INTO lists (owner_id, description, due_horizon)
+		VALUES ($1, $2, make_interval(secs => $3))
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *todo.List
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		created, err = queryRow(ctx, tx, listCols, query, uid, list.Description, intervalSecs(list.DueHorizon))
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo list: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *ListRepository) UpdateList(ctx context.Context, listID string, update todo.ListUpdate) (*todo.List, error) {
+	query := `
+		-- Name: Update TODO List
+		UPDATE lists
+		   SET description = COALESCE($2, description),
+		       due_horizon = CASE WHEN $3::boolean THEN make_interval(secs => $4) ELSE due_horizon END
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	var list *todo.List
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
 		}
 
-		dueList[i] = todo.DueList{DueItems: due, List: l}
+		var err error
+		list, err = queryRow(ctx, tx, listCols, query, listID, update.Description, update.SetDueHorizon, intervalSecs(update.DueHorizon))
+
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
 	}
 
-	return dueList, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to update todo list %q: %w", listID, err)
+	}
+
+	return list, nil
 }
 
-func (r *ListRepository) dueItems(ctx context.Context, listID string) ([]todo.Item, error) {
+// DeleteList by moving it to the trash, along with its items, until it is restored or purged. Deleting a list which is
+// already in the trash keeps when it was originally deleted.
+func (r *ListRepository) DeleteList(ctx context.Context, listID string) error {
+	query := `
+		-- Name: Delete TODO List
+		UPDATE lists
+		   SET deleted = COALESCE(deleted, now())
+		 WHERE id = $1
+	`
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleOwner, hiddenLists); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, listID)
+		if err != nil {
+			return fmt.Errorf("failed to delete todo list %q: %w", listID, err)
+		}
+
+		return expectAffected(res, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID)))
+	})
+}
+
+// dueItems of each of the lists, keyed by list ID. Lists without any due items are not included. Items are due soon
+// when due within the horizon, which when nil is the horizon of the list, or the server default. Only top-level items
+// are included, which are due as soon as the first of their open subtasks is, at any depth, so that subtasks are not
+// listed again apart from the items they belong to. Archived and deleted items are never due.
+func (r *ListRepository) dueItems(ctx context.Context, horizon *time.Duration, listIDs ...string) (map[string][]todo.Item, error) {
 	query := `
 		-- Name: TODO Due List Items
-		SELECT id,
-		       description,
-		       due,
-		       completed
+		WITH RECURSIVE subtree AS (
+		     SELECT id AS root_id,
+		            id,
+		            due
+		       FROM items
+		      WHERE list_id = ANY($1::int[])
+		        AND parent_id IS NULL
+		        AND completed IS NULL
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION ALL
+		     SELECT s.root_id,
+		            c.id,
+		            c.due
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		      WHERE c.completed IS NULL
+		        AND c.archived IS NULL
+		        AND c.deleted IS NULL
+		),
+		effective AS (
+		     SELECT root_id AS item_id,
+		            min(due) AS effective_due
+		       FROM subtree
+		      GROUP BY root_id
+		)
+		SELECT list_id,
+		       ` + itemColumns + `
 		  FROM items
-		 WHERE list_id = $1
-		   AND due <= now() + INTERVAL '1 day'
-		   AND completed IS NULL
-		 ORDER BY due
+		  JOIN effective e ON e.item_id = items.id
+		 WHERE e.effective_due <= now() + COALESCE(make_interval(secs => $2), (SELECT l.due_horizon FROM lists l WHERE l.id = items.list_id), make_interval(secs => $3))
+		 ORDER BY list_id, e.effective_due, priority DESC
 	 `
 
-	itemCols := func(i *todo.Item) []any {
-		return []any{&i.ID, &i.Description, &i.Due, &i.Completed}
+	type listItem struct {
+		ListID string
+		Item   todo.Item
 	}
 
-	items, err := queryRows(ctx, r.db, itemCols, query, listID)
+	cols := func(li *listItem) []any {
+		return append([]any{&li.ListID}, itemCols(&li.Item)...)
+	}
+
+	dueHorizon := todo.Duration(r.dueHorizon)
+
+	items, err := queryRows(ctx, r.db, cols, query, pq.Array(listIDs), intervalSecs((*todo.Duration)(horizon)), intervalSecs(&dueHorizon))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query due items for todo list %q: %w", listID, err)
+		return nil, fmt.Errorf("failed to query due items for %d todo lists: %w", len(listIDs), err)
 	}
 
-	return items, nil
+	due := make(map[string][]todo.Item)
+	for _, li := range items {
+		due[li.ListID] = append(due[li.ListID], li.Item)
+	}
+
+	return due, nil
+}
+
+// dueList of the list with its due items, summarising how many are urgent.
+func (r *ListRepository) dueList(l todo.List, due []todo.Item, horizon *time.Duration) *todo.DueList {
+	dl := todo.DueList{DueItems: due, Horizon: r.horizon(l, horizon), List: l}
+
+	for _, item := range due {
+		if item.Priority == todo.PriorityUrgent {
+			dl.UrgentDue++
+		}
+	}
+
+	return &dl
+}
+
+// horizon applied for items of the list to be considered due soon, matching that used by dueItems.
+func (r *ListRepository) horizon(l todo.List, override *time.Duration) todo.Duration {
+	switch {
+	case override != nil:
+		return todo.Duration(*override)
+	case l.DueHorizon != nil:
+		return *l.DueHorizon
+	default:
+		return todo.Duration(r.dueHorizon)
+	}
+}
+
+func listCols(l *todo.List) []any {
+	return []any{&l.ID, &l.Description, intervalScanner{d: &l.DueHorizon}, &l.Archived, &l.Deleted}
+}
+
+// memberList is a TODO list, along with the role of the authenticated user on it.
+type memberList struct {
+	List   todo.List
+	Role   todo.Role
+	Shared bool
+}
+
+func memberListCols(l *memberList) []any {
+	return append(listCols(&l.List), &l.Role, &l.Shared)
 }
@@ -1,13 +1,16 @@
 package database_test
 
 import (
-	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -18,8 +21,16 @@ import (
 func TestItems(t *testing.T) {
 	t.Parallel()
 
+	practiceDue := time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC)
+	presentDue := time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)
+	dueAfter := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+	sydney := time.FixedZone("AEST", 10*60*60)
+
 	type args struct {
+		Filter todo.ItemFilter
 		ListID string
+		Page   todo.Page
 	}
 
 	type fields struct {
@@ -29,6 +40,7 @@ func TestItems(t *testing.T) {
 	type want struct {
 		Error error
 		Items []todo.Item
+		Next  string
 	}
 
 	queryErr := errors.New("failed to execute query")
@@ -39,27 +51,30 @@ func TestItems(t *testing.T) {
 		Want   want
 	}{
 		"Query failure": {
-			Args: args{ListID: "1"},
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockItemsQuery(mock, "1").WillReturnError(queryErr)
+					mockListAccess(mock, "1")
+					mockItemsQuery(mock, "1", todo.Page{Limit: 100}).WillReturnError(queryErr)
 				},
 			},
 			Want: want{Error: queryErr},
 		},
 		"Empty": {
-			Args: args{ListID: "1"},
+			Args: args{ListID: "1", Page: todo.Page{Limit: 100}},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockItemsQuery(mock, "1").WillReturnRows(mockItemRows())
+					mockListAccess(mock, "1")
+					mockItemsQuery(mock, "1", todo.Page{Limit: 100}).WillReturnRows(mockItemRows())
 				},
 			},
 		},
 		"Non-empty": {
-			Args: args{ListID: "2"},
+			Args: args{ListID: "2", Page: todo.Page{Limit: 100}},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockItemsQuery(mock, "2").WillReturnRows(mockItemRows(
+					mockListAccess(mock, "2")
+					mockItemsQuery(mock, "2", todo.Page{Limit: 100}).WillReturnRows(mockItemRows(
 						todo.Item{ID: "1", Description: "Bananas"},
 						todo.Item{ID: "2", Description: "Apples"},
 						todo.Item{ID: "3", Description: "Strawberries"},
@@ -74,6 +89,293 @@ func TestItems(t *testing.T) {
 				},
 			},
 		},
+		"First page with more": {
+			Args: args{ListID: "2", Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQuery(mock, "2", todo.Page{Limit: 2}).WillReturnRows(mockItemRows(
+						todo.Item{ID: "2", Description: "Apples", Position: 1024},
+						todo.Item{ID: "1", Description: "Bananas", Position: 2048},
+						todo.Item{ID: "3", Description: "Strawberries", Position: 3072},
+					))
+				},
+			},
+			Want: want{
+				Items: []todo.Item{
+					{ID: "2", Description: "Apples", Position: 1024},
+					{ID: "1", Description: "Bananas", Position: 2048},
+				},
+				Next: `{"sort":"position","id":"1","position":2048}`,
+			},
+		},
+		"Open items due soonest first": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Status: todo.ItemStatusOpen, Sort: todo.ItemSortDue}, Page: todo.Page{Limit: 1}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND completed IS NULL", "due NULLS LAST, id", "2", 2).WillReturnRows(mockItemRows(
+						todo.Item{ID: "5", Description: "Practice", Due: &practiceDue},
+						todo.Item{ID: "6", Description: "Attend & Present", Due: &presentDue},
+					))
+				},
+			},
+			Want: want{
+				Items: []todo.Item{{ID: "5", Description: "Practice", Due: &practiceDue}},
+				Next:  `{"sort":"due","id":"5","due":"2023-06-26T00:00:00Z"}`,
+			},
+		},
+		"Overdue items due within range, continuing from key": {
+			Args: args{
+				ListID: "2",
+				Filter: todo.ItemFilter{Status: todo.ItemStatusOverdue, DueAfter: &dueAfter, DueBefore: &dueBefore, Sort: todo.ItemSortDueDesc},
+				Page:   todo.Page{After: `{"sort":"-due","id":"6","due":"2023-06-29T08:00:00Z"}`, Limit: 10},
+			},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock,
+						"list_id = $1 AND archived IS NULL AND deleted IS NULL AND completed IS NULL AND due < now() AND due < $2 AND due > $3 AND (due < $4 OR (due = $4 AND id > $5) OR due IS NULL)",
+						"due DESC NULLS LAST, id",
+						"2", dueBefore, dueAfter, presentDue, "6", 11,
+					).WillReturnRows(mockItemRows(todo.Item{ID: "5", Description: "Practice", Due: &practiceDue}))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "5", Description: "Practice", Due: &practiceDue}}},
+		},
+		"Items due within range with offsets": {
+			Args: args{
+				ListID: "2",
+				Filter: todo.ItemFilter{DueAfter: ptr(dueAfter.In(sydney)), DueBefore: ptr(dueBefore.In(sydney))},
+				Page:   todo.Page{Limit: 10},
+			},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					// The column stores no offset, so the same instants must be compared in UTC
+					mockItemsQueryWhere(mock,
+						"list_id = $1 AND archived IS NULL AND deleted IS NULL AND due < $2 AND due > $3",
+						"position, id",
+						"2", dueBefore, dueAfter, 11,
+					).WillReturnRows(mockItemRows(todo.Item{ID: "5", Description: "Practice", Due: &practiceDue}))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "5", Description: "Practice", Due: &practiceDue}}},
+		},
+		"Continuing from key without due date": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Sort: todo.ItemSortDue}, Page: todo.Page{After: `{"sort":"due","id":"6"}`, Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND (due IS NULL AND id > $2)", "due NULLS LAST, id", "2", "6", 11).WillReturnRows(mockItemRows())
+				},
+			},
+		},
+		"Completed items by description, continuing from key": {
+			Args: args{
+				ListID: "2",
+				Filter: todo.ItemFilter{Status: todo.ItemStatusCompleted, Sort: todo.ItemSortDescription},
+				Page:   todo.Page{After: `{"sort":"description","id":"3","description":"Apples"}`, Limit: 10},
+			},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND completed IS NOT NULL AND (description, id) > ($2, $3)", "description, id", "2", "Apples", "3", 11).
+						WillReturnRows(mockItemRows(todo.Item{ID: "1", Description: "Bananas"}))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "1", Description: "Bananas"}}},
+		},
+		"Items with any of the tags": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Tags: []string{"@home", "@work"}}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock,
+						"list_id = $1 AND archived IS NULL AND deleted IS NULL AND id IN (SELECT it.item_id FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE t.name = ANY($2))",
+						"position, id",
+						"2", pq.Array([]string{"@home", "@work"}), 11,
+					).WillReturnRows(mockItemRows(
+						todo.Item{ID: "1", Description: "Bananas", Tags: todo.Tags{"@home"}},
+						todo.Item{ID: "4", Description: "Prepare Presentation", Tags: todo.Tags{"@home", "@work"}},
+					))
+				},
+			},
+			Want: want{
+				Items: []todo.Item{
+					{ID: "1", Description: "Bananas", Tags: todo.Tags{"@home"}},
+					{ID: "4", Description: "Prepare Presentation", Tags: todo.Tags{"@home", "@work"}},
+				},
+			},
+		},
+		"Items with all of the tags": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Tags: []string{"@home", "@work"}, TagMatch: todo.TagMatchAll}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock,
+						"list_id = $1 AND archived IS NULL AND deleted IS NULL AND id IN (SELECT it.item_id FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE t.name = ANY($2) GROUP BY it.item_id HAVING count(*) = $3)",
+						"position, id",
+						"2", pq.Array([]string{"@home", "@work"}), 2, 11,
+					).WillReturnRows(mockItemRows(todo.Item{ID: "4", Description: "Prepare Presentation", Tags: todo.Tags{"@home", "@work"}}))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "4", Description: "Prepare Presentation", Tags: todo.Tags{"@home", "@work"}}}},
+		},
+		"Tree of subtasks": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Tree: true}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND parent_id IS NULL", "position, id", "2", 11).WillReturnRows(mockItemRows(
+						todo.Item{ID: "1", Description: "Bananas"},
+						todo.Item{ID: "4", Description: "Prepare Presentation", Progress: &todo.Progress{Done: 1, Total: 2}},
+					))
+					mockSubtasksQuery(mock, todo.Include{}, "1", "4").WillReturnRows(mockItemRows(
+						todo.Item{ID: "5", ParentID: ptr("4"), Description: "Slides", Progress: &todo.Progress{Done: 0, Total: 1}},
+						todo.Item{ID: "6", ParentID: ptr("4"), Description: "Rehearse", Completed: &practiceDue},
+						todo.Item{ID: "7", ParentID: ptr("5"), Description: "Charts"},
+					))
+				},
+			},
+			Want: want{
+				Items: []todo.Item{
+					{ID: "1", Description: "Bananas"},
+					{
+						ID:          "4",
+						Description: "Prepare Presentation",
+						Progress:    &todo.Progress{Done: 1, Total: 2},
+						Subtasks: []todo.Item{
+							{
+								ID:          "5",
+								ParentID:    ptr("4"),
+								Description: "Slides",
+								Progress:    &todo.Progress{Done: 0, Total: 1},
+								Subtasks:    []todo.Item{{ID: "7", ParentID: ptr("5"), Description: "Charts"}},
+							},
+							{ID: "6", ParentID: ptr("4"), Description: "Rehearse", Completed: &practiceDue},
+						},
+					},
+				},
+			},
+		},
+		"Tree including archived items": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Tree: true, Include: todo.Include{Archived: true}}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND deleted IS NULL AND parent_id IS NULL", "position, id", "2", 11).WillReturnRows(mockItemRows(
+						todo.Item{ID: "4", Description: "Prepare Presentation", Archived: &practiceDue},
+					))
+					mockSubtasksQuery(mock, todo.Include{Archived: true}, "4").WillReturnRows(mockItemRows(
+						todo.Item{ID: "5", ParentID: ptr("4"), Description: "Slides", Archived: &practiceDue},
+					))
+				},
+			},
+			Want: want{
+				Items: []todo.Item{
+					{
+						ID:          "4",
+						Description: "Prepare Presentation",
+						Archived:    &practiceDue,
+						Subtasks:    []todo.Item{{ID: "5", ParentID: ptr("4"), Description: "Slides", Archived: &practiceDue}},
+					},
+				},
+			},
+		},
+		"Subtasks Query failure": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Tree: true}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND parent_id IS NULL", "position, id", "2", 11).WillReturnRows(mockItemRows(
+						todo.Item{ID: "4", Description: "Prepare Presentation"},
+					))
+					mockSubtasksQuery(mock, todo.Include{}, "4").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Key for a different sort": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Sort: todo.ItemSortDescription}, Page: todo.Page{After: `{"sort":"due","id":"6"}`, Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError(`page key is not valid for sort "description"`)},
+		},
+		"Deleted List": {
+			Args: args{ListID: "2", Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockHiddenList(mock, "2", false, true)
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"Archived List": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Include: todo.Include{Deleted: true}}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockHiddenList(mock, "2", true, false)
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"Archived List included": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Include: todo.Include{Archived: true}}, Page: todo.Page{Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockHiddenList(mock, "2", true, false)
+					mockItemsQueryWhere(mock, "list_id = $1 AND deleted IS NULL", "position, id", "2", 11).
+						WillReturnRows(mockItemRows(todo.Item{ID: "5", Description: "Practice", Archived: &practiceDue}))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "5", Description: "Practice", Archived: &practiceDue}}},
+		},
+		"Key with a tampered ID": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Sort: todo.ItemSortID}, Page: todo.Page{After: "2 OR true", Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError(`page key is not valid for sort "id"`)},
+		},
+		"Sort key with a tampered ID": {
+			Args: args{ListID: "2", Page: todo.Page{After: `{"sort":"position","id":"x","position":2048}`, Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError(`page key is not valid for sort "position"`)},
+		},
+		"Last page": {
+			Args: args{ListID: "2", Page: todo.Page{After: `{"sort":"position","id":"1","position":2048}`, Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND (position, id) > ($2, $3)", "position, id", "2", int64(2048), "1", 3).WillReturnRows(mockItemRows(
+						todo.Item{ID: "3", Description: "Strawberries", Position: 3072},
+					))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "3", Description: "Strawberries", Position: 3072}}},
+		},
+		"Created order, continuing from key": {
+			Args: args{ListID: "2", Filter: todo.ItemFilter{Sort: todo.ItemSortID}, Page: todo.Page{After: "2", Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL AND id > $2", "id", "2", "2", 3).WillReturnRows(mockItemRows(
+						todo.Item{ID: "3", Description: "Strawberries"},
+					))
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "3", Description: "Strawberries"}}},
+		},
 	}
 
 	for name, tt := range testTable {
@@ -83,11 +385,11 @@ func TestItems(t *testing.T) {
 			t.Parallel()
 
 			db, mock := mockDB(t)
-			repo := database.NewListRepository(db)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
 
 			tt.Fields.MockExpectations(mock)
 
-			items, err := repo.Items(context.Background(), tt.Args.ListID)
+			items, next, err := repo.Items(userContext(), tt.Args.ListID, tt.Args.Filter, tt.Args.Page)
 
 			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
 
@@ -98,6 +400,7 @@ func TestItems(t *testing.T) {
 
 			require.NoError(t, err, "Retrieval error")
 			assert.Equal(t, tt.Want.Items, items, "Items")
+			assert.Equal(t, tt.Want.Next, next, "Next")
 		})
 	}
 }
@@ -106,7 +409,9 @@ func TestList(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		ListID string
+		Horizon *time.Duration
+		Include todo.Include
+		ListID  string
 	}
 
 	type fields struct {
@@ -119,6 +424,7 @@ func TestList(t *testing.T) {
 	}
 
 	queryErr := errors.New("failed to execute query")
+	archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
 
 	testTable := map[string]struct {
 		Args   args
@@ -129,45 +435,90 @@ func TestList(t *testing.T) {
 			Args: args{ListID: "1"},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListQuery(mock, "1").WillReturnError(queryErr)
+					mockListAccess(mock, "1")
+					mockListQuery(mock, "1", todo.Include{}).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Access Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListRoleQuery(mock, "1").WillReturnError(queryErr)
 				},
 			},
 			Want: want{Error: queryErr},
 		},
+		"Owned by another User": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockNoListAccess(mock, "1")
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
 		"No Result": {
 			Args: args{ListID: "1"},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListQuery(mock, "1").WillReturnRows(mockListRows())
+					mockListAccess(mock, "1")
+					mockListQuery(mock, "1", todo.Include{}).WillReturnRows(mockListRows())
 				},
 			},
 			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
 		},
+		"Archived excluded": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockListQuery(mock, "2", todo.Include{}).WillReturnRows(mockListRows())
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"Archived included": {
+			Args: args{Include: todo.Include{Archived: true}, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockListQuery(mock, "2", todo.Include{Archived: true}).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023", Archived: &archived}))
+					mockItemsQueryDue(mock, "2").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{List: &todo.DueList{Role: todo.RoleOwner, Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023", Archived: &archived}}},
+		},
 		"Exists - No Items Due": {
 			Args: args{ListID: "2"},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListQuery(mock, "2").WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}))
-					mockItemsQueryDue(mock, "2").WillReturnRows(mockItemRows())
+					mockListAccess(mock, "2")
+					mockListQuery(mock, "2", todo.Include{}).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}))
+					mockItemsQueryDue(mock, "2").WillReturnRows(mockDueItemRows())
 				},
 			},
-			Want: want{List: &todo.DueList{List: todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}}},
+			Want: want{List: &todo.DueList{Role: todo.RoleOwner, Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}}},
 		},
 		"Exists - With Due Items": {
 			Args: args{ListID: "2"},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListQuery(mock, "2").WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}))
-					mockItemsQueryDue(mock, "2").WillReturnRows(mockItemRows(
-						todo.Item{ID: "1", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "2", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "3", Description: "Attend & Present", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))},
+					mockListAccess(mock, "2")
+					mockListQuery(mock, "2", todo.Include{}).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}))
+					mockItemsQueryDue(mock, "2").WillReturnRows(mockDueItemRows(
+						dueItem{"2", todo.Item{ID: "1", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))}},
+						dueItem{"2", todo.Item{ID: "2", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))}},
+						dueItem{"2", todo.Item{ID: "3", Description: "Attend & Present", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))}},
 					))
 				},
 			},
 			Want: want{
 				List: &todo.DueList{
-					List: todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"},
+					Role:    todo.RoleOwner,
+					Horizon: defaultHorizon,
+					List:    todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"},
 					DueItems: []todo.Item{
 						{ID: "1", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 						{ID: "2", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))},
@@ -176,6 +527,67 @@ func TestList(t *testing.T) {
 				},
 			},
 		},
+		"Exists - With Urgent Due Items": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "2")
+					mockListQuery(mock, "2", todo.Include{}).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"}))
+					mockItemsQueryDue(mock, "2").WillReturnRows(mockDueItemRows(
+						dueItem{"2", todo.Item{ID: "1", Description: "Book Venue", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityUrgent}},
+						dueItem{"2", todo.Item{ID: "2", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityHigh}},
+						dueItem{"2", todo.Item{ID: "3", Description: "Order Pizza", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityUrgent}},
+					))
+				},
+			},
+			Want: want{
+				List: &todo.DueList{
+					Role:    todo.RoleOwner,
+					Horizon: defaultHorizon,
+					List:    todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"},
+					DueItems: []todo.Item{
+						{ID: "1", Description: "Book Venue", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityUrgent},
+						{ID: "2", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityHigh},
+						{ID: "3", Description: "Order Pizza", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)), Priority: todo.PriorityUrgent},
+					},
+					UrgentDue: 2,
+				},
+			},
+		},
+		"List with its own Horizon": {
+			Args: args{ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "3")
+					mockListQuery(mock, "3", todo.Include{}).WillReturnRows(mockListRows(todo.List{ID: "3", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))}))
+					mockItemsQueryDue(mock, "3").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				List: &todo.DueList{
+					Role:    todo.RoleOwner,
+					Horizon: todo.Duration(7 * 24 * time.Hour),
+					List:    todo.List{ID: "3", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))},
+				},
+			},
+		},
+		"Horizon Override": {
+			Args: args{Horizon: ptr(90 * time.Minute), ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListAccess(mock, "3")
+					mockListQuery(mock, "3", todo.Include{}).WillReturnRows(mockListRows(todo.List{ID: "3", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))}))
+					mockItemsQueryDueWithin(mock, float64(5400), "3").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				List: &todo.DueList{
+					Role:    todo.RoleOwner,
+					Horizon: todo.Duration(90 * time.Minute),
+					List:    todo.List{ID: "3", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))},
+				},
+			},
+		},
 	}
 
 	for name, tt := range testTable {
@@ -185,11 +597,11 @@ func TestList(t *testing.T) {
 			t.Parallel()
 
 			db, mock := mockDB(t)
-			repo := database.NewListRepository(db)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
 
 			tt.Fields.MockExpectations(mock)
 
-			list, err := repo.List(context.Background(), tt.Args.ListID)
+			list, err := repo.List(userContext(), tt.Args.ListID, tt.Args.Horizon, tt.Args.Include)
 
 			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
 
@@ -207,6 +619,12 @@ func TestList(t *testing.T) {
 func TestLists(t *testing.T) {
 	t.Parallel()
 
+	type args struct {
+		Horizon *time.Duration
+		Include todo.Include
+		Page    todo.Page
+	}
+
 	type fields struct {
 		MockExpectations func(sqlmock.Sqlmock)
 	}
@@ -214,75 +632,146 @@ func TestLists(t *testing.T) {
 	type want struct {
 		Error error
 		Lists []todo.DueList
+		Next  string
 	}
 
 	queryErr := errors.New("failed to execute query")
+	archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+
+	var (
+		manyLists    []todo.List
+		manyListIDs  []string
+		manyDueLists []todo.DueList
+	)
+
+	for i := 1; i <= 5000; i++ {
+		l := todo.List{ID: strconv.Itoa(i), Description: "Chores"}
+		manyLists = append(manyLists, l)
+		manyListIDs = append(manyListIDs, l.ID)
+		manyDueLists = append(manyDueLists, todo.DueList{Horizon: defaultHorizon, List: l, Role: todo.RoleOwner})
+	}
+
+	manyDueLists[0].DueItems = []todo.Item{{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))}}
+	manyDueLists[4999].DueItems = []todo.Item{{ID: "2", Description: "Groceries", Due: ptr(time.Date(2023, time.June, 22, 2, 0, 0, 0, time.UTC))}}
+
+	firstPage := todo.Page{Limit: 5000}
 
 	testTable := map[string]struct {
+		Args   args
 		Fields fields
 		Want   want
 	}{
 		"Query failure": {
+			Args: args{Page: firstPage},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListsQuery(mock).WillReturnError(queryErr)
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnError(queryErr)
 				},
 			},
 			Want: want{Error: queryErr},
 		},
+		"Tampered Page Key": {
+			Args: args{Page: todo.Page{After: "abc", Limit: 10}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {},
+			},
+			Want: want{Error: todo.InvalidArgumentError("page key is not valid")},
+		},
 		"No Lists": {
+			Args: args{Page: firstPage},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows())
+				},
+			},
+		},
+		"Due Items Query failure": {
+			Args: args{Page: firstPage},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows(todo.List{ID: "1", Description: "Chores"}))
+					mockItemsQueryDue(mock, "1").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Including archived and deleted": {
+			Args: args{Include: todo.Include{Archived: true, Deleted: true}, Page: firstPage},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, firstPage, todo.Include{Archived: true, Deleted: true}).WillReturnRows(mockOwnedListRows(
+						todo.List{ID: "1", Description: "Chores", Archived: &archived},
+						todo.List{ID: "2", Description: "Holiday", Deleted: &archived},
+					))
+					mockItemsQueryDue(mock, "1", "2").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				Lists: []todo.DueList{
+					{Horizon: defaultHorizon, List: todo.List{ID: "1", Description: "Chores", Archived: &archived}, Role: todo.RoleOwner},
+					{Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Holiday", Deleted: &archived}, Role: todo.RoleOwner},
+				},
+			},
+		},
+		"Many lists with a fixed number of queries": {
+			Args: args{Page: firstPage},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListsQuery(mock).WillReturnRows(mockListRows())
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows(manyLists...))
+					// Any further queries beyond these two would fail as unexpected
+					mockItemsQueryDue(mock, manyListIDs...).WillReturnRows(mockDueItemRows(
+						dueItem{"1", todo.Item{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))}},
+						dueItem{"5000", todo.Item{ID: "2", Description: "Groceries", Due: ptr(time.Date(2023, time.June, 22, 2, 0, 0, 0, time.UTC))}},
+					))
 				},
 			},
+			Want: want{Lists: manyDueLists},
 		},
 		"Non-empty with no due items": {
+			Args: args{Page: firstPage},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListsQuery(mock).WillReturnRows(mockListRows(
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows(
 						todo.List{ID: "1", Description: "Chores"},
 						todo.List{ID: "2", Description: "Golang-Syd June 2023"},
 						todo.List{ID: "3", Description: "Holiday"},
 					))
-					mockItemsQueryDue(mock, "1").WillReturnRows(mockItemRows())
-					mockItemsQueryDue(mock, "2").WillReturnRows(mockItemRows())
-					mockItemsQueryDue(mock, "3").WillReturnRows(mockItemRows())
+					mockItemsQueryDue(mock, "1", "2", "3").WillReturnRows(mockDueItemRows())
 				},
 			},
 			Want: want{
 				Lists: []todo.DueList{
-					{List: todo.List{ID: "1", Description: "Chores"}},
-					{List: todo.List{ID: "2", Description: "Golang-Syd June 2023"}},
-					{List: todo.List{ID: "3", Description: "Holiday"}},
+					{Horizon: defaultHorizon, List: todo.List{ID: "1", Description: "Chores"}, Role: todo.RoleOwner},
+					{Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Golang-Syd June 2023"}, Role: todo.RoleOwner},
+					{Horizon: defaultHorizon, List: todo.List{ID: "3", Description: "Holiday"}, Role: todo.RoleOwner},
 				},
 			},
 		},
 		"Lists with due items": {
+			Args: args{Page: firstPage},
 			Fields: fields{
 				MockExpectations: func(mock sqlmock.Sqlmock) {
-					mockListsQuery(mock).WillReturnRows(mockListRows(
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows(
 						todo.List{ID: "1", Description: "Chores"},
 						todo.List{ID: "2", Description: "Golang-Syd June 2023"},
 						todo.List{ID: "3", Description: "Holiday"},
 					))
-					mockItemsQueryDue(mock, "1").WillReturnRows(mockItemRows(
-						todo.Item{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "2", Description: "Mop Floors", Due: ptr(time.Date(2023, time.June, 21, 10, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "3", Description: "Groceries", Due: ptr(time.Date(2023, time.June, 22, 2, 0, 0, 0, time.UTC))},
+					mockItemsQueryDue(mock, "1", "2", "3").WillReturnRows(mockDueItemRows(
+						dueItem{"1", todo.Item{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))}},
+						dueItem{"1", todo.Item{ID: "2", Description: "Mop Floors", Due: ptr(time.Date(2023, time.June, 21, 10, 0, 0, 0, time.UTC))}},
+						dueItem{"1", todo.Item{ID: "3", Description: "Groceries", Due: ptr(time.Date(2023, time.June, 22, 2, 0, 0, 0, time.UTC))}},
+						dueItem{"2", todo.Item{ID: "4", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))}},
+						dueItem{"2", todo.Item{ID: "5", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))}},
+						dueItem{"2", todo.Item{ID: "6", Description: "Attend & Present", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))}},
 					))
-					mockItemsQueryDue(mock, "2").WillReturnRows(mockItemRows(
-						todo.Item{ID: "4", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "5", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))},
-						todo.Item{ID: "6", Description: "Attend & Present", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))},
-					))
-					mockItemsQueryDue(mock, "3").WillReturnRows(mockItemRows())
 				},
 			},
 			Want: want{
 				Lists: []todo.DueList{
 					{
-						List: todo.List{ID: "1", Description: "Chores"},
+						Horizon: defaultHorizon,
+						List:    todo.List{ID: "1", Description: "Chores"},
+						Role:    todo.RoleOwner,
 						DueItems: []todo.Item{
 							{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 							{ID: "2", Description: "Mop Floors", Due: ptr(time.Date(2023, time.June, 21, 10, 0, 0, 0, time.UTC))},
@@ -290,119 +779,690 @@ func TestLists(t *testing.T) {
 						},
 					},
 					{
-						List: todo.List{ID: "2", Description: "Golang-Syd June 2023"},
+						Horizon: defaultHorizon,
+						List:    todo.List{ID: "2", Description: "Golang-Syd June 2023"},
+						Role:    todo.RoleOwner,
 						DueItems: []todo.Item{
 							{ID: "4", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 							{ID: "5", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))},
 							{ID: "6", Description: "Attend & Present", Due: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))},
 						},
 					},
-					{List: todo.List{ID: "3", Description: "Holiday"}},
+					{Horizon: defaultHorizon, List: todo.List{ID: "3", Description: "Holiday"}, Role: todo.RoleOwner},
 				},
 			},
 		},
-	}
-
-	for name, tt := range testTable {
-		tt := tt
-
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			db, mock := mockDB(t)
-			repo := database.NewListRepository(db)
-
-			tt.Fields.MockExpectations(mock)
-
-			lists, err := repo.Lists(context.Background())
-
-			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
-
-			if tt.Want.Error != nil {
-				assert.ErrorIs(t, err, tt.Want.Error, "Retrieval error")
-				return
-			}
-
-			require.NoError(t, err, "Retrieval error")
-			assert.Equal(t, tt.Want.Lists, lists, "Lists")
-		})
-	}
-}
+		"Owned and Shared Lists": {
+			Args: args{Page: firstPage},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "description", "due_horizon", "archived", "deleted", "role", "shared"}).
+							AddRow("1", "Chores", nil, nil, nil, "owner", false).
+							AddRow("2", "Groceries", nil, nil, nil, "editor", true).
+							AddRow("3", "Holiday", nil, nil, nil, "viewer", true),
+					)
+					mockItemsQueryDue(mock, "1", "2", "3").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				Lists: []todo.DueList{
+					{Horizon: defaultHorizon, List: todo.List{ID: "1", Description: "Chores"}, Role: todo.RoleOwner},
+					{Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Groceries"}, Role: todo.RoleEditor, Shared: true},
+					{Horizon: defaultHorizon, List: todo.List{ID: "3", Description: "Holiday"}, Role: todo.RoleViewer, Shared: true},
+				},
+			},
+		},
+		"Horizon Override": {
+			Args: args{Horizon: ptr(time.Duration(0)), Page: firstPage},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, firstPage, todo.Include{}).WillReturnRows(mockOwnedListRows(
+						todo.List{ID: "1", Description: "Chores"},
+						todo.List{ID: "2", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))},
+					))
+					mockItemsQueryDueWithin(mock, float64(0), "1", "2").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				Lists: []todo.DueList{
+					{List: todo.List{ID: "1", Description: "Chores"}, Role: todo.RoleOwner},
+					{List: todo.List{ID: "2", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))}, Role: todo.RoleOwner},
+				},
+			},
+		},
+		"Page with more": {
+			Args: args{Page: todo.Page{After: "1", Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListsQuery(mock, todo.Page{After: "1", Limit: 2}, todo.Include{}).WillReturnRows(mockOwnedListRows(
+						todo.List{ID: "2", Description: "Golang-Syd June 2023"},
+						todo.List{ID: "3", Description: "Holiday"},
+						todo.List{ID: "4", Description: "Moving House"},
+					))
+					// Due items are only retrieved for lists within the page
+					mockItemsQueryDue(mock, "2", "3").WillReturnRows(mockDueItemRows())
+				},
+			},
+			Want: want{
+				Lists: []todo.DueList{
+					{Horizon: defaultHorizon, List: todo.List{ID: "2", Description: "Golang-Syd June 2023"}, Role: todo.RoleOwner},
+					{Horizon: defaultHorizon, List: todo.List{ID: "3", Description: "Holiday"}, Role: todo.RoleOwner},
+				},
+				Next: "3",
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			lists, next, err := repo.Lists(userContext(), tt.Args.Page, tt.Args.Horizon, tt.Args.Include)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Retrieval error")
+				return
+			}
+
+			require.NoError(t, err, "Retrieval error")
+			assert.Equal(t, tt.Want.Lists, lists, "Lists")
+			assert.Equal(t, tt.Want.Next, next, "Next")
+		})
+	}
+}
+
+func TestCreateList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		List todo.List
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		List  *todo.List
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{List: todo.List{Description: "Chores"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListQuery(mock, "Chores", nil).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Created": {
+			Args: args{List: todo.List{Description: "Chores"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListQuery(mock, "Chores", nil).WillReturnRows(mockListRows(todo.List{ID: "5001", Description: "Chores"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "5001", Description: "Chores"}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			list, err := repo.CreateList(userContext(), tt.Args.List)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Creation error")
+				return
+			}
+
+			require.NoError(t, err, "Creation error")
+			assert.Equal(t, tt.Want.List, list, "List")
+		})
+	}
+}
+
+func TestUpdateList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+		Update todo.ListUpdate
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		List  *todo.List
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ListID: "1", Update: todo.ListUpdate{Description: ptr("Chores")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateListQuery(mock, "1", ptr("Chores"), false, nil).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"No Result": {
+			Args: args{ListID: "1", Update: todo.ListUpdate{Description: ptr("Chores")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUpdateListQuery(mock, "1", ptr("Chores"), false, nil).WillReturnRows(mockListRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Horizon Cleared": {
+			Args: args{ListID: "2", Update: todo.ListUpdate{SetDueHorizon: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockUpdateListQuery(mock, "2", nil, true, nil).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Holiday"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "2", Description: "Holiday"}},
+		},
+		"Updated": {
+			Args: args{ListID: "2", Update: todo.ListUpdate{Description: ptr("Holiday")}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockUpdateListQuery(mock, "2", ptr("Holiday"), false, nil).WillReturnRows(mockListRows(todo.List{ID: "2", Description: "Holiday"}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "2", Description: "Holiday"}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			list, err := repo.UpdateList(userContext(), tt.Args.ListID, tt.Args.Update)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Update error")
+				return
+			}
+
+			require.NoError(t, err, "Update error")
+			assert.Equal(t, tt.Want.List, list, "List")
+		})
+	}
+}
+
+func TestDeleteList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteListQuery(mock, "1").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Owned by another User": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockNoListAccess(mock, "1")
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Shared with the User as an Editor": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListRole(mock, "1", todo.RoleEditor)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.ForbiddenError(`owner role on list "1" is required`)},
+		},
+		"No Result": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockDeleteListQuery(mock, "1").WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Deleted": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockDeleteListQuery(mock, "2").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
-func mockItemsQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			err := repo.DeleteList(userContext(), tt.Args.ListID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Deletion error")
+				return
+			}
+
+			require.NoError(t, err, "Deletion error")
+		})
+	}
+}
+
+// mockItemsQuery for the first page of items, in the default order of their position.
+func mockItemsQuery(mock sqlmock.Sqlmock, listID string, page todo.Page) *sqlmock.ExpectedQuery {
+	return mockItemsQueryWhere(mock, "list_id = $1 AND archived IS NULL AND deleted IS NULL", "position, id", listID, page.Limit+1)
+}
+
+func mockItemsQueryWhere(mock sqlmock.Sqlmock, where, orderBy string, args ...driver.Value) *sqlmock.ExpectedQuery {
 	q := `
 		-- Name: TODO List Items
 		SELECT id,
 		       description,
 		       due,
-		       completed
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
 		  FROM items
-		 WHERE list_id = $1
+		 WHERE ` + where + `
+		 ORDER BY ` + orderBy + `
+		 LIMIT $` + strconv.Itoa(len(args)) + `
 	`
 
-	return mock.ExpectQuery(q).WithArgs(listID)
+	return mock.ExpectQuery(q).WithArgs(args...)
 }
 
-func mockItemsQueryDue(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+func mockSubtasksQuery(mock sqlmock.Sqlmock, include todo.Include, itemIDs ...string) *sqlmock.ExpectedQuery {
 	q := `
-		-- Name: TODO Due List Items
+		-- Name: TODO List Item Subtasks
+		WITH RECURSIVE subtasks AS (
+		     SELECT id
+		       FROM items
+		      WHERE parent_id = ANY($1::int[])
+		        AND ($2::boolean OR archived IS NULL)
+		        AND ($3::boolean OR deleted IS NULL)
+		      UNION ALL
+		     SELECT i.id
+		       FROM items i
+		       JOIN subtasks s ON i.parent_id = s.id
+		      WHERE ($2::boolean OR i.archived IS NULL)
+		        AND ($3::boolean OR i.deleted IS NULL)
+		)
 		SELECT id,
 		       description,
 		       due,
-		       completed
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
 		  FROM items
-		 WHERE list_id = $1
-		   AND due <= now() + INTERVAL '1 day'
-		   AND completed IS NULL
-		 ORDER BY due
+		 WHERE id IN (SELECT id FROM subtasks)
+		 ORDER BY position, id
 	`
 
-	return mock.ExpectQuery(q).WithArgs(listID)
+	return mock.ExpectQuery(q).WithArgs(pq.Array(itemIDs), include.Archived, include.Deleted)
+}
+
+func mockItemsQueryDue(mock sqlmock.Sqlmock, listIDs ...string) *sqlmock.ExpectedQuery {
+	return mockItemsQueryDueWithin(mock, nil, listIDs...)
+}
+
+func mockItemsQueryDueWithin(mock sqlmock.Sqlmock, horizonSecs any, listIDs ...string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO Due List Items
+		WITH RECURSIVE subtree AS (
+		     SELECT id AS root_id,
+		            id,
+		            due
+		       FROM items
+		      WHERE list_id = ANY($1::int[])
+		        AND parent_id IS NULL
+		        AND completed IS NULL
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION ALL
+		     SELECT s.root_id,
+		            c.id,
+		            c.due
+		       FROM items c
+		       JOIN subtree s ON c.parent_id = s.id
+		      WHERE c.completed IS NULL
+		        AND c.archived IS NULL
+		        AND c.deleted IS NULL
+		),
+		effective AS (
+		     SELECT root_id AS item_id,
+		            min(due) AS effective_due
+		       FROM subtree
+		      GROUP BY root_id
+		)
+		SELECT list_id,
+		       id,
+		       description,
+		       due,
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
+		  FROM items
+		  JOIN effective e ON e.item_id = items.id
+		 WHERE e.effective_due <= now() + COALESCE(make_interval(secs => $2), (SELECT l.due_horizon FROM lists l WHERE l.id = items.list_id), make_interval(secs => $3))
+		 ORDER BY list_id, e.effective_due, priority DESC
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pq.Array(listIDs), horizonSecs, time.Duration(defaultHorizon).Seconds())
+}
+
+// dueItem of a specific list, as returned when querying for due items of many lists at once.
+type dueItem struct {
+	ListID string
+	Item   todo.Item
+}
+
+func mockDueItemRows(items ...dueItem) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"list_id", "id", "description", "due", "completed", "priority", "recurrence", "tags", "parent_id", "auto_complete", "progress", "position", "archived", "deleted"})
+
+	for _, di := range items {
+		i := di.Item
+		rows.AddRow(di.ListID, i.ID, i.Description, i.Due, i.Completed, int64(i.Priority), recurrenceValue(i.Recurrence), tagsValue(i.Tags), i.ParentID, i.AutoComplete, progressValue(i.Progress), i.Position, i.Archived, i.Deleted)
+	}
+
+	return rows
 }
 
 func mockItemRows(items ...todo.Item) *sqlmock.Rows {
-	rows := sqlmock.NewRows([]string{"id", "description", "due", "completed"})
+	rows := sqlmock.NewRows([]string{"id", "description", "due", "completed", "priority", "recurrence", "tags", "parent_id", "auto_complete", "progress", "position", "archived", "deleted"})
 
-	for _, item := range items {
-		rows.AddRow(item.ID, item.Description, item.Due, item.Completed)
+	for _, i := range items {
+		rows.AddRow(i.ID, i.Description, i.Due, i.Completed, int64(i.Priority), recurrenceValue(i.Recurrence), tagsValue(i.Tags), i.ParentID, i.AutoComplete, progressValue(i.Progress), i.Position, i.Archived, i.Deleted)
 	}
 
 	return rows
 }
 
-func mockListQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+// recurrenceValue of the recurrence as Postgres would return it, as an RRULE.
+func recurrenceValue(r *todo.Recurrence) driver.Value {
+	if r == nil {
+		return nil
+	}
+
+	return r.String()
+}
+
+// tagsValue of the tags as Postgres would return them, as an array literal.
+func tagsValue(tags todo.Tags) driver.Value {
+	v, _ := pq.StringArray(tags).Value()
+	return v
+}
+
+// progressValue of the progress as Postgres would return it, as an array of the number done and the total.
+func progressValue(p *todo.Progress) driver.Value {
+	if p == nil {
+		return "{0,0}"
+	}
+
+	return fmt.Sprintf("{%d,%d}", p.Done, p.Total)
+}
+
+func mockListQuery(mock sqlmock.Sqlmock, listID string, include todo.Include) *sqlmock.ExpectedQuery {
 	q := `
 		-- Name: TODO List
 		SELECT id,
-		       description
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon),
+		       archived,
+		       deleted
 		  FROM lists
 		 WHERE id = $1
+		   AND ($2::boolean OR archived IS NULL)
+		   AND ($3::boolean OR deleted IS NULL)
 	`
 
-	return mock.ExpectQuery(q).WithArgs(listID)
+	return mock.ExpectQuery(q).WithArgs(listID, include.Archived, include.Deleted)
 }
 
-func mockListsQuery(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+func mockListsQuery(mock sqlmock.Sqlmock, page todo.Page, include todo.Include) *sqlmock.ExpectedQuery {
 	q := `
 		-- Name: TODO Lists
-		SELECT id,
-		       description
-		  FROM lists
+		SELECT l.id,
+		       l.description,
+		       EXTRACT(EPOCH FROM l.due_horizon),
+		       l.archived,
+		       l.deleted,
+		       CASE WHEN l.owner_id = $5 THEN 'owner' ELSE m.role END,
+		       l.owner_id <> $5
+		  FROM lists l
+		  LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $5
+		 WHERE ($1::int IS NULL OR l.id > $1)
+		   AND ($3::boolean OR l.archived IS NULL)
+		   AND ($4::boolean OR l.deleted IS NULL)
+		   AND (l.owner_id = $5 OR m.user_id IS NOT NULL)
+		 ORDER BY l.id
+		 LIMIT $2
 	`
 
-	return mock.ExpectQuery(q)
+	return mock.ExpectQuery(q).WithArgs(pageAfter(page), page.Limit+1, include.Archived, include.Deleted, testUser.ID)
+}
+
+// pageAfter as the query argument expected for the page, which is NULL for the first page.
+func pageAfter(page todo.Page) any {
+	if page.After == "" {
+		return nil
+	}
+
+	return page.After
 }
 
 func mockListRows(lists ...todo.List) *sqlmock.Rows {
-	rows := sqlmock.NewRows([]string{"id", "description"})
+	rows := sqlmock.NewRows([]string{"id", "description", "due_horizon", "archived", "deleted"})
 
 	for _, list := range lists {
-		rows.AddRow(list.ID, list.Description)
+		var horizon any
+		if list.DueHorizon != nil {
+			horizon = []byte(strconv.FormatFloat(time.Duration(*list.DueHorizon).Seconds(), 'f', -1, 64))
+		}
+
+		rows.AddRow(list.ID, list.Description, horizon, list.Archived, list.Deleted)
 	}
 
 	return rows
 }
 
+// mockOwnedListRows of lists which are owned by the test user.
+func mockOwnedListRows(lists ...todo.List) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "description", "due_horizon", "archived", "deleted", "role", "shared"})
+
+	for _, list := range lists {
+		var horizon any
+		if list.DueHorizon != nil {
+			horizon = []byte(strconv.FormatFloat(time.Duration(*list.DueHorizon).Seconds(), 'f', -1, 64))
+		}
+
+		rows.AddRow(list.ID, list.Description, horizon, list.Archived, list.Deleted, todo.RoleOwner, false)
+	}
+
+	return rows
+}
+
+func mockCreateListQuery(mock sqlmock.Sqlmock, description string, dueHorizonSecs any) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Create TODO List
+		INSERT INTO lists (owner_id, description, due_horizon)
+		VALUES ($1, $2, make_interval(secs => $3))
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(testUser.ID, description, dueHorizonSecs)
+}
+
+func mockUpdateListQuery(mock sqlmock.Sqlmock, listID string, description *string, setDueHorizon bool, dueHorizonSecs any) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Update TODO List
+		UPDATE lists
+		   SET description = COALESCE($2, description),
+		       due_horizon = CASE WHEN $3::boolean THEN make_interval(secs => $4) ELSE due_horizon END
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID, description, setDueHorizon, dueHorizonSecs)
+}
+
+func mockDeleteListQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Delete TODO List
+		UPDATE lists
+		   SET deleted = COALESCE(deleted, now())
+		 WHERE id = $1
+	`
+
+	return mock.ExpectExec(q).WithArgs(listID)
+}
+
+// defaultHorizon of the repository under test, for lists without their own horizon.
+const defaultHorizon = todo.Duration(24 * time.Hour)
+
+// maxSubtaskDepth of the repository under test.
+const maxSubtaskDepth = 2
+
 func mockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	require.NoError(t, err, "Opening a stub database connection encountered an error")
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// Members of a TODO list, being its owner and those it has been shared with, ordered by their roles and then names.
+func (r *ListRepository) Members(ctx context.Context, listID string) ([]todo.Member, error) {
+	query := `
+		-- Name: TODO List Members
+		SELECT id,
+		       name,
+		       role
+		  FROM (
+		        SELECT u.id,
+		               u.name,
+		               'owner' AS role
+		          FROM lists l
+		          JOIN users u ON u.id = l.owner_id
+		         WHERE l.id = $1
+		        UNION ALL
+		        SELECT u.id,
+		               u.name,
+		               m.role
+		          FROM list_members m
+		          JOIN users u ON u.id = m.user_id
+		         WHERE m.list_id = $1
+		       ) members
+		 ORDER BY array_position(ARRAY['owner', 'editor', 'viewer'], role), name
+	`
+
+	if err := r.checkAccess(ctx, r.db, listID, todo.RoleViewer, hiddenLists); err != nil {
+		return nil, err
+	}
+
+	members, err := queryRows(ctx, r.db, memberCols, query, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query members of todo list %q: %w", listID, err)
+	}
+
+	return members, nil
+}
+
+// AddMember to a TODO list with the role, by their name, or change the role of an existing member. Users are
+// provisioned when they have not been seen before, so that lists can be shared with them before they first sign in.
+func (r *ListRepository) AddMember(ctx context.Context, listID, name string, role todo.Role) (*todo.Member, error) {
+	// The owner of the list is excluded, as they must always remain its owner
+	query := `
+		-- Name: Add TODO List Member
+		INSERT INTO list_members (list_id, user_id, role)
+		SELECT id,
+		       $2,
+		       $3
+		  FROM lists
+		 WHERE id = $1
+		   AND owner_id <> $2
+		ON CONFLICT (list_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	var member *todo.Member
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleOwner, hiddenLists); err != nil {
+			return err
+		}
+
+		user, err := provisionUser(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, listID, user.ID, role)
+		if err != nil {
+			return fmt.Errorf("failed to add member %q to todo list %q: %w", name, listID, err)
+		}
+
+		if err := expectAffected(res, todo.InvalidArgumentError(fmt.Sprintf("user %q already owns list %q", name, listID))); err != nil {
+			return err
+		}
+
+		member = &todo.Member{User: *user, Role: role}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember from a TODO list, by their name. Members may always remove themselves, while only owners may remove
+// others. The owner of the list cannot be removed.
+func (r *ListRepository) RemoveMember(ctx context.Context, listID, name string) error {
+	query := `
+		-- Name: Remove TODO List Member
+		DELETE FROM list_members m
+		 USING users u
+		 WHERE m.list_id = $1
+		   AND m.user_id = u.id
+		   AND u.name = $2
+	`
+
+	required := todo.RoleOwner
+	if user, ok := todo.UserFromContext(ctx); ok && user.Name == name {
+		required = todo.RoleViewer
+	}
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, required, hiddenLists); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, listID, name)
+		if err != nil {
+			return fmt.Errorf("failed to remove member %q from todo list %q: %w", name, listID, err)
+		}
+
+		return expectAffected(res, todo.NotFoundError(fmt.Sprintf("user %q is not a member of list %q", name, listID)))
+	})
+}
+
+// checkAccess of the authenticated user to a TODO list, where their role must allow what the required role does. Lists
+// which have not been shared with the user are not found, so that their existence is not revealed. Archived and deleted
+// lists are not found either, unless included, so that their items are hidden and cannot be changed along with them.
+func (r *ListRepository) checkAccess(ctx context.Context, db rowQuerier, listID string, required todo.Role, include todo.Include) error {
+	access, err := r.listAccess(ctx, db, listID)
+	if err != nil {
+		return err
+	}
+
+	if (access.Archived && !include.Archived) || (access.Deleted && !include.Deleted) {
+		return todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
+	}
+
+	if !access.Role.Allows(required) {
+		return todo.ForbiddenError(fmt.Sprintf("%s role on list %q is required", required, listID))
+	}
+
+	return nil
+}
+
+// hiddenLists which are included when managing lists themselves, rather than their items, such as restoring them.
+var hiddenLists = todo.Include{Archived: true, Deleted: true}
+
+// listAccess of the authenticated user to a TODO list, as either its owner or a member, along with whether the list is
+// archived or deleted.
+type listAccess struct {
+	Role     todo.Role
+	Archived bool
+	Deleted  bool
+}
+
+// listAccess of the authenticated user to a TODO list.
+func (r *ListRepository) listAccess(ctx context.Context, db rowQuerier, listID string) (*listAccess, error) {
+	query := `
+		-- Name: TODO List Role
+		SELECT CASE WHEN l.owner_id = $2 THEN 'owner' ELSE m.role END,
+		       l.archived IS NOT NULL,
+		       l.deleted IS NOT NULL
+		  FROM lists l
+		  LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $2
+		 WHERE l.id = $1
+	`
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var role sql.NullString
+
+	cols := func(a *listAccess) []any {
+		return []any{&role, &a.Archived, &a.Deleted}
+	}
+
+	access, err := queryRow(ctx, db, cols, query, listID, uid)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !role.Valid) {
+		return nil, todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role on todo list %q: %w", listID, err)
+	}
+
+	access.Role = todo.Role(role.String)
+
+	return access, nil
+}
+
+func memberCols(m *todo.Member) []any {
+	return []any{&m.User.ID, &m.User.Name, &m.Role}
+}
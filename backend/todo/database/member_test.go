@@ -0,0 +1,430 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestListAccess(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	repo := database.NewListRepository(db, 0, nil, maxSubtaskDepth)
+
+	// Without an authenticated user, nothing is queried
+	_, err := repo.List(context.Background(), "1", nil, todo.Include{})
+
+	assert.ErrorIs(t, err, todo.UnauthenticatedError("no user is authenticated"), "Unauthenticated error")
+	assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+}
+
+func TestMembers(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error   error
+		Members []todo.Member
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Not Shared with the User": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockNoListAccess(mock, "1")
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "1" does not exist`)},
+		},
+		"Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListRole(mock, "1", todo.RoleViewer)
+					mockMembersQuery(mock, "1").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Members": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockListRole(mock, "1", todo.RoleViewer)
+					mockMembersQuery(mock, "1").WillReturnRows(mockMemberRows(
+						todo.Member{User: todo.User{ID: "7", Name: "bob"}, Role: todo.RoleOwner},
+						todo.Member{User: todo.User{ID: "42", Name: "alice"}, Role: todo.RoleViewer},
+					))
+				},
+			},
+			Want: want{
+				Members: []todo.Member{
+					{User: todo.User{ID: "7", Name: "bob"}, Role: todo.RoleOwner},
+					{User: todo.User{ID: "42", Name: "alice"}, Role: todo.RoleViewer},
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			members, err := repo.Members(userContext(), tt.Args.ListID)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Members error")
+				return
+			}
+
+			require.NoError(t, err, "Members error")
+			assert.Equal(t, tt.Want.Members, members, "Members")
+		})
+	}
+}
+
+func TestAddMember(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+		Name   string
+		Role   todo.Role
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error  error
+		Member *todo.Member
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Shared with the User as an Editor": {
+			Args: args{ListID: "1", Name: "bob", Role: todo.RoleViewer},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListRole(mock, "1", todo.RoleEditor)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.ForbiddenError(`owner role on list "1" is required`)},
+		},
+		"Query failure": {
+			Args: args{ListID: "1", Name: "bob", Role: todo.RoleViewer},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockProvisionUserQuery(mock, "bob").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "bob"))
+					mockAddMemberQuery(mock, "1", "7", todo.RoleViewer).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Owner of the List": {
+			Args: args{ListID: "1", Name: "alice", Role: todo.RoleViewer},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockProvisionUserQuery(mock, "alice").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(testUser.ID, "alice"))
+					mockAddMemberQuery(mock, "1", testUser.ID, todo.RoleViewer).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.InvalidArgumentError(`user "alice" already owns list "1"`)},
+		},
+		"Added": {
+			Args: args{ListID: "1", Name: "bob", Role: todo.RoleEditor},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockProvisionUserQuery(mock, "bob").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "bob"))
+					mockAddMemberQuery(mock, "1", "7", todo.RoleEditor).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Member: &todo.Member{User: todo.User{ID: "7", Name: "bob"}, Role: todo.RoleEditor}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			member, err := repo.AddMember(userContext(), tt.Args.ListID, tt.Args.Name, tt.Args.Role)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Add error")
+				return
+			}
+
+			require.NoError(t, err, "Add error")
+			assert.Equal(t, tt.Want.Member, member, "Member")
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+		Name   string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Others by an Editor": {
+			Args: args{ListID: "1", Name: "bob"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListRole(mock, "1", todo.RoleEditor)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.ForbiddenError(`owner role on list "1" is required`)},
+		},
+		"Query failure": {
+			Args: args{ListID: "1", Name: "bob"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRemoveMemberQuery(mock, "1", "bob").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Not a Member": {
+			Args: args{ListID: "1", Name: "bob"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRemoveMemberQuery(mock, "1", "bob").WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`user "bob" is not a member of list "1"`)},
+		},
+		"Removed by the Owner": {
+			Args: args{ListID: "1", Name: "bob"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockRemoveMemberQuery(mock, "1", "bob").WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+		"Removed Themselves": {
+			Args: args{ListID: "1", Name: testUser.Name},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListRole(mock, "1", todo.RoleViewer)
+					mockRemoveMemberQuery(mock, "1", testUser.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectCommit()
+				},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			err := repo.RemoveMember(userContext(), tt.Args.ListID, tt.Args.Name)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Remove error")
+				return
+			}
+
+			require.NoError(t, err, "Remove error")
+		})
+	}
+}
+
+func mockMembersQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Members
+		SELECT id,
+		       name,
+		       role
+		  FROM (
+		        SELECT u.id,
+		               u.name,
+		               'owner' AS role
+		          FROM lists l
+		          JOIN users u ON u.id = l.owner_id
+		         WHERE l.id = $1
+		        UNION ALL
+		        SELECT u.id,
+		               u.name,
+		               m.role
+		          FROM list_members m
+		          JOIN users u ON u.id = m.user_id
+		         WHERE m.list_id = $1
+		       ) members
+		 ORDER BY array_position(ARRAY['owner', 'editor', 'viewer'], role), name
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
+
+func mockMemberRows(members ...todo.Member) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "name", "role"})
+
+	for _, m := range members {
+		rows.AddRow(m.User.ID, m.User.Name, m.Role)
+	}
+
+	return rows
+}
+
+func mockAddMemberQuery(mock sqlmock.Sqlmock, listID, userID string, role todo.Role) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Add TODO List Member
+		INSERT INTO list_members (list_id, user_id, role)
+		SELECT id,
+		       $2,
+		       $3
+		  FROM lists
+		 WHERE id = $1
+		   AND owner_id <> $2
+		ON CONFLICT (list_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	return mock.ExpectExec(q).WithArgs(listID, userID, role)
+}
+
+func mockRemoveMemberQuery(mock sqlmock.Sqlmock, listID, name string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Remove TODO List Member
+		DELETE FROM list_members m
+		 USING users u
+		 WHERE m.list_id = $1
+		   AND m.user_id = u.id
+		   AND u.name = $2
+	`
+
+	return mock.ExpectExec(q).WithArgs(listID, name)
+}
+
+func mockListRoleQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Role
+		SELECT CASE WHEN l.owner_id = $2 THEN 'owner' ELSE m.role END,
+		       l.archived IS NOT NULL,
+		       l.deleted IS NOT NULL
+		  FROM lists l
+		  LEFT JOIN list_members m ON m.list_id = l.id AND m.user_id = $2
+		 WHERE l.id = $1
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID, testUser.ID)
+}
+
+// mockListAccess of the test user to a list which they own.
+func mockListAccess(mock sqlmock.Sqlmock, listID string) {
+	mockListRole(mock, listID, todo.RoleOwner)
+}
+
+// mockListRole of the test user on a list which they own, or which has been shared with them.
+func mockListRole(mock sqlmock.Sqlmock, listID string, role todo.Role) {
+	mockListRoleQuery(mock, listID).WillReturnRows(mockListRoleRows().AddRow(string(role), false, false))
+}
+
+// mockHiddenList owned by the test user, which is archived or deleted.
+func mockHiddenList(mock sqlmock.Sqlmock, listID string, archived, deleted bool) {
+	mockListRoleQuery(mock, listID).WillReturnRows(mockListRoleRows().AddRow(string(todo.RoleOwner), archived, deleted))
+}
+
+// mockNoListAccess of the test user to a list which has not been shared with them, or which does not exist.
+func mockNoListAccess(mock sqlmock.Sqlmock, listID string) {
+	mockListRoleQuery(mock, listID).WillReturnRows(mockListRoleRows().AddRow(nil, false, false))
+}
+
+func mockListRoleRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"role", "archived", "deleted"})
+}
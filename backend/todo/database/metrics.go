@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// queryNameKey of the attribute with the name of each query, from its `-- Name:` comment.
+const queryNameKey = "db.query.name"
+
+// MeteredConnector which records the latency of queries by their names, taken from the `-- Name:` comment which each
+// query starts with. Queries without a name are recorded as "unnamed". Prepared statements are not recorded, as the
+// repositories never prepare them.
+func MeteredConnector(c driver.Connector, mp metric.MeterProvider) (driver.Connector, error) {
+	meter := mp.Meter("github.com/dackroyd/todo-list/backend/todo/database")
+
+	latency, err := meter.Float64Histogram("db.client.query.duration", metric.WithUnit("ms"), metric.WithDescription("Duration of executing queries, by their names"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &meteredConnector{Connector: c, latency: latency}, nil
+}
+
+type meteredConnector struct {
+	driver.Connector
+	latency metric.Float64Histogram
+}
+
+func (c *meteredConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &meteredConn{Conn: conn, latency: c.latency}, nil
+}
+
+// meteredConn records the latency of queries, and otherwise passes everything through to the driver's connection.
+type meteredConn struct {
+	driver.Conn
+	latency metric.Float64Histogram
+}
+
+func (c *meteredConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	defer c.record(ctx, "query", query, time.Now())
+
+	return q.QueryContext(ctx, query, args)
+}
+
+func (c *meteredConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	defer c.record(ctx, "exec", query, time.Now())
+
+	return e.ExecContext(ctx, query, args)
+}
+
+func (c *meteredConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+
+	return c.Conn.Prepare(query)
+}
+
+func (c *meteredConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+
+	// Drivers which predate transaction options only support the defaults, which database/sql also falls back to, so
+	// other options are refused rather than silently ignored
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("driver does not support non-default isolation levels")
+	}
+
+	if opts.ReadOnly {
+		return nil, errors.New("driver does not support read-only transactions")
+	}
+
+	return c.Conn.Begin()
+}
+
+func (c *meteredConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *meteredConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *meteredConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c *meteredConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+func (c *meteredConn) record(ctx context.Context, operation, query string, start time.Time) {
+	dur := time.Since(start)
+
+	c.latency.Record(ctx, float64(dur)/float64(time.Millisecond), metric.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperation(operation),
+		attribute.String(queryNameKey, queryName(query)),
+	))
+}
+
+// queryName from the `-- Name:` comment which the query starts with.
+func queryName(query string) string {
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(line, "-- Name:"); ok {
+			return strings.TrimSpace(name)
+		}
+
+		break
+	}
+
+	return "unnamed"
+}
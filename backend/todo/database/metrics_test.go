@@ -0,0 +1,161 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestMeteredConnector(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// DSNs remain registered with the stub driver, so must be unique when tests are repeated
+	dsn := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+
+	stub, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err, "Opening a stub database connection encountered an error")
+
+	t.Cleanup(func() {
+		mock.ExpectClose()
+		assert.NoError(t, stub.Close(), "Closing mock DB")
+	})
+
+	conn, err := database.MeteredConnector(dsnConnector{dsn: dsn, driver: stub.Driver()}, mp)
+	require.NoError(t, err, "Connector Error")
+
+	// The connection of the stub is shared, so is only closed by the stub
+	db := sql.OpenDB(conn)
+
+	repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+	mockListRole(mock, "1", todo.RoleViewer)
+	mockMembersQuery(mock, "1").WillReturnRows(mockMemberRows())
+	mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = repo.Members(userContext(), "1")
+	require.NoError(t, err, "Members error")
+
+	_, err = db.ExecContext(context.Background(), "SELECT 1")
+	require.NoError(t, err, "Exec error")
+
+	assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm), "Collect error")
+
+	require.Len(t, rm.ScopeMetrics, 1, "Scopes")
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1, "Metrics")
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "db.client.query.duration", m.Name, "Metric Name")
+	assert.Equal(t, "ms", m.Unit, "Metric Unit")
+
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok, "Histogram of type %T", m.Data)
+
+	counts := map[string]uint64{}
+
+	for _, dp := range hist.DataPoints {
+		name, _ := dp.Attributes.Value(attribute.Key("db.query.name"))
+		op, _ := dp.Attributes.Value(attribute.Key("db.operation"))
+
+		counts[op.AsString()+": "+name.AsString()] += dp.Count
+	}
+
+	want := map[string]uint64{
+		"query: TODO List Role":    1,
+		"query: TODO List Members": 1,
+		"exec: unnamed":            1,
+	}
+
+	assert.Equal(t, want, counts, "Query Counts")
+}
+
+func TestMeteredConnector_BeginOptions(t *testing.T) {
+	t.Parallel()
+
+	mp := sdkmetric.NewMeterProvider()
+
+	// DSNs remain registered with the stub driver, so must be unique when tests are repeated
+	dsn := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+
+	stub, mock, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err, "Opening a stub database connection encountered an error")
+
+	t.Cleanup(func() {
+		mock.ExpectClose()
+		assert.NoError(t, stub.Close(), "Closing mock DB")
+	})
+
+	conn, err := database.MeteredConnector(legacyConnector{dsnConnector{dsn: dsn, driver: stub.Driver()}}, mp)
+	require.NoError(t, err, "Connector Error")
+
+	// The connection of the stub is shared, so is only closed by the stub
+	db := sql.OpenDB(conn)
+
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err, "Default Options error")
+	require.NoError(t, tx.Rollback(), "Rollback error")
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	assert.EqualError(t, err, "driver does not support non-default isolation levels", "Isolation Level error")
+
+	_, err = db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	assert.EqualError(t, err, "driver does not support read-only transactions", "Read Only error")
+
+	assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+}
+
+// dsnConnector opens connections by the DSN, as database/sql does for drivers without their own connectors.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// legacyConnector of connections which predate transaction options, only able to begin them with the defaults.
+type legacyConnector struct {
+	dsnConnector
+}
+
+func (c legacyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.dsnConnector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return legacyConn{Conn: conn}, nil
+}
+
+// legacyConn hides the optional interfaces of the connection, with only the methods which every driver has.
+type legacyConn struct {
+	driver.Conn
+}
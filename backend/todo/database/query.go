@@ -4,6 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dackroyd/todo-list/backend/todo"
 )
 
 type rowQuerier interface {
@@ -51,3 +57,259 @@ func queryRows[T any](ctx context.Context, db rowsQuerier, columns func(*T) []an
 
 	return result, nil
 }
+
+// setActor of the transaction, which triggers record in the history of each list and item changed within it.
+const setActor = `
+	-- Name: Set History Actor
+	SELECT set_config('todo.actor', $1, true)
+`
+
+// pauseHistory of the transaction while on, so that changes are not recorded in the history.
+const pauseHistory = `
+	-- Name: Pause History
+	SELECT set_config('todo.history_paused', $1, true)
+`
+
+// withoutHistory executes fn within the transaction without recording the changes it makes in the history, for those
+// made by the system rather than by the actor, such as renumbering positions.
+func withoutHistory(ctx context.Context, tx *sql.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, pauseHistory, "on"); err != nil {
+		return fmt.Errorf("unable to pause history: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, pauseHistory, "off"); err != nil {
+		return fmt.Errorf("unable to resume history: %w", err)
+	}
+
+	return nil
+}
+
+// inTx executes fn within a transaction, which is committed when fn succeeds, and rolled back otherwise. Changes made
+// within the transaction are recorded as made by the actor of the context, when there is one.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	if actor, ok := todo.Actor(ctx); ok {
+		if _, err := tx.ExecContext(ctx, setActor, actor); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("unable to set actor %q of transaction: %w", actor, err)
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		// The original failure is more useful than any failure to roll back
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// expectAffected returns notFound when the result indicates that no rows were affected by the statement.
+func expectAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("unable to determine affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// nextPage trims results which were queried with one more than the page limit. When that extra result is present there
+// are further pages, and the key of the last result of this page is returned to continue from.
+func nextPage[T any](results []T, limit int, key func(T) string) ([]T, string) {
+	if len(results) <= limit {
+		return results, ""
+	}
+
+	results = results[:limit]
+
+	return results, key(results[limit-1])
+}
+
+// nullIfEmpty so that optional string arguments are passed to queries as NULL.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// pageKeyID as a query argument, for pages which continue after the integer ID of the last result. Keys are provided by
+// clients, so those which are not IDs are rejected, rather than failing the query. An empty key is passed as NULL.
+func pageKeyID(key string) (any, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	if !validID(key) {
+		return nil, todo.InvalidArgumentError("page key is not valid")
+	}
+
+	return key, nil
+}
+
+// validID which is an integer, as all IDs are.
+func validID(id string) bool {
+	_, err := strconv.ParseInt(id, 10, 64)
+	return err == nil
+}
+
+// intervalSecs as a query argument, for use with `make_interval(secs => $n)`. A nil duration is passed as NULL.
+func intervalSecs(d *todo.Duration) any {
+	if d == nil {
+		return nil
+	}
+
+	return time.Duration(*d).Seconds()
+}
+
+// utcTime as a query argument, for TIMESTAMP columns which store no offset, so that times with any offset are stored
+// as the same instant. A nil time is passed as NULL.
+func utcTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
+
+// intervalScanner scans an interval queried as `EXTRACT(EPOCH FROM interval)` into a duration, which is nil for NULL.
+type intervalScanner struct {
+	d **todo.Duration
+}
+
+func (s intervalScanner) Scan(src any) error {
+	var secs float64
+
+	switch v := src.(type) {
+	case nil:
+		*s.d = nil
+		return nil
+	case float64:
+		secs = v
+	case int64:
+		secs = float64(v)
+	case []byte:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse interval seconds %q: %w", v, err)
+		}
+
+		secs = f
+	default:
+		return fmt.Errorf("unsupported type %T for interval seconds", src)
+	}
+
+	d := todo.Duration(time.Duration(secs * float64(time.Second)))
+	*s.d = &d
+
+	return nil
+}
+
+// recurrenceRule as a query argument, which is NULL for items which do not recur.
+func recurrenceRule(r *todo.Recurrence) any {
+	if r == nil {
+		return nil
+	}
+
+	return r.String()
+}
+
+// recurrenceScanner scans a recurrence rule into a recurrence, which is nil for NULL.
+type recurrenceScanner struct {
+	r **todo.Recurrence
+}
+
+func (s recurrenceScanner) Scan(src any) error {
+	var rule string
+
+	switch v := src.(type) {
+	case nil:
+		*s.r = nil
+		return nil
+	case string:
+		rule = v
+	case []byte:
+		rule = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for recurrence rule", src)
+	}
+
+	r, err := todo.ParseRecurrence(rule)
+	if err != nil {
+		return fmt.Errorf("unable to parse recurrence rule %q: %w", rule, err)
+	}
+
+	*s.r = r
+
+	return nil
+}
+
+// progressScanner scans the progress of subtasks, queried as an array of the number done and the total, into a progress
+// which is nil when there are no subtasks.
+type progressScanner struct {
+	p **todo.Progress
+}
+
+func (s progressScanner) Scan(src any) error {
+	var counts pq.Int64Array
+	if err := counts.Scan(src); err != nil {
+		return fmt.Errorf("unable to scan subtask progress: %w", err)
+	}
+
+	if len(counts) != 2 {
+		return fmt.Errorf("subtask progress must have 2 counts, got %d", len(counts))
+	}
+
+	if counts[1] == 0 {
+		*s.p = nil
+		return nil
+	}
+
+	*s.p = &todo.Progress{Done: int(counts[0]), Total: int(counts[1])}
+
+	return nil
+}
+
+// dueOffsetScanner scans a due offset, queried as an array of the days and seconds into the day, into a due offset which
+// is nil for NULL.
+type dueOffsetScanner struct {
+	o **todo.DueOffset
+}
+
+func (s dueOffsetScanner) Scan(src any) error {
+	if src == nil {
+		*s.o = nil
+		return nil
+	}
+
+	var parts pq.Int64Array
+	if err := parts.Scan(src); err != nil {
+		return fmt.Errorf("unable to scan due offset: %w", err)
+	}
+
+	if len(parts) != 2 {
+		return fmt.Errorf("due offset must have days and seconds, got %d values", len(parts))
+	}
+
+	*s.o = &todo.DueOffset{Days: int(parts[0]), TimeOfDay: time.Duration(parts[1]) * time.Second}
+
+	return nil
+}
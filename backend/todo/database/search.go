@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// Search for TODO lists and items, of lists owned by or shared with the authenticated user, with descriptions matching
+// the query, which uses web search syntax: quoted phrases, `or`, and `-` to exclude terms. Results of both kinds are
+// mixed together, ordered by rank. Snippets are HTML, with the description escaped before matches are highlighted, so
+// that descriptions cannot inject markup of their own.
+// Archived and deleted lists and items are never found, nor are items of such lists.
+func (r *ListRepository) Search(ctx context.Context, query string, page todo.Page) ([]todo.SearchResult, string, error) {
+	q := `
+		-- Name: Search TODO Lists and Items
+		SELECT kind,
+		       id,
+		       list_id,
+		       description,
+		       ts_headline('english',
+		                   replace(replace(replace(replace(replace(description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'),
+		                   websearch_to_tsquery('english', $1),
+		                   'StartSel=<mark>, StopSel=</mark>'),
+		       rank
+		  FROM (
+		        SELECT 'list' AS kind,
+		               id,
+		               id AS list_id,
+		               COALESCE(description, '') AS description,
+		               ts_rank(search, websearch_to_tsquery('english', $1))::float8 AS rank
+		          FROM lists
+		         WHERE search @@ websearch_to_tsquery('english', $1)
+		           AND archived IS NULL
+		           AND deleted IS NULL
+		           AND (owner_id = $6 OR id IN (SELECT list_id FROM list_members WHERE user_id = $6))
+		        UNION ALL
+		        SELECT 'item',
+		               id,
+		               list_id,
+		               description,
+		               ts_rank(search, websearch_to_tsquery('english', $1))::float8
+		          FROM items
+		         WHERE search @@ websearch_to_tsquery('english', $1)
+		           AND archived IS NULL
+		           AND deleted IS NULL
+		           AND list_id IN (
+		               SELECT id
+		                 FROM lists
+		                WHERE archived IS NULL
+		                  AND deleted IS NULL
+		                  AND (owner_id = $6 OR id IN (SELECT list_id FROM list_members WHERE user_id = $6))
+		           )
+		       ) hits
+		 WHERE $2::float8 IS NULL
+		    OR rank < $2
+		    OR (rank = $2 AND (kind, id) > ($3, $4::int))
+		 ORDER BY rank DESC, kind, id
+		 LIMIT $5
+	`
+
+	var after searchKey
+	if page.After != "" {
+		if err := json.Unmarshal([]byte(page.After), &after); err != nil || after.Rank == nil || !validID(after.ID) {
+			return nil, "", todo.InvalidArgumentError("page key is not valid for search")
+		}
+	}
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, err := queryRows(ctx, r.db, searchResultCols, q, query, after.Rank, nullIfEmpty(string(after.Kind)), nullIfEmpty(after.ID), page.Limit+1, uid)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search todo lists and items for %q: %w", query, err)
+	}
+
+	results, next := nextPage(results, page.Limit, resultKey)
+
+	return results, next, nil
+}
+
+// searchKey of the last result of a page, which identifies where the next page continues from.
+type searchKey struct {
+	Rank *float64        `json:"rank"`
+	Kind todo.SearchKind `json:"kind"`
+	ID   string          `json:"id"`
+}
+
+func resultKey(res todo.SearchResult) string {
+	b, _ := json.Marshal(&searchKey{Rank: &res.Rank, Kind: res.Kind, ID: res.ID})
+
+	return string(b)
+}
+
+func searchResultCols(res *todo.SearchResult) []any {
+	return []any{&res.Kind, &res.ID, &res.ListID, &res.Description, &res.Snippet, &res.Rank}
+}
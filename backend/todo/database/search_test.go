@@ -0,0 +1,190 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Page  todo.Page
+		Query string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error   error
+		Results []todo.SearchResult
+		Next    string
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	washingList := todo.SearchResult{Kind: todo.SearchKindList, ID: "3", ListID: "3", Description: "Washing", Snippet: "<mark>Washing</mark>", Rank: 0.0607927}
+	washingItem := todo.SearchResult{Kind: todo.SearchKindItem, ID: "1", ListID: "1", Description: "Washing", Snippet: "<mark>Washing</mark>", Rank: 0.0607927}
+	washCarItem := todo.SearchResult{Kind: todo.SearchKindItem, ID: "7", ListID: "2", Description: "Wash the car", Snippet: "<mark>Wash</mark> the car", Rank: 0.0303964}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{Query: "washing", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockSearchQuery(mock, "washing", nil, nil, nil, 101).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Invalid Page Key": {
+			Args:   args{Query: "washing", Page: todo.Page{After: "3", Limit: 100}},
+			Fields: fields{MockExpectations: func(sqlmock.Sqlmock) {}},
+			Want:   want{Error: todo.InvalidArgumentError("page key is not valid for search")},
+		},
+		"Page Key with a tampered ID": {
+			Args:   args{Query: "washing", Page: todo.Page{After: `{"rank":0.5,"kind":"item","id":"x"}`, Limit: 100}},
+			Fields: fields{MockExpectations: func(sqlmock.Sqlmock) {}},
+			Want:   want{Error: todo.InvalidArgumentError("page key is not valid for search")},
+		},
+		"No Results": {
+			Args: args{Query: "washing", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockSearchQuery(mock, "washing", nil, nil, nil, 101).WillReturnRows(mockSearchRows())
+				},
+			},
+		},
+		"Lists and Items": {
+			Args: args{Query: "washing", Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockSearchQuery(mock, "washing", nil, nil, nil, 101).WillReturnRows(mockSearchRows(washingItem, washingList, washCarItem))
+				},
+			},
+			Want: want{Results: []todo.SearchResult{washingItem, washingList, washCarItem}},
+		},
+		"Page with more": {
+			Args: args{Query: "washing", Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockSearchQuery(mock, "washing", nil, nil, nil, 3).WillReturnRows(mockSearchRows(washingItem, washingList, washCarItem))
+				},
+			},
+			Want: want{
+				Results: []todo.SearchResult{washingItem, washingList},
+				Next:    `{"rank":0.0607927,"kind":"list","id":"3"}`,
+			},
+		},
+		"Following page": {
+			Args: args{Query: "washing", Page: todo.Page{After: `{"rank":0.0607927,"kind":"list","id":"3"}`, Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockSearchQuery(mock, "washing", 0.0607927, "list", "3", 3).WillReturnRows(mockSearchRows(washCarItem))
+				},
+			},
+			Want: want{Results: []todo.SearchResult{washCarItem}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			results, next, err := repo.Search(userContext(), tt.Args.Query, tt.Args.Page)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Search error")
+				return
+			}
+
+			require.NoError(t, err, "Search error")
+			assert.Equal(t, tt.Want.Results, results, "Results")
+			assert.Equal(t, tt.Want.Next, next, "Next")
+		})
+	}
+}
+
+func mockSearchQuery(mock sqlmock.Sqlmock, query string, afterRank, afterKind, afterID any, limit int) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Search TODO Lists and Items
+		SELECT kind,
+		       id,
+		       list_id,
+		       description,
+		       ts_headline('english',
+		                   replace(replace(replace(replace(replace(description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'),
+		                   websearch_to_tsquery('english', $1),
+		                   'StartSel=<mark>, StopSel=</mark>'),
+		       rank
+		  FROM (
+		        SELECT 'list' AS kind,
+		               id,
+		               id AS list_id,
+		               COALESCE(description, '') AS description,
+		               ts_rank(search, websearch_to_tsquery('english', $1))::float8 AS rank
+		          FROM lists
+		         WHERE search @@ websearch_to_tsquery('english', $1)
+		           AND archived IS NULL
+		           AND deleted IS NULL
+		           AND (owner_id = $6 OR id IN (SELECT list_id FROM list_members WHERE user_id = $6))
+		        UNION ALL
+		        SELECT 'item',
+		               id,
+		               list_id,
+		               description,
+		               ts_rank(search, websearch_to_tsquery('english', $1))::float8
+		          FROM items
+		         WHERE search @@ websearch_to_tsquery('english', $1)
+		           AND archived IS NULL
+		           AND deleted IS NULL
+		           AND list_id IN (
+		               SELECT id
+		                 FROM lists
+		                WHERE archived IS NULL
+		                  AND deleted IS NULL
+		                  AND (owner_id = $6 OR id IN (SELECT list_id FROM list_members WHERE user_id = $6))
+		           )
+		       ) hits
+		 WHERE $2::float8 IS NULL
+		    OR rank < $2
+		    OR (rank = $2 AND (kind, id) > ($3, $4::int))
+		 ORDER BY rank DESC, kind, id
+		 LIMIT $5
+	`
+
+	return mock.ExpectQuery(q).WithArgs(query, afterRank, afterKind, afterID, limit, testUser.ID)
+}
+
+func mockSearchRows(results ...todo.SearchResult) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"kind", "id", "list_id", "description", "ts_headline", "rank"})
+
+	for _, res := range results {
+		rows.AddRow(string(res.Kind), res.ID, res.ListID, res.Description, res.Snippet, res.Rank)
+	}
+
+	return rows
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// Tags which are applied to at least one TODO item of the lists owned by or shared with the authenticated user, in
+// alphabetical order, along with the number of their items having each. Archived and deleted items are not counted.
+func (r *ListRepository) Tags(ctx context.Context, page todo.Page) ([]todo.Tag, string, error) {
+	query := `
+		-- Name: TODO Item Tags
+		SELECT t.name,
+		       count(*)
+		  FROM tags t
+		  JOIN item_tags it ON it.tag_id = t.id
+		  JOIN items i ON i.id = it.item_id
+		  JOIN lists l ON l.id = i.list_id
+		 WHERE (l.owner_id = $3 OR l.id IN (SELECT list_id FROM list_members WHERE user_id = $3))
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND ($1::text IS NULL OR t.name > $1)
+		 GROUP BY t.name
+		 ORDER BY t.name
+		 LIMIT $2
+	`
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tags, err := queryRows(ctx, r.db, tagCols, query, nullIfEmpty(page.After), page.Limit+1, uid)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query for tags: %w", err)
+	}
+
+	tags, next := nextPage(tags, page.Limit, func(t todo.Tag) string { return t.Name })
+
+	return tags, next, nil
+}
+
+// TagItem with the tag, which is created when it is not already in use. Tagging an item which already has the tag has
+// no effect.
+func (r *ListRepository) TagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error) {
+	createTag := `
+		-- Name: Create TODO Item Tag
+		INSERT INTO tags (name)
+		VALUES ($1)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	tagItem := `
+		-- Name: Tag TODO List Item
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT i.id,
+		       t.id
+		  FROM items i,
+		       tags t
+		 WHERE i.id = $1
+		   AND i.list_id = $2
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND t.name = $3
+		ON CONFLICT DO NOTHING
+	`
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, createTag, tag); err != nil {
+			return fmt.Errorf("failed to create tag %q: %w", tag, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, tagItem, itemID, listID, tag); err != nil {
+			return fmt.Errorf("failed to tag item %q of todo list %q with %q: %w", itemID, listID, tag, err)
+		}
+
+		var err error
+		item, err = r.item(ctx, tx, listID, itemID, todo.Include{})
+
+		return err
+	})
+
+	return item, err
+}
+
+// UntagItem by removing the tag from it. Removing a tag which the item does not have has no effect.
+func (r *ListRepository) UntagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error) {
+	query := `
+		-- Name: Untag TODO List Item
+		DELETE FROM item_tags it
+		 USING items i,
+		       tags t
+		 WHERE it.item_id = i.id
+		   AND it.tag_id = t.id
+		   AND i.id = $1
+		   AND i.list_id = $2
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND t.name = $3
+	`
+
+	var item *todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleEditor, todo.Include{}); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, itemID, listID, tag); err != nil {
+			return fmt.Errorf("failed to untag item %q of todo list %q with %q: %w", itemID, listID, tag, err)
+		}
+
+		var err error
+		item, err = r.item(ctx, tx, listID, itemID, todo.Include{})
+
+		return err
+	})
+
+	return item, err
+}
+
+// item of a TODO list, as it is within the transaction. Archived and deleted items are not found, unless included.
+func (r *ListRepository) item(ctx context.Context, tx *sql.Tx, listID, itemID string, include todo.Include) (*todo.Item, error) {
+	query := `
+		-- Name: TODO List Item
+		SELECT ` + itemColumns + `
+		  FROM items
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND ($3::boolean OR archived IS NULL)
+		   AND ($4::boolean OR deleted IS NULL)
+	`
+
+	item, err := queryRow(ctx, tx, itemCols, query, itemID, listID, include.Archived, include.Deleted)
+
+	return itemResult(item, err, listID, itemID, "retrieve")
+}
+
+func tagCols(t *todo.Tag) []any {
+	return []any{&t.Name, &t.Count}
+}
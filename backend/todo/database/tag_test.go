@@ -0,0 +1,427 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Page todo.Page
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Tags  []todo.Tag
+		Next  string
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTagsQuery(mock, todo.Page{Limit: 100}).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"No Tags": {
+			Args: args{Page: todo.Page{Limit: 100}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTagsQuery(mock, todo.Page{Limit: 100}).WillReturnRows(mockTagRows())
+				},
+			},
+		},
+		"Page with more": {
+			Args: args{Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTagsQuery(mock, todo.Page{Limit: 2}).WillReturnRows(mockTagRows(
+						todo.Tag{Name: "@errands", Count: 1},
+						todo.Tag{Name: "@home", Count: 12},
+						todo.Tag{Name: "@work", Count: 3},
+					))
+				},
+			},
+			Want: want{
+				Tags: []todo.Tag{{Name: "@errands", Count: 1}, {Name: "@home", Count: 12}},
+				Next: "@home",
+			},
+		},
+		"Last page": {
+			Args: args{Page: todo.Page{After: "@home", Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTagsQuery(mock, todo.Page{After: "@home", Limit: 2}).WillReturnRows(mockTagRows(todo.Tag{Name: "@work", Count: 3}))
+				},
+			},
+			Want: want{Tags: []todo.Tag{{Name: "@work", Count: 3}}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			tags, next, err := repo.Tags(userContext(), tt.Args.Page)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Retrieval error")
+				return
+			}
+
+			require.NoError(t, err, "Retrieval error")
+			assert.Equal(t, tt.Want.Tags, tags, "Tags")
+			assert.Equal(t, tt.Want.Next, next, "Next")
+		})
+	}
+}
+
+func TestTagItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		Tag    string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	execErr := errors.New("failed to execute statement")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Create Tag failure": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCreateTagQuery(mock, "@home").WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Item does not exist": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCreateTagQuery(mock, "@home").WillReturnResult(sqlmock.NewResult(1, 1))
+					mockTagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 0))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Archived Item": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCreateTagQuery(mock, "@home").WillReturnResult(sqlmock.NewResult(1, 1))
+					// Archived items are neither tagged, nor found afterwards
+					mockTagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 0))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Tagged": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockCreateTagQuery(mock, "@home").WillReturnResult(sqlmock.NewResult(0, 0))
+					mockTagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows(todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{"@home", "@weekend"}}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{"@home", "@weekend"}}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.TagItem(userContext(), tt.Args.ListID, tt.Args.ItemID, tt.Args.Tag)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Tagging error")
+				return
+			}
+
+			require.NoError(t, err, "Tagging error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func TestUntagItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		Tag    string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Item  *todo.Item
+	}
+
+	execErr := errors.New("failed to execute statement")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Untag failure": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUntagItemQuery(mock, "2", "1", "@home").WillReturnError(execErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: execErr},
+		},
+		"Item does not exist": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUntagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 0))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Deleted Item": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					// Deleted items are neither untagged, nor found afterwards
+					mockUntagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 0))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "2" does not exist in list "1"`)},
+		},
+		"Untagged": {
+			Args: args{ListID: "1", ItemID: "2", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockUntagItemQuery(mock, "2", "1", "@home").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockItemQuery(mock, "2", "1", todo.Include{}).WillReturnRows(mockItemRows(todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{}}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Item: &todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{}}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			item, err := repo.UntagItem(userContext(), tt.Args.ListID, tt.Args.ItemID, tt.Args.Tag)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Untagging error")
+				return
+			}
+
+			require.NoError(t, err, "Untagging error")
+			assert.Equal(t, tt.Want.Item, item, "Item")
+		})
+	}
+}
+
+func mockTagsQuery(mock sqlmock.Sqlmock, page todo.Page) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO Item Tags
+		SELECT t.name,
+		       count(*)
+		  FROM tags t
+		  JOIN item_tags it ON it.tag_id = t.id
+		  JOIN items i ON i.id = it.item_id
+		  JOIN lists l ON l.id = i.list_id
+		 WHERE (l.owner_id = $3 OR l.id IN (SELECT list_id FROM list_members WHERE user_id = $3))
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND ($1::text IS NULL OR t.name > $1)
+		 GROUP BY t.name
+		 ORDER BY t.name
+		 LIMIT $2
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pageAfter(page), page.Limit+1, testUser.ID)
+}
+
+func mockTagRows(tags ...todo.Tag) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"name", "count"})
+
+	for _, tag := range tags {
+		rows.AddRow(tag.Name, tag.Count)
+	}
+
+	return rows
+}
+
+func mockCreateTagQuery(mock sqlmock.Sqlmock, tag string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Create TODO Item Tag
+		INSERT INTO tags (name)
+		VALUES ($1)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	return mock.ExpectExec(q).WithArgs(tag)
+}
+
+func mockTagItemQuery(mock sqlmock.Sqlmock, itemID, listID, tag string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Tag TODO List Item
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT i.id,
+		       t.id
+		  FROM items i,
+		       tags t
+		 WHERE i.id = $1
+		   AND i.list_id = $2
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND t.name = $3
+		ON CONFLICT DO NOTHING
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, listID, tag)
+}
+
+func mockUntagItemQuery(mock sqlmock.Sqlmock, itemID, listID, tag string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Untag TODO List Item
+		DELETE FROM item_tags it
+		 USING items i,
+		       tags t
+		 WHERE it.item_id = i.id
+		   AND it.tag_id = t.id
+		   AND i.id = $1
+		   AND i.list_id = $2
+		   AND i.archived IS NULL
+		   AND i.deleted IS NULL
+		   AND t.name = $3
+	`
+
+	return mock.ExpectExec(q).WithArgs(itemID, listID, tag)
+}
+
+func mockItemQuery(mock sqlmock.Sqlmock, itemID, listID string, include todo.Include) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Item
+		SELECT id,
+		       description,
+		       due,
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
+		  FROM items
+		 WHERE id = $1
+		   AND list_id = $2
+		   AND ($3::boolean OR archived IS NULL)
+		   AND ($4::boolean OR deleted IS NULL)
+	`
+
+	return mock.ExpectQuery(q).WithArgs(itemID, listID, include.Archived, include.Deleted)
+}
@@ -0,0 +1,400 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// Templates of TODO lists owned by the authenticated user, in the requested page. The key of the last template is
+// returned when there are further pages.
+func (r *ListRepository) Templates(ctx context.Context, page todo.Page) ([]todo.Template, string, error) {
+	query := `
+		-- Name: TODO List Templates
+		SELECT id,
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon)
+		  FROM templates
+		 WHERE owner_id = $3
+		   AND ($1::int IS NULL OR id > $1)
+		 ORDER BY id
+		 LIMIT $2
+	`
+
+	after, err := pageKeyID(page.After)
+	if err != nil {
+		return nil, "", err
+	}
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	templates, err := queryRows(ctx, r.db, templateCols, query, after, page.Limit+1, uid)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query todo list templates: %w", err)
+	}
+
+	templates, next := nextPage(templates, page.Limit, func(t todo.Template) string { return t.ID })
+
+	return templates, next, nil
+}
+
+// Template of a TODO list, along with its items. Templates owned by other users are not found.
+func (r *ListRepository) Template(ctx context.Context, templateID string) (*todo.Template, []todo.TemplateItem, error) {
+	query := `
+		-- Name: TODO List Template
+		SELECT id,
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon)
+		  FROM templates
+		 WHERE id = $1
+		   AND owner_id = $2
+	`
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := queryRow(ctx, r.db, templateCols, query, templateID, uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, templateNotFound(templateID)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query todo list template %q: %w", templateID, err)
+	}
+
+	items, err := templateItems(ctx, r.db, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return template, items, nil
+}
+
+// SaveTemplate of a TODO list, owned by the authenticated user, which has all the items of the list which are not
+// archived or deleted. The due dates of the items are saved relative to the anchor date, while completion is not saved,
+// so that lists created from the template start with nothing done.
+func (r *ListRepository) SaveTemplate(ctx context.Context, listID string, save todo.TemplateSave) (*todo.Template, []todo.TemplateItem, error) {
+	createTemplate := `
+		-- Name: Create TODO List Template
+		INSERT INTO templates (owner_id, description, due_horizon)
+		SELECT $3,
+		       COALESCE($2, description),
+		       due_horizon
+		  FROM lists
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon)
+	`
+
+	dueDates := `
+		-- Name: TODO List Item Due Dates
+		SELECT id,
+		       due
+		  FROM items
+		 WHERE list_id = $1
+		   AND due IS NOT NULL
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		 ORDER BY due, id
+	`
+
+	// IDs for the template items are allocated up front, so that subtasks can refer to the copies of their parents
+	copyItems := `
+		-- Name: Copy TODO List Items to Template
+		WITH copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('template_items', 'id')) AS copy_id
+		       FROM items
+		      WHERE list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		),
+		offsets AS (
+		     SELECT *
+		       FROM unnest($3::int[], $4::int[], $5::float8[]) AS o(item_id, days, secs)
+		)
+		INSERT INTO template_items (id, template_id, parent_id, description, due_days, due_time, priority, recurrence, tags, auto_complete, position)
+		SELECT c.copy_id,
+		       $1,
+		       p.copy_id,
+		       i.description,
+		       o.days,
+		       make_interval(secs => o.secs),
+		       i.priority,
+		       i.recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = i.id ORDER BY t.name),
+		       i.auto_complete,
+		       i.position
+		  FROM items i
+		  JOIN copies c ON c.id = i.id
+		  LEFT JOIN copies p ON p.id = i.parent_id
+		  LEFT JOIN offsets o ON o.item_id = i.id
+	`
+
+	var (
+		template *todo.Template
+		items    []todo.TemplateItem
+	)
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.checkAccess(ctx, tx, listID, todo.RoleViewer, hiddenLists); err != nil {
+			return err
+		}
+
+		var err error
+
+		template, err = queryRow(ctx, tx, templateCols, createTemplate, listID, nullIfEmpty(save.Description), uid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", listID))
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to create template of todo list %q: %w", listID, err)
+		}
+
+		due, err := queryRows(ctx, tx, itemDueCols, dueDates, listID)
+		if err != nil {
+			return fmt.Errorf("failed to query due dates of items of todo list %q: %w", listID, err)
+		}
+
+		// Without an anchor date, the earliest due item is due on the anchor date
+		anchor := save.Anchor
+		if anchor == nil && len(due) > 0 {
+			first := due[0].Due.In(r.loc)
+			anchor = &first
+		}
+
+		ids := make([]string, len(due))
+		days := make([]int64, len(due))
+		secs := make([]float64, len(due))
+
+		for i, d := range due {
+			offset := todo.DueOffsetFrom(*anchor, d.Due, r.loc)
+
+			ids[i] = d.ID
+			days[i] = int64(offset.Days)
+			secs[i] = offset.TimeOfDay.Seconds()
+		}
+
+		if _, err := tx.ExecContext(ctx, copyItems, template.ID, listID, pq.Array(ids), pq.Array(days), pq.Array(secs)); err != nil {
+			return fmt.Errorf("failed to copy items of todo list %q to template %q: %w", listID, template.ID, err)
+		}
+
+		items, err = templateItems(ctx, tx, template.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return template, items, nil
+}
+
+// InstantiateTemplate as a new TODO list owned by the authenticated user, with the due dates of its items resolved from
+// the anchor date.
+func (r *ListRepository) InstantiateTemplate(ctx context.Context, templateID string, instance todo.TemplateInstance) (*todo.List, []todo.Item, error) {
+	createList := `
+		-- Name: Create TODO List from Template
+		INSERT INTO lists (owner_id, description, due_horizon)
+		SELECT owner_id,
+		       COALESCE($2, description),
+		       due_horizon
+		  FROM templates
+		 WHERE id = $1
+		   AND owner_id = $3
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	createTags := `
+		-- Name: Create TODO Item Tags from Template
+		INSERT INTO tags (name)
+		SELECT DISTINCT unnest(tags)
+		  FROM template_items
+		 WHERE template_id = $1
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	// IDs for the items are allocated up front, so that subtasks can refer to the items created for their parents
+	createItems := `
+		-- Name: Create TODO List Items from Template
+		WITH copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('items', 'id')) AS copy_id
+		       FROM template_items
+		      WHERE template_id = $1
+		),
+		dues AS (
+		     SELECT *
+		       FROM unnest($3::int[], $4::timestamp[]) AS d(template_item_id, due)
+		),
+		items_created AS (
+		     INSERT INTO items (id, list_id, parent_id, description, due, priority, recurrence, auto_complete, position)
+		     SELECT c.copy_id,
+		            $2,
+		            p.copy_id,
+		            ti.description,
+		            d.due,
+		            ti.priority,
+		            ti.recurrence,
+		            ti.auto_complete,
+		            ti.position
+		       FROM template_items ti
+		       JOIN copies c ON c.id = ti.id
+		       LEFT JOIN copies p ON p.id = ti.parent_id
+		       LEFT JOIN dues d ON d.template_item_id = ti.id
+		)
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT c.copy_id,
+		       t.id
+		  FROM template_items ti
+		  JOIN copies c ON c.id = ti.id
+		  JOIN tags t ON t.name = ANY(ti.tags)
+	`
+
+	created := `
+		-- Name: TODO List Items from Template
+		SELECT ` + itemColumns + `
+		  FROM items
+		 WHERE list_id = $1
+		 ORDER BY position, id
+	`
+
+	var (
+		list  *todo.List
+		items []todo.Item
+	)
+
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+
+		list, err = queryRow(ctx, tx, listCols, createList, templateID, nullIfEmpty(instance.Description), uid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return templateNotFound(templateID)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to create todo list from template %q: %w", templateID, err)
+		}
+
+		templated, err := templateItems(ctx, tx, templateID)
+		if err != nil {
+			return err
+		}
+
+		var (
+			ids  []string
+			dues []time.Time
+		)
+
+		for _, ti := range templated {
+			if ti.DueOffset != nil {
+				ids = append(ids, ti.ID)
+				dues = append(dues, ti.DueOffset.Due(instance.Anchor, r.loc).UTC())
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, createTags, templateID); err != nil {
+			return fmt.Errorf("failed to create tags of template %q: %w", templateID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, createItems, templateID, list.ID, pq.Array(ids), pq.Array(dues)); err != nil {
+			return fmt.Errorf("failed to create items of todo list %q from template %q: %w", list.ID, templateID, err)
+		}
+
+		if items, err = queryRows(ctx, tx, itemCols, created, list.ID); err != nil {
+			return fmt.Errorf("failed to query items of todo list %q: %w", list.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, items, nil
+}
+
+// templateItems of a template, in the order of their positions.
+func templateItems(ctx context.Context, db rowsQuerier, templateID string) ([]todo.TemplateItem, error) {
+	query := `
+		-- Name: TODO List Template Items
+		SELECT id,
+		       parent_id,
+		       description,
+		       CASE WHEN due_days IS NOT NULL THEN ARRAY[due_days, EXTRACT(EPOCH FROM due_time)::int] END,
+		       priority,
+		       recurrence,
+		       tags,
+		       auto_complete,
+		       position
+		  FROM template_items
+		 WHERE template_id = $1
+		 ORDER BY position, id
+	`
+
+	items, err := queryRows(ctx, db, templateItemCols, query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items of todo list template %q: %w", templateID, err)
+	}
+
+	return items, nil
+}
+
+func templateNotFound(templateID string) todo.NotFoundError {
+	return todo.NotFoundError(fmt.Sprintf("template with id %q does not exist", templateID))
+}
+
+func templateCols(t *todo.Template) []any {
+	return []any{&t.ID, &t.Description, intervalScanner{d: &t.DueHorizon}}
+}
+
+func templateItemCols(i *todo.TemplateItem) []any {
+	return []any{
+		&i.ID,
+		&i.ParentID,
+		&i.Description,
+		dueOffsetScanner{o: &i.DueOffset},
+		&i.Priority,
+		recurrenceScanner{r: &i.Recurrence},
+		(*pq.StringArray)(&i.Tags),
+		&i.AutoComplete,
+		&i.Position,
+	}
+}
+
+// itemDue is the due date of a TODO item.
+type itemDue struct {
+	ID  string
+	Due time.Time
+}
+
+func itemDueCols(d *itemDue) []any {
+	return []any{&d.ID, &d.Due}
+}
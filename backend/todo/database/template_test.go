@@ -0,0 +1,813 @@
+package database_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Page todo.Page
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error     error
+		Next      string
+		Templates []todo.Template
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplatesQuery(mock, todo.Page{Limit: 2}).WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Tampered Page Key": {
+			Args: args{Page: todo.Page{After: "abc", Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {},
+			},
+			Want: want{Error: todo.InvalidArgumentError("page key is not valid")},
+		},
+		"No Templates": {
+			Args: args{Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplatesQuery(mock, todo.Page{Limit: 2}).WillReturnRows(mockTemplateRows())
+				},
+			},
+		},
+		"First Page": {
+			Args: args{Page: todo.Page{Limit: 2}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplatesQuery(mock, todo.Page{Limit: 2}).WillReturnRows(mockTemplateRows(
+						todo.Template{ID: "1", Description: "Moving House"},
+						todo.Template{ID: "2", Description: "Holiday", DueHorizon: ptr(todo.Duration(72 * time.Hour))},
+						todo.Template{ID: "3", Description: "Christmas"},
+					))
+				},
+			},
+			Want: want{
+				Templates: []todo.Template{
+					{ID: "1", Description: "Moving House"},
+					{ID: "2", Description: "Holiday", DueHorizon: ptr(todo.Duration(72 * time.Hour))},
+				},
+				Next: "2",
+			},
+		},
+		"Last Page": {
+			Args: args{Page: todo.Page{Limit: 2, After: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplatesQuery(mock, todo.Page{Limit: 2, After: "2"}).WillReturnRows(mockTemplateRows(todo.Template{ID: "3", Description: "Christmas"}))
+				},
+			},
+			Want: want{Templates: []todo.Template{{ID: "3", Description: "Christmas"}}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			templates, next, err := repo.Templates(userContext(), tt.Args.Page)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Templates error")
+				return
+			}
+
+			require.NoError(t, err, "Templates error")
+			assert.Equal(t, tt.Want.Templates, templates, "Templates")
+			assert.Equal(t, tt.Want.Next, next, "Next")
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error    error
+		Items    []todo.TemplateItem
+		Template *todo.Template
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	items := []todo.TemplateItem{
+		{ID: "7", Description: "Book removalist", DueOffset: &todo.DueOffset{Days: -14, TimeOfDay: 9 * time.Hour}, Priority: todo.PriorityHigh, Position: 1024},
+		{ID: "8", ParentID: ptr("7"), Description: "Get quotes", Tags: todo.Tags{"@phone"}, Position: 2048},
+	}
+
+	testTable := map[string]struct {
+		Fields fields
+		Want   want
+	}{
+		"Not Found": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplateQuery(mock, "4").WillReturnRows(mockTemplateRows())
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`template with id "4" does not exist`)},
+		},
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplateQuery(mock, "4").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Items Query failure": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplateQuery(mock, "4").WillReturnRows(mockTemplateRows(todo.Template{ID: "4", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Found": {
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockTemplateQuery(mock, "4").WillReturnRows(mockTemplateRows(todo.Template{ID: "4", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnRows(mockTemplateItemRows(items...))
+				},
+			},
+			Want: want{Template: &todo.Template{ID: "4", Description: "Moving House"}, Items: items},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			template, items, err := repo.Template(userContext(), "4")
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Template error")
+				return
+			}
+
+			require.NoError(t, err, "Template error")
+			assert.Equal(t, tt.Want.Template, template, "Template")
+			assert.Equal(t, tt.Want.Items, items, "Items")
+		})
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+		Save   todo.TemplateSave
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error    error
+		Items    []todo.TemplateItem
+		Template *todo.Template
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	due := []dueDate{
+		{ID: "3", Due: time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)},
+		{ID: "4", Due: time.Date(2023, time.July, 1, 17, 30, 0, 0, time.UTC)},
+	}
+
+	template := todo.Template{ID: "5", Description: "Holiday"}
+
+	items := []todo.TemplateItem{
+		{ID: "10", Description: "Pack", DueOffset: &todo.DueOffset{TimeOfDay: 8 * time.Hour}, Position: 1024},
+		{ID: "11", Description: "Fly", DueOffset: &todo.DueOffset{Days: 2, TimeOfDay: 17*time.Hour + 30*time.Minute}, Position: 2048},
+		{ID: "12", Description: "Buy sunscreen", Position: 3072},
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"List owned by another User": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockNoListAccess(mock, "2")
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"List does not exist": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnRows(mockTemplateRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"Create failure": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Due Dates Query failure": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnRows(mockTemplateRows(todo.Template{ID: "5", Description: "Holiday"}))
+					mockItemDueDatesQuery(mock, "2").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Copy failure": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnRows(mockTemplateRows(todo.Template{ID: "5", Description: "Holiday"}))
+					mockItemDueDatesQuery(mock, "2").WillReturnRows(mockDueDateRows(due...))
+					mockCopyToTemplateQuery(mock, "5", "2", []string{"3", "4"}, []int64{0, 2}, []float64{28800, 63000}).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Saved relative to the earliest Due Date": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnRows(mockTemplateRows(todo.Template{ID: "5", Description: "Holiday"}))
+					mockItemDueDatesQuery(mock, "2").WillReturnRows(mockDueDateRows(due...))
+					mockCopyToTemplateQuery(mock, "5", "2", []string{"3", "4"}, []int64{0, 2}, []float64{28800, 63000}).WillReturnResult(sqlmock.NewResult(0, 3))
+					mockTemplateItemsQuery(mock, "5").WillReturnRows(mockTemplateItemRows(items...))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Template: &template, Items: items},
+		},
+		"Saved relative to the Anchor Date": {
+			Args: args{ListID: "2", Save: todo.TemplateSave{Description: "Beach Holiday", Anchor: ptr(time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC))}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", "Beach Holiday").WillReturnRows(mockTemplateRows(todo.Template{ID: "5", Description: "Beach Holiday"}))
+					mockItemDueDatesQuery(mock, "2").WillReturnRows(mockDueDateRows(due...))
+					mockCopyToTemplateQuery(mock, "5", "2", []string{"3", "4"}, []int64{-2, 0}, []float64{28800, 63000}).WillReturnResult(sqlmock.NewResult(0, 3))
+					mockTemplateItemsQuery(mock, "5").WillReturnRows(mockTemplateItemRows(items[2]))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Template: &todo.Template{ID: "5", Description: "Beach Holiday"}, Items: items[2:]},
+		},
+		"Saved without Due Dates": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "2")
+					mockCreateTemplateQuery(mock, "2", nil).WillReturnRows(mockTemplateRows(todo.Template{ID: "5", Description: "Holiday"}))
+					mockItemDueDatesQuery(mock, "2").WillReturnRows(mockDueDateRows())
+					mockCopyToTemplateQuery(mock, "5", "2", []string{}, []int64{}, []float64{}).WillReturnResult(sqlmock.NewResult(0, 1))
+					mockTemplateItemsQuery(mock, "5").WillReturnRows(mockTemplateItemRows(items[2]))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Template: &template, Items: items[2:]},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			template, items, err := repo.SaveTemplate(userContext(), tt.Args.ListID, tt.Args.Save)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Save error")
+				return
+			}
+
+			require.NoError(t, err, "Save error")
+			assert.Equal(t, tt.Want.Template, template, "Template")
+			assert.Equal(t, tt.Want.Items, items, "Items")
+		})
+	}
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Instance todo.TemplateInstance
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Items []todo.Item
+		List  *todo.List
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	anchor := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	templated := []todo.TemplateItem{
+		{ID: "7", Description: "Book removalist", DueOffset: &todo.DueOffset{TimeOfDay: 8 * time.Hour}, Position: 1024},
+		{ID: "8", ParentID: ptr("7"), Description: "Get quotes", Tags: todo.Tags{"@phone"}, Position: 2048},
+		{ID: "9", Description: "Pack", DueOffset: &todo.DueOffset{Days: 2, TimeOfDay: 17*time.Hour + 30*time.Minute}, Position: 3072},
+	}
+
+	dues := []time.Time{
+		time.Date(2023, time.August, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2023, time.August, 3, 17, 30, 0, 0, time.UTC),
+	}
+
+	items := []todo.Item{
+		{ID: "20", Description: "Book removalist", Due: &dues[0], Progress: &todo.Progress{Total: 1}, Position: 1024},
+		{ID: "21", ParentID: ptr("20"), Description: "Get quotes", Tags: todo.Tags{"@phone"}, Position: 2048},
+		{ID: "22", Description: "Pack", Due: &dues[1], Position: 3072},
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Template does not exist": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnRows(mockListRows())
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`template with id "4" does not exist`)},
+		},
+		"Create List failure": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Template Items Query failure": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnRows(mockListRows(todo.List{ID: "6", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Create Tags failure": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnRows(mockListRows(todo.List{ID: "6", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnRows(mockTemplateItemRows(templated...))
+					mockCreateTagsFromTemplateQuery(mock, "4").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Create Items failure": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnRows(mockListRows(todo.List{ID: "6", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnRows(mockTemplateItemRows(templated...))
+					mockCreateTagsFromTemplateQuery(mock, "4").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockCreateItemsFromTemplateQuery(mock, "4", "6", []string{"7", "9"}, dues).WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Created": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", nil).WillReturnRows(mockListRows(todo.List{ID: "6", Description: "Moving House"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnRows(mockTemplateItemRows(templated...))
+					mockCreateTagsFromTemplateQuery(mock, "4").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockCreateItemsFromTemplateQuery(mock, "4", "6", []string{"7", "9"}, dues).WillReturnResult(sqlmock.NewResult(0, 1))
+					mockItemsFromTemplateQuery(mock, "6").WillReturnRows(mockItemRows(items...))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "6", Description: "Moving House"}, Items: items},
+		},
+		"Created with Description": {
+			Args: args{Instance: todo.TemplateInstance{Anchor: anchor, Description: "Moving to Melbourne"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockCreateListFromTemplateQuery(mock, "4", "Moving to Melbourne").WillReturnRows(mockListRows(todo.List{ID: "6", Description: "Moving to Melbourne"}))
+					mockTemplateItemsQuery(mock, "4").WillReturnRows(mockTemplateItemRows(templated[1]))
+					mockCreateTagsFromTemplateQuery(mock, "4").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockCreateItemsFromTemplateQuery(mock, "4", "6", nil, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+					mockItemsFromTemplateQuery(mock, "6").WillReturnRows(mockItemRows(items[1]))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{List: &todo.List{ID: "6", Description: "Moving to Melbourne"}, Items: items[1:2]},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			list, items, err := repo.InstantiateTemplate(userContext(), "4", tt.Args.Instance)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Instantiate error")
+				return
+			}
+
+			require.NoError(t, err, "Instantiate error")
+			assert.Equal(t, tt.Want.List, list, "List")
+			assert.Equal(t, tt.Want.Items, items, "Items")
+		})
+	}
+}
+
+func mockTemplatesQuery(mock sqlmock.Sqlmock, page todo.Page) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Templates
+		SELECT id,
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon)
+		  FROM templates
+		 WHERE owner_id = $3
+		   AND ($1::int IS NULL OR id > $1)
+		 ORDER BY id
+		 LIMIT $2
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pageAfter(page), page.Limit+1, testUser.ID)
+}
+
+func mockTemplateQuery(mock sqlmock.Sqlmock, templateID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Template
+		SELECT id,
+		       description,
+		       EXTRACT(EPOCH FROM due_horizon)
+		  FROM templates
+		 WHERE id = $1
+		   AND owner_id = $2
+	`
+
+	return mock.ExpectQuery(q).WithArgs(templateID, testUser.ID)
+}
+
+func mockTemplateItemsQuery(mock sqlmock.Sqlmock, templateID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Template Items
+		SELECT id,
+		       parent_id,
+		       description,
+		       CASE WHEN due_days IS NOT NULL THEN ARRAY[due_days, EXTRACT(EPOCH FROM due_time)::int] END,
+		       priority,
+		       recurrence,
+		       tags,
+		       auto_complete,
+		       position
+		  FROM template_items
+		 WHERE template_id = $1
+		 ORDER BY position, id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(templateID)
+}
+
+func mockTemplateItemRows(items ...todo.TemplateItem) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "parent_id", "description", "due_offset", "priority", "recurrence", "tags", "auto_complete", "position"})
+
+	for _, i := range items {
+		rows.AddRow(i.ID, i.ParentID, i.Description, dueOffsetValue(i.DueOffset), int64(i.Priority), recurrenceValue(i.Recurrence), tagsValue(i.Tags), i.AutoComplete, i.Position)
+	}
+
+	return rows
+}
+
+func mockTemplateRows(templates ...todo.Template) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "description", "due_horizon"})
+
+	for _, t := range templates {
+		var horizon any
+		if t.DueHorizon != nil {
+			horizon = []byte(strconv.FormatFloat(time.Duration(*t.DueHorizon).Seconds(), 'f', -1, 64))
+		}
+
+		rows.AddRow(t.ID, t.Description, horizon)
+	}
+
+	return rows
+}
+
+// dueOffsetValue of the offset as Postgres would return it, as an array of the days and seconds into the day.
+func dueOffsetValue(o *todo.DueOffset) driver.Value {
+	if o == nil {
+		return nil
+	}
+
+	return fmt.Sprintf("{%d,%d}", o.Days, int64(o.TimeOfDay.Seconds()))
+}
+
+func mockCreateTemplateQuery(mock sqlmock.Sqlmock, listID string, description any) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Create TODO List Template
+		INSERT INTO templates (owner_id, description, due_horizon)
+		SELECT $3,
+		       COALESCE($2, description),
+		       due_horizon
+		  FROM lists
+		 WHERE id = $1
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon)
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID, description, testUser.ID)
+}
+
+// dueDate of an item, as queried when saving a template.
+type dueDate struct {
+	ID  string
+	Due time.Time
+}
+
+func mockItemDueDatesQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Item Due Dates
+		SELECT id,
+		       due
+		  FROM items
+		 WHERE list_id = $1
+		   AND due IS NOT NULL
+		   AND archived IS NULL
+		   AND deleted IS NULL
+		 ORDER BY due, id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
+
+func mockDueDateRows(dues ...dueDate) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "due"})
+
+	for _, d := range dues {
+		rows.AddRow(d.ID, d.Due)
+	}
+
+	return rows
+}
+
+func mockCopyToTemplateQuery(mock sqlmock.Sqlmock, templateID, listID string, itemIDs []string, days []int64, secs []float64) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Copy TODO List Items to Template
+		WITH copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('template_items', 'id')) AS copy_id
+		       FROM items
+		      WHERE list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		),
+		offsets AS (
+		     SELECT *
+		       FROM unnest($3::int[], $4::int[], $5::float8[]) AS o(item_id, days, secs)
+		)
+		INSERT INTO template_items (id, template_id, parent_id, description, due_days, due_time, priority, recurrence, tags, auto_complete, position)
+		SELECT c.copy_id,
+		       $1,
+		       p.copy_id,
+		       i.description,
+		       o.days,
+		       make_interval(secs => o.secs),
+		       i.priority,
+		       i.recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = i.id ORDER BY t.name),
+		       i.auto_complete,
+		       i.position
+		  FROM items i
+		  JOIN copies c ON c.id = i.id
+		  LEFT JOIN copies p ON p.id = i.parent_id
+		  LEFT JOIN offsets o ON o.item_id = i.id
+	`
+
+	return mock.ExpectExec(q).WithArgs(templateID, listID, pq.Array(itemIDs), pq.Array(days), pq.Array(secs))
+}
+
+func mockCreateListFromTemplateQuery(mock sqlmock.Sqlmock, templateID string, description any) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Create TODO List from Template
+		INSERT INTO lists (owner_id, description, due_horizon)
+		SELECT owner_id,
+		       COALESCE($2, description),
+		       due_horizon
+		  FROM templates
+		 WHERE id = $1
+		   AND owner_id = $3
+		RETURNING id,
+		          description,
+		          EXTRACT(EPOCH FROM due_horizon),
+		          archived,
+		          deleted
+	`
+
+	return mock.ExpectQuery(q).WithArgs(templateID, description, testUser.ID)
+}
+
+func mockCreateTagsFromTemplateQuery(mock sqlmock.Sqlmock, templateID string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Create TODO Item Tags from Template
+		INSERT INTO tags (name)
+		SELECT DISTINCT unnest(tags)
+		  FROM template_items
+		 WHERE template_id = $1
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	return mock.ExpectExec(q).WithArgs(templateID)
+}
+
+func mockCreateItemsFromTemplateQuery(mock sqlmock.Sqlmock, templateID, listID string, templateItemIDs []string, dues []time.Time) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Create TODO List Items from Template
+		WITH copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('items', 'id')) AS copy_id
+		       FROM template_items
+		      WHERE template_id = $1
+		),
+		dues AS (
+		     SELECT *
+		       FROM unnest($3::int[], $4::timestamp[]) AS d(template_item_id, due)
+		),
+		items_created AS (
+		     INSERT INTO items (id, list_id, parent_id, description, due, priority, recurrence, auto_complete, position)
+		     SELECT c.copy_id,
+		            $2,
+		            p.copy_id,
+		            ti.description,
+		            d.due,
+		            ti.priority,
+		            ti.recurrence,
+		            ti.auto_complete,
+		            ti.position
+		       FROM template_items ti
+		       JOIN copies c ON c.id = ti.id
+		       LEFT JOIN copies p ON p.id = ti.parent_id
+		       LEFT JOIN dues d ON d.template_item_id = ti.id
+		)
+		INSERT INTO item_tags (item_id, tag_id)
+		SELECT c.copy_id,
+		       t.id
+		  FROM template_items ti
+		  JOIN copies c ON c.id = ti.id
+		  JOIN tags t ON t.name = ANY(ti.tags)
+	`
+
+	return mock.ExpectExec(q).WithArgs(templateID, listID, pq.Array(templateItemIDs), pq.Array(dues))
+}
+
+func mockItemsFromTemplateQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Items from Template
+		SELECT id,
+		       description,
+		       due,
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
+		  FROM items
+		 WHERE list_id = $1
+		 ORDER BY position, id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// TransferItems of a TODO list to another list, returning the items as they are in that list. Subtasks are transferred
+// along with the items they belong to, while items which were subtasks of an item left behind become top-level items.
+// Transferred items are positioned after all items already in the list, in the same order as they were. Archived and
+// deleted items are not found, so cannot be transferred.
+//
+// Copies duplicate the items, along with their subtasks and tags, leaving the originals where they are. Moving items
+// to the list they are already in is not allowed, but copying them is.
+//
+// The items are either all transferred, or none are.
+func (r *ListRepository) TransferItems(ctx context.Context, listID string, transfer todo.ItemTransfer) ([]todo.Item, error) {
+	if !transfer.Copy && transfer.ListID == listID {
+		return nil, todo.InvalidArgumentError(fmt.Sprintf("items are already in list %q", listID))
+	}
+
+	target := `
+		-- Name: TODO List Transfer Target
+		SELECT id
+		  FROM lists
+		 WHERE id = $1
+		   FOR UPDATE
+	`
+
+	found := `
+		-- Name: TODO List Items to Transfer
+		SELECT id
+		  FROM items
+		 WHERE id = ANY($1::int[])
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+	`
+
+	// Archived and deleted subtasks are moved along with their parents, remaining hidden, so that they are never left
+	// behind as subtasks of items in another list. They are not copied, as the copies would not be hidden.
+	move := `
+		-- Name: Move TODO List Items
+		WITH RECURSIVE moved AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = ANY($1::int[])
+		        AND list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION
+		     SELECT i.id
+		       FROM items i
+		       JOIN moved m ON i.parent_id = m.id
+		)
+		UPDATE items
+		   SET list_id = $3,
+		       parent_id = CASE WHEN parent_id IN (SELECT id FROM moved) THEN parent_id END,
+		       position = position + (SELECT COALESCE(max(position), 0) FROM items WHERE list_id = $3)
+		 WHERE id IN (SELECT id FROM moved)
+	`
+
+	// IDs for the copies are allocated up front, so that subtasks can refer to the copies of their parents
+	copyItems := `
+		-- Name: Copy TODO List Items
+		WITH RECURSIVE copied AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = ANY($1::int[])
+		        AND list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION
+		     SELECT i.id
+		       FROM items i
+		       JOIN copied c ON i.parent_id = c.id
+		      WHERE i.archived IS NULL
+		        AND i.deleted IS NULL
+		),
+		copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('items', 'id')) AS copy_id
+		       FROM copied
+		),
+		items_copied AS (
+		     INSERT INTO items (id, list_id, parent_id, description, due, completed, priority, recurrence, auto_complete, position)
+		     SELECT c.copy_id,
+		            $3,
+		            p.copy_id,
+		            i.description,
+		            i.due,
+		            i.completed,
+		            i.priority,
+		            i.recurrence,
+		            i.auto_complete,
+		            i.position + (SELECT COALESCE(max(position), 0) FROM items WHERE list_id = $3)
+		       FROM items i
+		       JOIN copies c ON c.id = i.id
+		       LEFT JOIN copies p ON p.id = i.parent_id
+		),
+		tags_copied AS (
+		     INSERT INTO item_tags (item_id, tag_id)
+		     SELECT c.copy_id,
+		            it.tag_id
+		       FROM copies c
+		       JOIN item_tags it ON it.item_id = c.id
+		)
+		SELECT copy_id
+		  FROM copies
+		 WHERE id = ANY($1::int[])
+	`
+
+	transferred := `
+		-- Name: Transferred TODO List Items
+		SELECT ` + itemColumns + `
+		  FROM items
+		 WHERE id = ANY($1::int[])
+		 ORDER BY position, id
+	`
+
+	idCol := func(id *string) []any { return []any{id} }
+
+	var items []todo.Item
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, id := range []string{listID, transfer.ListID} {
+			if err := r.checkAccess(ctx, tx, id, todo.RoleEditor, todo.Include{}); err != nil {
+				return err
+			}
+		}
+
+		// The target list is locked, so that items transferred concurrently are not given the same positions
+		_, err := queryRow(ctx, tx, idCol, target, transfer.ListID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return todo.NotFoundError(fmt.Sprintf("list with id %q does not exist", transfer.ListID))
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to query todo list %q: %w", transfer.ListID, err)
+		}
+
+		ids, err := queryRows(ctx, tx, idCol, found, pq.Array(transfer.ItemIDs), listID)
+		if err != nil {
+			return fmt.Errorf("failed to query items of todo list %q: %w", listID, err)
+		}
+
+		if missing := missingID(transfer.ItemIDs, ids); missing != "" {
+			return itemNotFound(listID, missing)
+		}
+
+		if transfer.Copy {
+			if ids, err = queryRows(ctx, tx, idCol, copyItems, pq.Array(transfer.ItemIDs), listID, transfer.ListID); err != nil {
+				return fmt.Errorf("failed to copy items of todo list %q to %q: %w", listID, transfer.ListID, err)
+			}
+		} else if _, err := tx.ExecContext(ctx, move, pq.Array(transfer.ItemIDs), listID, transfer.ListID); err != nil {
+			return fmt.Errorf("failed to move items of todo list %q to %q: %w", listID, transfer.ListID, err)
+		}
+
+		if items, err = queryRows(ctx, tx, itemCols, transferred, pq.Array(ids)); err != nil {
+			return fmt.Errorf("failed to query items transferred to todo list %q: %w", transfer.ListID, err)
+		}
+
+		return nil
+	})
+
+	return items, err
+}
+
+// missingID of those wanted which was not found, or empty when all were found.
+func missingID(wanted, found []string) string {
+	seen := make(map[string]bool, len(found))
+	for _, id := range found {
+		seen[id] = true
+	}
+
+	for _, id := range wanted {
+		if !seen[id] {
+			return id
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,373 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestTransferItems(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID   string
+		Transfer todo.ItemTransfer
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		Items []todo.Item
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Moved to the same List": {
+			Args:   args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "1"}},
+			Fields: fields{MockExpectations: func(sqlmock.Sqlmock) {}},
+			Want:   want{Error: todo.InvalidArgumentError(`items are already in list "1"`)},
+		},
+		"Target List does not exist": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockNoListAccess(mock, "2")
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`list with id "2" does not exist`)},
+		},
+		"Target Query failure": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Item does not exist": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3", "4"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3", "4").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "4" does not exist in list "1"`)},
+		},
+		"Archived or Deleted Item": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: todo.NotFoundError(`item with id "3" does not exist in list "1"`)},
+		},
+		"Move failure": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mockMoveItemsQuery(mock, "1", "2", "3").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Moved": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"4", "3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "4", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3").AddRow("4"))
+					mockMoveItemsQuery(mock, "1", "2", "4", "3").WillReturnResult(sqlmock.NewResult(0, 3))
+					mockTransferredItemsQuery(mock, "3", "4").WillReturnRows(mockItemRows(
+						todo.Item{ID: "3", Description: "Washing", Position: 5120},
+						todo.Item{ID: "4", Description: "Ironing", Progress: &todo.Progress{Done: 1, Total: 1}, Position: 6144},
+					))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{
+				Items: []todo.Item{
+					{ID: "3", Description: "Washing", Position: 5120},
+					{ID: "4", Description: "Ironing", Progress: &todo.Progress{Done: 1, Total: 1}, Position: 6144},
+				},
+			},
+		},
+		"Copy failure": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2", Copy: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mockCopyItemsQuery(mock, "1", "2", "3").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Copied": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2", Copy: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mockCopyItemsQuery(mock, "1", "2", "3").WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow("12"))
+					mockTransferredItemsQuery(mock, "12").WillReturnRows(mockItemRows(
+						todo.Item{ID: "12", Description: "Washing", Tags: todo.Tags{"@home"}, Position: 5120},
+					))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "12", Description: "Washing", Tags: todo.Tags{"@home"}, Position: 5120}}},
+		},
+		"Copied without a Deleted Subtask": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"4"}, ListID: "2", Copy: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "4").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("4"))
+					mockCopyItemsQuery(mock, "1", "2", "4").WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow("13"))
+					mockTransferredItemsQuery(mock, "13").WillReturnRows(mockItemRows(todo.Item{ID: "13", Description: "Ironing", Position: 5120}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "13", Description: "Ironing", Position: 5120}}},
+		},
+		"Copied to the same List": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "1", Copy: true}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "1")
+					mockTransferTargetQuery(mock, "1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mockCopyItemsQuery(mock, "1", "1", "3").WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow("12"))
+					mockTransferredItemsQuery(mock, "12").WillReturnRows(mockItemRows(todo.Item{ID: "12", Description: "Washing", Position: 5120}))
+					mock.ExpectCommit()
+				},
+			},
+			Want: want{Items: []todo.Item{{ID: "12", Description: "Washing", Position: 5120}}},
+		},
+		"Transferred Items Query failure": {
+			Args: args{ListID: "1", Transfer: todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mock.ExpectBegin()
+					mockListAccess(mock, "1")
+					mockListAccess(mock, "2")
+					mockTransferTargetQuery(mock, "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+					mockItemsToTransferQuery(mock, "1", "3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+					mockMoveItemsQuery(mock, "1", "2", "3").WillReturnResult(sqlmock.NewResult(0, 1))
+					mockTransferredItemsQuery(mock, "3").WillReturnError(queryErr)
+					mock.ExpectRollback()
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewListRepository(db, time.Duration(defaultHorizon), time.UTC, maxSubtaskDepth)
+
+			tt.Fields.MockExpectations(mock)
+
+			items, err := repo.TransferItems(userContext(), tt.Args.ListID, tt.Args.Transfer)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Transfer error")
+				return
+			}
+
+			require.NoError(t, err, "Transfer error")
+			assert.Equal(t, tt.Want.Items, items, "Items")
+		})
+	}
+}
+
+func mockTransferTargetQuery(mock sqlmock.Sqlmock, listID string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Transfer Target
+		SELECT id
+		  FROM lists
+		 WHERE id = $1
+		   FOR UPDATE
+	`
+
+	return mock.ExpectQuery(q).WithArgs(listID)
+}
+
+func mockItemsToTransferQuery(mock sqlmock.Sqlmock, listID string, itemIDs ...string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: TODO List Items to Transfer
+		SELECT id
+		  FROM items
+		 WHERE id = ANY($1::int[])
+		   AND list_id = $2
+		   AND archived IS NULL
+		   AND deleted IS NULL
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pq.Array(itemIDs), listID)
+}
+
+func mockMoveItemsQuery(mock sqlmock.Sqlmock, listID, targetID string, itemIDs ...string) *sqlmock.ExpectedExec {
+	q := `
+		-- Name: Move TODO List Items
+		WITH RECURSIVE moved AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = ANY($1::int[])
+		        AND list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION
+		     SELECT i.id
+		       FROM items i
+		       JOIN moved m ON i.parent_id = m.id
+		)
+		UPDATE items
+		   SET list_id = $3,
+		       parent_id = CASE WHEN parent_id IN (SELECT id FROM moved) THEN parent_id END,
+		       position = position + (SELECT COALESCE(max(position), 0) FROM items WHERE list_id = $3)
+		 WHERE id IN (SELECT id FROM moved)
+	`
+
+	return mock.ExpectExec(q).WithArgs(pq.Array(itemIDs), listID, targetID)
+}
+
+func mockCopyItemsQuery(mock sqlmock.Sqlmock, listID, targetID string, itemIDs ...string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Copy TODO List Items
+		WITH RECURSIVE copied AS (
+		     SELECT id
+		       FROM items
+		      WHERE id = ANY($1::int[])
+		        AND list_id = $2
+		        AND archived IS NULL
+		        AND deleted IS NULL
+		      UNION
+		     SELECT i.id
+		       FROM items i
+		       JOIN copied c ON i.parent_id = c.id
+		      WHERE i.archived IS NULL
+		        AND i.deleted IS NULL
+		),
+		copies AS (
+		     SELECT id,
+		            nextval(pg_get_serial_sequence('items', 'id')) AS copy_id
+		       FROM copied
+		),
+		items_copied AS (
+		     INSERT INTO items (id, list_id, parent_id, description, due, completed, priority, recurrence, auto_complete, position)
+		     SELECT c.copy_id,
+		            $3,
+		            p.copy_id,
+		            i.description,
+		            i.due,
+		            i.completed,
+		            i.priority,
+		            i.recurrence,
+		            i.auto_complete,
+		            i.position + (SELECT COALESCE(max(position), 0) FROM items WHERE list_id = $3)
+		       FROM items i
+		       JOIN copies c ON c.id = i.id
+		       LEFT JOIN copies p ON p.id = i.parent_id
+		),
+		tags_copied AS (
+		     INSERT INTO item_tags (item_id, tag_id)
+		     SELECT c.copy_id,
+		            it.tag_id
+		       FROM copies c
+		       JOIN item_tags it ON it.item_id = c.id
+		)
+		SELECT copy_id
+		  FROM copies
+		 WHERE id = ANY($1::int[])
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pq.Array(itemIDs), listID, targetID)
+}
+
+func mockTransferredItemsQuery(mock sqlmock.Sqlmock, itemIDs ...string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Transferred TODO List Items
+		SELECT id,
+		       description,
+		       due,
+		       completed,
+		       priority,
+		       recurrence,
+		       ARRAY(SELECT t.name FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = items.id ORDER BY t.name),
+		       parent_id,
+		       auto_complete,
+		       (SELECT ARRAY[count(*) FILTER (WHERE s.completed IS NOT NULL), count(*)] FROM items s WHERE s.parent_id = items.id AND s.deleted IS NULL),
+		       position,
+		       archived,
+		       deleted
+		  FROM items
+		 WHERE id = ANY($1::int[])
+		 ORDER BY position, id
+	`
+
+	return mock.ExpectQuery(q).WithArgs(pq.Array(itemIDs))
+}
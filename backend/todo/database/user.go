@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// ProvisionUser with the name, which is created the first time it is seen.
+func (r *UserRepository) ProvisionUser(ctx context.Context, name string) (*todo.User, error) {
+	return provisionUser(ctx, r.db, name)
+}
+
+// provisionUser with the name, as part of an ongoing transaction or otherwise.
+func provisionUser(ctx context.Context, db rowQuerier, name string) (*todo.User, error) {
+	// When the user already exists nothing is inserted, and the existing user is found instead
+	query := `
+		-- Name: Provision User
+		WITH inserted AS (
+		     INSERT INTO users (name)
+		     VALUES ($1)
+		     ON CONFLICT (name) DO NOTHING
+		     RETURNING id,
+		               name
+		)
+		SELECT id,
+		       name
+		  FROM inserted
+		 UNION ALL
+		SELECT id,
+		       name
+		  FROM users
+		 WHERE name = $1
+	`
+
+	user, err := queryRow(ctx, db, userCols, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to provision user %q: %w", name, err)
+	}
+
+	return user, nil
+}
+
+func userCols(u *todo.User) []any {
+	return []any{&u.ID, &u.Name}
+}
+
+// userID of the user authenticated within the context, who lists are scoped to.
+func userID(ctx context.Context) (string, error) {
+	user, ok := todo.UserFromContext(ctx)
+	if !ok {
+		return "", todo.UnauthenticatedError("no user is authenticated")
+	}
+
+	return user.ID, nil
+}
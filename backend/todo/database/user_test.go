@@ -0,0 +1,114 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/database"
+)
+
+func TestProvisionUser(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Name string
+	}
+
+	type fields struct {
+		MockExpectations func(sqlmock.Sqlmock)
+	}
+
+	type want struct {
+		Error error
+		User  *todo.User
+	}
+
+	queryErr := errors.New("failed to execute query")
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Args: args{Name: "alice"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnError(queryErr)
+				},
+			},
+			Want: want{Error: queryErr},
+		},
+		"Provisioned": {
+			Args: args{Name: "alice"},
+			Fields: fields{
+				MockExpectations: func(mock sqlmock.Sqlmock) {
+					mockProvisionUserQuery(mock, "alice").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("7", "alice"))
+				},
+			},
+			Want: want{User: &todo.User{ID: "7", Name: "alice"}},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := mockDB(t)
+			repo := database.NewUserRepository(db)
+
+			tt.Fields.MockExpectations(mock)
+
+			user, err := repo.ProvisionUser(context.Background(), tt.Args.Name)
+
+			assert.NoError(t, mock.ExpectationsWereMet(), "DB Expectations")
+
+			if tt.Want.Error != nil {
+				assert.ErrorIs(t, err, tt.Want.Error, "Provision error")
+				return
+			}
+
+			require.NoError(t, err, "Provision error")
+			assert.Equal(t, tt.Want.User, user, "User")
+		})
+	}
+}
+
+// testUser who is authenticated when using the repository under test.
+var testUser = todo.User{ID: "42", Name: "alice"}
+
+// userContext with the test user authenticated.
+func userContext() context.Context {
+	return todo.WithUser(context.Background(), testUser)
+}
+
+func mockProvisionUserQuery(mock sqlmock.Sqlmock, name string) *sqlmock.ExpectedQuery {
+	q := `
+		-- Name: Provision User
+		WITH inserted AS (
+		     INSERT INTO users (name)
+		     VALUES ($1)
+		     ON CONFLICT (name) DO NOTHING
+		     RETURNING id,
+		               name
+		)
+		SELECT id,
+		       name
+		  FROM inserted
+		 UNION ALL
+		SELECT id,
+		       name
+		  FROM users
+		 WHERE name = $1
+	`
+
+	return mock.ExpectQuery(q).WithArgs(name)
+}
@@ -0,0 +1,66 @@
+package todo
+
+import (
+	"context"
+	"time"
+)
+
+// Change of a TODO list or item, as recorded in its history. History is append-only, so changes are never modified,
+// and outlive the lists and items they are of.
+type Change struct {
+	ID     string `json:"id"`
+	ListID string `json:"listId"`
+	// ItemID of the item which changed. Nil when the list itself changed.
+	ItemID *string `json:"itemId"`
+	// Actor who made the change. Nil when not known.
+	Actor     *string         `json:"actor"`
+	Time      time.Time       `json:"time"`
+	Operation ChangeOperation `json:"operation"`
+	// Fields which changed, keyed by their name, with their values from before and after the change.
+	Fields map[string]FieldChange `json:"fields"`
+}
+
+// FieldChange of the value of a field, which is nil when the field had no value, such as before it was created.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// ChangeOperation which changed a TODO list or item.
+type ChangeOperation string
+
+const (
+	// ChangeCreate of a list or item, where all fields are new.
+	ChangeCreate ChangeOperation = "create"
+	// ChangeUpdate of the fields of a list or item, including when it is archived or deleted.
+	ChangeUpdate ChangeOperation = "update"
+	// ChangeDelete of a list or item permanently, once purged from the trash.
+	ChangeDelete ChangeOperation = "delete"
+	// ChangeTag of an item, adding the tag.
+	ChangeTag ChangeOperation = "tag"
+	// ChangeUntag of an item, removing the tag.
+	ChangeUntag ChangeOperation = "untag"
+)
+
+// HistoryFilter restricts which changes are retrieved. Zero values do not filter.
+type HistoryFilter struct {
+	// ItemID of the item to retrieve changes of, rather than changes of the list and all of its items.
+	ItemID string
+	// Since changes made at or after this time.
+	Since *time.Time
+	// Until changes made before this time.
+	Until *time.Time
+}
+
+type actorKey struct{}
+
+// WithActor who is making changes within the context, which is recorded in the history of what they change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor making changes within the context. Not ok when unknown.
+func Actor(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
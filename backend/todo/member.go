@@ -0,0 +1,47 @@
+package todo
+
+import "fmt"
+
+// Role of a user on a TODO list, which determines what they are allowed to do with it. Each role allows everything
+// that the roles below it do.
+type Role string
+
+const (
+	// RoleViewer may read the list and its items.
+	RoleViewer Role = "viewer"
+	// RoleEditor may also change the list and its items.
+	RoleEditor Role = "editor"
+	// RoleOwner may also archive or delete the list, and manage who it is shared with.
+	RoleOwner Role = "owner"
+)
+
+var roleRanks = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// ParseRole which must be one of the known roles.
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := roleRanks[r]; !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("unknown role %q, must be one of %q, %q or %q", s, RoleViewer, RoleEditor, RoleOwner))
+	}
+
+	return r, nil
+}
+
+// Allows what the required role is allowed to do.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[required]
+}
+
+// Member of a TODO list, who it has been shared with.
+type Member struct {
+	User User `json:"user"`
+	Role Role `json:"role"`
+}
+
+// ForbiddenError occurs when the user may access a value, but their role does not allow what they are trying to do.
+type ForbiddenError string
+
+func (f ForbiddenError) Error() string {
+	return string(f)
+}
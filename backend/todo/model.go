@@ -1,6 +1,10 @@
 package todo
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // NotFoundError occurs when trying to retrieve a specific value, and no such value exists.
 type NotFoundError string
@@ -9,20 +13,303 @@ func (n NotFoundError) Error() string {
 	return string(n)
 }
 
+// InvalidArgumentError occurs when a value provided is not acceptable, such as a malformed page cursor.
+type InvalidArgumentError string
+
+func (i InvalidArgumentError) Error() string {
+	return string(i)
+}
+
+// Page of results to be retrieved. Pages are keyset based, continuing on from the last result of the previous page,
+// so that retrieving later pages is as efficient as the first.
+type Page struct {
+	// After is the key of the last result of the previous page. Empty when retrieving the first page.
+	After string
+	// Limit of the number of results included in the page.
+	Limit int
+}
+
+// ItemStatus by which TODO items may be filtered.
+type ItemStatus string
+
+const (
+	// ItemStatusOpen items have not been completed.
+	ItemStatusOpen ItemStatus = "open"
+	// ItemStatusCompleted items have been completed.
+	ItemStatusCompleted ItemStatus = "completed"
+	// ItemStatusOverdue items have not been completed, and are past their due date.
+	ItemStatusOverdue ItemStatus = "overdue"
+)
+
+// ItemSort order in which TODO items are retrieved.
+type ItemSort string
+
+const (
+	// ItemSortPosition orders items by their position, which users may arrange by moving items. This is the default.
+	ItemSortPosition ItemSort = "position"
+	// ItemSortID orders items by their ID, which is the order they were created.
+	ItemSortID ItemSort = "id"
+	// ItemSortDue orders items by their due date, soonest first. Items without a due date are last.
+	ItemSortDue ItemSort = "due"
+	// ItemSortDueDesc orders items by their due date, latest first. Items without a due date are last.
+	ItemSortDueDesc ItemSort = "-due"
+	// ItemSortDescription orders items alphabetically by their description.
+	ItemSortDescription ItemSort = "description"
+)
+
+// TagMatch determines whether items must have any, or all, of the tags being filtered on.
+type TagMatch string
+
+const (
+	// TagMatchAny items have at least one of the tags. This is the default.
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll items have every one of the tags.
+	TagMatchAll TagMatch = "all"
+)
+
+// ItemFilter restricts which TODO items are retrieved, and the order they are retrieved in. Zero values do not filter.
+type ItemFilter struct {
+	Status    ItemStatus
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	// Tags which items must have, according to TagMatch. Tags must be distinct.
+	Tags     []string
+	TagMatch TagMatch
+	Sort     ItemSort
+	// Tree retrieves only top-level items, which the filter and sort apply to, with all of their subtasks nested
+	// within them.
+	Tree bool
+	// Include items which are archived or deleted, which are otherwise excluded.
+	Include Include
+}
+
+// Include lists or items which are excluded by default.
+type Include struct {
+	// Archived lists or items.
+	Archived bool
+	// Deleted lists or items, which are in the trash.
+	Deleted bool
+}
+
 // DueList of TODO items, where they are overdue or must be completed soon.
 type DueList struct {
 	DueItems []Item `json:"dueItems"`
-	List     List   `json:"list"`
+	// Horizon applied to determine which items are due soon: those due before now + Horizon.
+	Horizon Duration `json:"horizon"`
+	List    List     `json:"list"`
+	// Role of the authenticated user on the list.
+	Role Role `json:"role,omitempty"`
+	// Shared is when the list is owned by another user, who has shared it with the authenticated user.
+	Shared bool `json:"-"`
+	// UrgentDue is the number of the due items which are of urgent priority.
+	UrgentDue int `json:"urgentDue"`
 }
 
+// List of TODO items.
 type List struct {
 	ID          string `json:"id"`
 	Description string `json:"description"`
+	// DueHorizon for items on this list to be considered due soon. Nil when the server default is used.
+	DueHorizon *Duration `json:"dueHorizon,omitempty"`
+	// Archived is when the list was archived, which hides it without deleting it. Nil unless archived.
+	Archived *time.Time `json:"archived,omitempty"`
+	// Deleted is when the list was moved to the trash, from which it is later purged. Nil unless deleted.
+	Deleted *time.Time `json:"deleted,omitempty"`
 }
 
+// ListUpdate of the fields of a TODO list. Nil fields are left unchanged.
+type ListUpdate struct {
+	Description *string
+	// DueHorizon of the list, which is only changed when SetDueHorizon is true. This allows it to be cleared.
+	DueHorizon    *Duration
+	SetDueHorizon bool
+}
+
+// Item to be done, which belongs to a TODO list. Items may be broken down into subtasks, which are items having the
+// item as their parent.
 type Item struct {
-	ID          string     `json:"id"`
+	ID string `json:"id"`
+	// ParentID of the item which this is a subtask of. Nil for top-level items.
+	ParentID    *string    `json:"parentId"`
 	Description string     `json:"description"`
 	Due         *time.Time `json:"due"`
 	Completed   *time.Time `json:"completed"`
+	Priority    Priority   `json:"priority"`
+	// Recurrence of the item, when completing it should create the next occurrence. Nil for one-off items.
+	Recurrence *Recurrence `json:"recurrence"`
+	Tags       Tags        `json:"tags"`
+	// AutoComplete the item once all of its subtasks have been completed.
+	AutoComplete bool `json:"autoComplete"`
+	// Progress of the subtasks of the item. Nil when it has no subtasks.
+	Progress *Progress `json:"progress"`
+	// Subtasks of the item, which are only included when items are retrieved as a tree.
+	Subtasks []Item `json:"subtasks,omitempty"`
+	// Position of the item within its list, relative to the other items. Positions are ranks with gaps between them,
+	// which are not meaningful to clients, so are not included in JSON.
+	Position int64 `json:"-"`
+	// Archived is when the item was archived, which hides it without deleting it. Nil unless archived.
+	Archived *time.Time `json:"archived,omitempty"`
+	// Deleted is when the item was moved to the trash, from which it is later purged. Nil unless deleted.
+	Deleted *time.Time `json:"deleted,omitempty"`
+}
+
+// ItemMove of a TODO item to a new position, next to another item of the same list. Exactly one of Before or After is
+// set.
+type ItemMove struct {
+	// Before is the ID of the item which the moved item is placed immediately before.
+	Before string
+	// After is the ID of the item which the moved item is placed immediately after.
+	After string
+}
+
+// ItemUpdate of the fields of a TODO item. Nil fields are left unchanged.
+type ItemUpdate struct {
+	Description *string
+	// Due date of the item, which is only changed when SetDue is true. This allows the due date to be cleared.
+	Due      *time.Time
+	SetDue   bool
+	Priority *Priority
+	// Recurrence of the item, which is only changed when SetRecurrence is true. This allows it to be cleared.
+	Recurrence    *Recurrence
+	SetRecurrence bool
+	AutoComplete  *bool
+}
+
+// ItemTransfer of TODO items to another list, which either moves them or copies them. Subtasks are transferred along
+// with the items they belong to.
+type ItemTransfer struct {
+	// ItemIDs of the items to transfer, which must be distinct.
+	ItemIDs []string
+	// ListID of the list which the items are transferred to.
+	ListID string
+	// Copy the items, leaving the originals where they are, rather than moving them.
+	Copy bool
+}
+
+// Progress of the subtasks of a TODO item, which is represented in JSON as a summary, such as "3/5 done".
+type Progress struct {
+	Done  int
+	Total int
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d done", p.Done, p.Total)
+}
+
+func (p Progress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// Priority of a TODO item, which is represented in JSON by its name, such as "urgent". The zero value is normal
+// priority, and higher priorities have greater values.
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+	PriorityUrgent Priority = 2
+)
+
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityNormal: "normal",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	name, ok := priorityNames[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown priority %d", int(p))
+	}
+
+	return json.Marshal(name)
+}
+
+func (p *Priority) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("priority must be a string: %w", err)
+	}
+
+	for v, name := range priorityNames {
+		if name == s {
+			*p = v
+			return nil
+		}
+	}
+
+	return fmt.Errorf("priority must be one of: low, normal, high, urgent")
+}
+
+// SearchKind of result found when searching, which is either a TODO list or an item.
+type SearchKind string
+
+const (
+	SearchKindList SearchKind = "list"
+	SearchKindItem SearchKind = "item"
+)
+
+// SearchResult of a TODO list or item whose description matches a search query.
+type SearchResult struct {
+	Kind SearchKind `json:"kind"`
+	ID   string     `json:"id"`
+	// ListID which the item belongs to. For a list, this is the same as ID.
+	ListID      string `json:"listId"`
+	Description string `json:"description"`
+	// Snippet of the description as HTML, which is escaped, with matching terms highlighted by <mark> tags. The
+	// description is plain text.
+	Snippet string `json:"snippet"`
+	// Rank of how well the result matches the query. Results are ordered with the highest rank first.
+	Rank float64 `json:"rank"`
+}
+
+// Tags of a TODO item, in alphabetical order. Represented in JSON as an empty array rather than null, when there are none.
+type Tags []string
+
+func (t Tags) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]string(t))
+}
+
+// Tag applied to TODO items, such as "@home" or "@work".
+type Tag struct {
+	Name string `json:"name"`
+	// Count of the items which have the tag.
+	Count int `json:"count"`
+}
+
+// Duration which is represented in JSON as a string, such as "36h0m0s", rather than as a number of nanoseconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
 }
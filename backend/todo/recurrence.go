@@ -0,0 +1,264 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency at which a recurring TODO item repeats.
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// Recurrence of a TODO item, so that completing it creates the next occurrence. Recurrences are represented as a subset
+// of iCalendar RRULEs (RFC 5545), such as "FREQ=WEEKLY;BYDAY=MO,TH" or "FREQ=MONTHLY;BYMONTHDAY=-1".
+//
+// Occurrences are scheduled from the due date of the item, skipping any which have already passed when it is completed.
+// The "X-FROM=COMPLETION" extension instead schedules the next occurrence from when the item was completed, such as
+// "FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION" for every 3 days after completion.
+type Recurrence struct {
+	Frequency Frequency
+	// Interval between occurrences, in units of the frequency. At least 1.
+	Interval int
+	// Weekdays on which weekly occurrences fall. When empty, the weekday of the item is used.
+	Weekdays []time.Weekday
+	// MonthDay on which monthly occurrences fall, where negative values count back from the end of the month. Months
+	// without such a day are skipped. When zero, the day of the month of the item is used.
+	MonthDay int
+	// FromCompletion schedules the next occurrence from when the item was completed, rather than when it was due.
+	FromCompletion bool
+}
+
+// weekdays in the form of the RRULE BYDAY part, indexed by time.Weekday.
+var weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrence from an RRULE, such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO".
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	r := Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("recurrence rule part %q must be of the form NAME=VALUE", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(value)); f {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+				r.Frequency = f
+			default:
+				return nil, fmt.Errorf("recurrence FREQ must be one of: DAILY, WEEKLY, MONTHLY")
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("recurrence INTERVAL must be a positive integer")
+			}
+
+			r.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := parseWeekday(day)
+				if !ok {
+					return nil, fmt.Errorf("recurrence BYDAY must be a list of weekdays, such as MO,WE,FR")
+				}
+
+				r.Weekdays = append(r.Weekdays, wd)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n == 0 || n < -31 || n > 31 {
+				return nil, fmt.Errorf("recurrence BYMONTHDAY must be between 1 and 31, or -31 and -1")
+			}
+
+			r.MonthDay = n
+		case "X-FROM":
+			if !strings.EqualFold(value, "COMPLETION") {
+				return nil, fmt.Errorf("recurrence X-FROM must be COMPLETION")
+			}
+
+			r.FromCompletion = true
+		default:
+			return nil, fmt.Errorf("recurrence rule part %q is not supported", name)
+		}
+	}
+
+	switch {
+	case r.Frequency == "":
+		return nil, fmt.Errorf("recurrence FREQ is required")
+	case len(r.Weekdays) > 0 && r.Frequency != FrequencyWeekly:
+		return nil, fmt.Errorf("recurrence BYDAY requires FREQ=WEEKLY")
+	case r.MonthDay != 0 && r.Frequency != FrequencyMonthly:
+		return nil, fmt.Errorf("recurrence BYMONTHDAY requires FREQ=MONTHLY")
+	case r.FromCompletion && r.Frequency != FrequencyDaily:
+		return nil, fmt.Errorf("recurrence X-FROM=COMPLETION requires FREQ=DAILY")
+	}
+
+	return &r, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd, name := range weekdays {
+		if strings.EqualFold(s, name) {
+			return time.Weekday(wd), true
+		}
+	}
+
+	return 0, false
+}
+
+// String of the recurrence as an RRULE.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, wd := range r.Weekdays {
+			days[i] = weekdays[wd]
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+
+	if r.FromCompletion {
+		parts = append(parts, "X-FROM=COMPLETION")
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r Recurrence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Recurrence) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("recurrence must be a string: %w", err)
+	}
+
+	v, err := ParseRecurrence(s)
+	if err != nil {
+		return err
+	}
+
+	*r = *v
+
+	return nil
+}
+
+// Next due date of an item with this recurrence, which was due at the given time (nil when it had no due date), and
+// was completed at the given time. Dates are calculated in the location, so that occurrences remain at the same local
+// time of day across daylight saving changes. Not ok when there is no next occurrence, such as monthly on the 30th of
+// February.
+func (r Recurrence) Next(due *time.Time, completed time.Time, loc *time.Location) (time.Time, bool) {
+	completed = completed.In(loc)
+
+	if r.FromCompletion || due == nil {
+		return r.after(completed)
+	}
+
+	next, ok := r.after(due.In(loc))
+	for ok && !next.After(completed) {
+		next, ok = r.after(next)
+	}
+
+	return next, ok
+}
+
+// after is the first occurrence strictly after t, at the same time of day.
+func (r Recurrence) after(t time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		return t.AddDate(0, 0, interval), true
+	case FrequencyWeekly:
+		days := r.Weekdays
+		if len(days) == 0 {
+			days = []time.Weekday{t.Weekday()}
+		}
+
+		// Weeks start on Monday, as is the RRULE default
+		week := civilDay(t) - mondayOffset(t.Weekday())
+
+		for d := 1; d <= 7*(interval+1); d++ {
+			c := t.AddDate(0, 0, d)
+			if !containsWeekday(days, c.Weekday()) {
+				continue
+			}
+
+			if weeks := (civilDay(c) - mondayOffset(c.Weekday()) - week) / 7; weeks%interval == 0 {
+				return c, true
+			}
+		}
+	case FrequencyMonthly:
+		// Within 4 years the same months recur, including a leap day, so any valid day will have been found by then
+		for k := 0; k <= 12*4*interval; k += interval {
+			first := time.Date(t.Year(), t.Month()+time.Month(k), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			n := daysIn(first)
+
+			day := r.MonthDay
+			switch {
+			case day == 0:
+				day = t.Day()
+			case day < 0:
+				day = n + 1 + day
+			}
+
+			if day < 1 || day > n {
+				continue
+			}
+
+			if c := first.AddDate(0, 0, day-1); c.After(t) {
+				return c, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// civilDay number of the date of t in its location, which is unaffected by daylight saving changes.
+func civilDay(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+// mondayOffset is the number of days since the start of the week (Monday) of the weekday.
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func containsWeekday(days []time.Weekday, wd time.Weekday) bool {
+	for _, d := range days {
+		if d == wd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// daysIn the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
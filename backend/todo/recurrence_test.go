@@ -0,0 +1,245 @@
+package todo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		Error      string
+		Recurrence *todo.Recurrence
+		Rule       string
+	}
+
+	testTable := map[string]struct {
+		Rule string
+		Want want
+	}{
+		"Daily": {
+			Rule: "FREQ=DAILY",
+			Want: want{Recurrence: &todo.Recurrence{Frequency: todo.FrequencyDaily, Interval: 1}, Rule: "FREQ=DAILY"},
+		},
+		"Every 3 days after completion": {
+			Rule: "freq=daily;interval=3;x-from=completion",
+			Want: want{
+				Recurrence: &todo.Recurrence{Frequency: todo.FrequencyDaily, Interval: 3, FromCompletion: true},
+				Rule:       "FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION",
+			},
+		},
+		"Fortnightly on weekdays": {
+			Rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR",
+			Want: want{
+				Recurrence: &todo.Recurrence{Frequency: todo.FrequencyWeekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}},
+				Rule:       "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR",
+			},
+		},
+		"Last day of the month": {
+			Rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			Want: want{Recurrence: &todo.Recurrence{Frequency: todo.FrequencyMonthly, Interval: 1, MonthDay: -1}, Rule: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		},
+		"Missing Frequency": {
+			Rule: "INTERVAL=2",
+			Want: want{Error: "recurrence FREQ is required"},
+		},
+		"Unsupported Frequency": {
+			Rule: "FREQ=YEARLY",
+			Want: want{Error: "recurrence FREQ must be one of: DAILY, WEEKLY, MONTHLY"},
+		},
+		"Invalid Interval": {
+			Rule: "FREQ=DAILY;INTERVAL=0",
+			Want: want{Error: "recurrence INTERVAL must be a positive integer"},
+		},
+		"Invalid Weekday": {
+			Rule: "FREQ=WEEKLY;BYDAY=MO,XX",
+			Want: want{Error: "recurrence BYDAY must be a list of weekdays, such as MO,WE,FR"},
+		},
+		"Invalid Month Day": {
+			Rule: "FREQ=MONTHLY;BYMONTHDAY=32",
+			Want: want{Error: "recurrence BYMONTHDAY must be between 1 and 31, or -31 and -1"},
+		},
+		"Weekdays without Weekly": {
+			Rule: "FREQ=DAILY;BYDAY=MO",
+			Want: want{Error: "recurrence BYDAY requires FREQ=WEEKLY"},
+		},
+		"From Completion without Daily": {
+			Rule: "FREQ=MONTHLY;X-FROM=COMPLETION",
+			Want: want{Error: "recurrence X-FROM=COMPLETION requires FREQ=DAILY"},
+		},
+		"Unsupported Part": {
+			Rule: "FREQ=DAILY;COUNT=3",
+			Want: want{Error: `recurrence rule part "COUNT" is not supported`},
+		},
+		"Malformed Part": {
+			Rule: "FREQ=DAILY;",
+			Want: want{Error: `recurrence rule part "" must be of the form NAME=VALUE`},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := todo.ParseRecurrence(tt.Rule)
+
+			if tt.Want.Error != "" {
+				assert.EqualError(t, err, tt.Want.Error, "Parse error")
+				return
+			}
+
+			require.NoError(t, err, "Parse error")
+			assert.Equal(t, tt.Want.Recurrence, r, "Recurrence")
+			assert.Equal(t, tt.Want.Rule, r.String(), "Rule")
+		})
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	t.Parallel()
+
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err, "Loading location")
+
+	type args struct {
+		Completed time.Time
+		Due       *time.Time
+		Loc       *time.Location
+		Rule      string
+	}
+
+	type want struct {
+		Next time.Time
+		OK   bool
+	}
+
+	at := func(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	testTable := map[string]struct {
+		Args args
+		Want want
+	}{
+		"Daily": {
+			Args: args{Rule: "FREQ=DAILY", Due: ptr(at(2023, time.June, 29, 8, time.UTC)), Completed: at(2023, time.June, 29, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.June, 30, 8, time.UTC), OK: true},
+		},
+		"Daily, skipping occurrences missed before completion": {
+			Args: args{Rule: "FREQ=DAILY", Due: ptr(at(2023, time.June, 26, 8, time.UTC)), Completed: at(2023, time.June, 29, 10, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.June, 30, 8, time.UTC), OK: true},
+		},
+		"Daily without a due date": {
+			Args: args{Rule: "FREQ=DAILY;INTERVAL=2", Completed: at(2023, time.June, 29, 10, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 1, 10, time.UTC), OK: true},
+		},
+		"Every 3 days after completion": {
+			Args: args{Rule: "FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION", Due: ptr(at(2023, time.June, 20, 8, time.UTC)), Completed: at(2023, time.June, 29, 10, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 2, 10, time.UTC), OK: true},
+		},
+		"Daily into a leap day": {
+			Args: args{Rule: "FREQ=DAILY", Due: ptr(at(2024, time.February, 28, 8, time.UTC)), Completed: at(2024, time.February, 28, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2024, time.February, 29, 8, time.UTC), OK: true},
+		},
+		"Daily when daylight saving ends": {
+			// 9am in Sydney is 22:00 UTC the day before during daylight saving, and 23:00 UTC after it ends on 2 April
+			Args: args{Rule: "FREQ=DAILY", Due: ptr(at(2023, time.March, 31, 22, time.UTC)), Completed: at(2023, time.March, 31, 21, time.UTC), Loc: sydney},
+			Want: want{Next: at(2023, time.April, 2, 9, sydney), OK: true},
+		},
+		"Daily when daylight saving starts": {
+			Args: args{Rule: "FREQ=DAILY", Due: ptr(at(2023, time.September, 30, 9, sydney)), Completed: at(2023, time.September, 30, 8, sydney), Loc: sydney},
+			Want: want{Next: at(2023, time.October, 1, 9, sydney), OK: true},
+		},
+		"Weekly on the weekday due": {
+			Args: args{Rule: "FREQ=WEEKLY", Due: ptr(at(2023, time.June, 29, 8, time.UTC)), Completed: at(2023, time.June, 29, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 6, 8, time.UTC), OK: true},
+		},
+		"Weekly on several weekdays": {
+			// Thursday 29 June, next occurring on the Saturday
+			Args: args{Rule: "FREQ=WEEKLY;BYDAY=MO,TH,SA", Due: ptr(at(2023, time.June, 29, 8, time.UTC)), Completed: at(2023, time.June, 29, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 1, 8, time.UTC), OK: true},
+		},
+		"Fortnightly, continuing within the week": {
+			// Monday 26 June, next occurring on the Wednesday of the same week
+			Args: args{Rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", Due: ptr(at(2023, time.June, 26, 8, time.UTC)), Completed: at(2023, time.June, 26, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.June, 28, 8, time.UTC), OK: true},
+		},
+		"Fortnightly, skipping a week": {
+			// Wednesday 28 June, next occurring on the Monday two weeks later
+			Args: args{Rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", Due: ptr(at(2023, time.June, 28, 8, time.UTC)), Completed: at(2023, time.June, 28, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 10, 8, time.UTC), OK: true},
+		},
+		"Weekly across daylight saving ending": {
+			Args: args{Rule: "FREQ=WEEKLY;BYDAY=SA", Due: ptr(at(2023, time.April, 1, 9, sydney)), Completed: at(2023, time.April, 1, 8, sydney), Loc: sydney},
+			Want: want{Next: at(2023, time.April, 8, 9, sydney), OK: true},
+		},
+		"Monthly on the day due": {
+			Args: args{Rule: "FREQ=MONTHLY", Due: ptr(at(2023, time.June, 15, 8, time.UTC)), Completed: at(2023, time.June, 15, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.July, 15, 8, time.UTC), OK: true},
+		},
+		"Monthly on a later day of the same month": {
+			Args: args{Rule: "FREQ=MONTHLY;BYMONTHDAY=20", Due: ptr(at(2023, time.June, 15, 8, time.UTC)), Completed: at(2023, time.June, 15, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.June, 20, 8, time.UTC), OK: true},
+		},
+		"Monthly on the 31st skips shorter months": {
+			Args: args{Rule: "FREQ=MONTHLY;BYMONTHDAY=31", Due: ptr(at(2023, time.March, 31, 8, time.UTC)), Completed: at(2023, time.March, 31, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.May, 31, 8, time.UTC), OK: true},
+		},
+		"Monthly on the last day": {
+			Args: args{Rule: "FREQ=MONTHLY;BYMONTHDAY=-1", Due: ptr(at(2023, time.January, 31, 8, time.UTC)), Completed: at(2023, time.January, 31, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.February, 28, 8, time.UTC), OK: true},
+		},
+		"Monthly on the last day of a leap year February": {
+			Args: args{Rule: "FREQ=MONTHLY;BYMONTHDAY=-1", Due: ptr(at(2024, time.January, 31, 8, time.UTC)), Completed: at(2024, time.January, 31, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2024, time.February, 29, 8, time.UTC), OK: true},
+		},
+		"Monthly on the 29th outside a leap year": {
+			Args: args{Rule: "FREQ=MONTHLY;BYMONTHDAY=29", Due: ptr(at(2023, time.January, 29, 8, time.UTC)), Completed: at(2023, time.January, 29, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2023, time.March, 29, 8, time.UTC), OK: true},
+		},
+		"Yearly via 12 months on a leap day": {
+			Args: args{Rule: "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=29", Due: ptr(at(2024, time.February, 29, 8, time.UTC)), Completed: at(2024, time.February, 29, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2028, time.February, 29, 8, time.UTC), OK: true},
+		},
+		"Monthly across the end of the year": {
+			Args: args{Rule: "FREQ=MONTHLY;INTERVAL=3", Due: ptr(at(2023, time.November, 30, 8, time.UTC)), Completed: at(2023, time.November, 30, 7, time.UTC), Loc: time.UTC},
+			Want: want{Next: at(2024, time.May, 30, 8, time.UTC), OK: true},
+		},
+		"Monthly across daylight saving starting": {
+			Args: args{Rule: "FREQ=MONTHLY", Due: ptr(at(2023, time.September, 15, 9, sydney)), Completed: at(2023, time.September, 15, 8, sydney), Loc: sydney},
+			Want: want{Next: at(2023, time.October, 15, 9, sydney), OK: true},
+		},
+		"No further occurrences": {
+			Args: args{Rule: "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", Due: ptr(at(2024, time.February, 1, 8, time.UTC)), Completed: at(2024, time.February, 1, 7, time.UTC), Loc: time.UTC},
+			Want: want{OK: false},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := todo.ParseRecurrence(tt.Args.Rule)
+			require.NoError(t, err, "Parse error")
+
+			next, ok := r.Next(tt.Args.Due, tt.Args.Completed, tt.Args.Loc)
+
+			assert.Equal(t, tt.Want.OK, ok, "Has next occurrence")
+			assert.True(t, tt.Want.Next.Equal(next), "Next occurrence: want %s, got %s", tt.Want.Next, next)
+		})
+	}
+}
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// ArchiveList so that it is hidden, without deleting it.
+func (l *ListsAPI) ArchiveList(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.listAction(l.repo.ArchiveList))(w, r)
+}
+
+// RestoreList which was archived or deleted, so that it is no longer hidden.
+func (l *ListsAPI) RestoreList(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.listAction(l.repo.RestoreList))(w, r)
+}
+
+// ArchiveItem so that it is hidden, along with its subtasks, without deleting them.
+func (l *ListsAPI) ArchiveItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemAction(l.repo.ArchiveItem))(w, r)
+}
+
+// RestoreItem which was archived or deleted, along with the subtasks which were hidden with it.
+func (l *ListsAPI) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemAction(l.repo.RestoreItem))(w, r)
+}
+
+// listAction which changes the state of a TODO list, without requiring any request body.
+func (l *ListsAPI) listAction(action func(ctx context.Context, listID string) (*todo.List, error)) func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+	return func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		list, err := action(r.Context(), listID)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		return &Response{Body: &SavedListBody{List: list}}, nil
+	}
+}
+
+// includeParam from the `include` query param, for lists or items which are otherwise excluded. Values may be repeated,
+// or separated by commas, such as `?include=archived,deleted`.
+func includeParam(r *http.Request) (todo.Include, *ErrorResponse) {
+	var include todo.Include
+
+	for _, v := range r.URL.Query()["include"] {
+		for _, s := range strings.Split(v, ",") {
+			switch strings.TrimSpace(s) {
+			case "archived":
+				include.Archived = true
+			case "deleted":
+				include.Deleted = true
+			default:
+				return todo.Include{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"include" query param must be one of: archived, deleted`}
+			}
+		}
+	}
+
+	return include, nil
+}
@@ -0,0 +1,174 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_ListActions(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Action string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty List ID Path Param": {
+			Args:   args{Action: "archive", ListID: "%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Archive - Not Found": {
+			Args: args{Action: "archive", ListID: "9"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnArchiveList(ctx, "9").Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Archive - Query failure": {
+			Args: args{Action: "archive", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnArchiveList(ctx, "1").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Archive": {
+			Args: args{Action: "archive", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnArchiveList(ctx, "1").Return(&todo.List{ID: "1", Description: "Chores", Archived: &archived}, nil)
+				},
+			},
+			Want: want{Body: `{"list": {"id": "1", "description": "Chores", "archived": "2023-07-03T09:00:00Z"}}`, Code: http.StatusOK},
+		},
+		"Restore - Not Found": {
+			Args: args{Action: "restore", ListID: "9"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRestoreList(ctx, "9").Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Restore": {
+			Args: args{Action: "restore", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRestoreList(ctx, "1").Return(&todo.List{ID: "1", Description: "Chores"}, nil)
+				},
+			},
+			Want: want{Body: `{"list": {"id": "1", "description": "Chores"}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/%s", tt.Args.ListID, tt.Args.Action)
+			req := httptest.NewRequest(http.MethodPost, route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) ArchiveList(ctx context.Context, listID string) (*todo.List, error) {
+	args := l.Called(testContext(ctx), listID)
+	return args.Get(0).(*todo.List), args.Error(1)
+}
+
+// OnArchiveList provides a type-safe mock setup function, used instead of using 'On("ArchiveList, ...)'
+func (l *listRepo) OnArchiveList(ctx context.Context, listID string) *call2[*todo.List, error] {
+	m := l.On("ArchiveList", testContext(ctx), listID)
+	return &call2[*todo.List, error]{m: m}
+}
+
+func (l *listRepo) RestoreList(ctx context.Context, listID string) (*todo.List, error) {
+	args := l.Called(testContext(ctx), listID)
+	return args.Get(0).(*todo.List), args.Error(1)
+}
+
+// OnRestoreList provides a type-safe mock setup function, used instead of using 'On("RestoreList, ...)'
+func (l *listRepo) OnRestoreList(ctx context.Context, listID string) *call2[*todo.List, error] {
+	m := l.On("RestoreList", testContext(ctx), listID)
+	return &call2[*todo.List, error]{m: m}
+}
+
+func (l *listRepo) ArchiveItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnArchiveItem provides a type-safe mock setup function, used instead of using 'On("ArchiveItem, ...)'
+func (l *listRepo) OnArchiveItem(ctx context.Context, listID, itemID string) *call2[*todo.Item, error] {
+	m := l.On("ArchiveItem", testContext(ctx), listID, itemID)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) RestoreItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnRestoreItem provides a type-safe mock setup function, used instead of using 'On("RestoreItem, ...)'
+func (l *listRepo) OnRestoreItem(ctx context.Context, listID, itemID string) *call2[*todo.Item, error] {
+	m := l.On("RestoreItem", testContext(ctx), listID, itemID)
+	return &call2[*todo.Item, error]{m: m}
+}
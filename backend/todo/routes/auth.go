@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// ErrNoCredentials occurs when a request has none of the credentials an Authenticator accepts, so other means of
+// authentication may be tried.
+var ErrNoCredentials = todo.UnauthenticatedError("authentication required")
+
+// Authenticator identifies who is making a request. Requests without valid credentials fail with an
+// UnauthenticatedError, which is ErrNoCredentials when there are no credentials at all.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// Identity of who is making a request, and the scopes of access they have been granted.
+type Identity struct {
+	User   todo.User
+	Scopes todo.Scopes
+}
+
+// Authenticators which are tried in turn, until one finds credentials within the request.
+type Authenticators []Authenticator
+
+func (a Authenticators) Authenticate(r *http.Request) (*Identity, error) {
+	for _, auth := range a {
+		id, err := auth.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		return id, err
+	}
+
+	return nil, ErrNoCredentials
+}
+
+// UserRepository where users are stored.
+type UserRepository interface {
+	ProvisionUser(ctx context.Context, name string) (*todo.User, error)
+}
+
+// HeaderAuth authenticates users by their name in a header, which is set by an authenticating proxy in front of the
+// API. Users are provisioned the first time they are seen. The header is only trusted on requests from the addresses of
+// the proxies, as anyone else could set it to whoever they like.
+type HeaderAuth struct {
+	header  string
+	proxies []netip.Prefix
+	users   UserRepository
+}
+
+// NewHeaderAuth for authenticating users by the name in the header, on requests from the proxies.
+func NewHeaderAuth(header string, proxies []netip.Prefix, users UserRepository) *HeaderAuth {
+	return &HeaderAuth{header: header, proxies: proxies, users: users}
+}
+
+// Authenticate the user named in the header, who may read and change their lists. The header is ignored on requests
+// which are not from a proxy.
+func (a *HeaderAuth) Authenticate(r *http.Request) (*Identity, error) {
+	name := strings.TrimSpace(r.Header.Get(a.header))
+	if name == "" || !a.fromProxy(r) {
+		return nil, ErrNoCredentials
+	}
+
+	user, err := a.users.ProvisionUser(r.Context(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{User: *user, Scopes: todo.Scopes{todo.ScopeListsRead, todo.ScopeListsWrite}}, nil
+}
+
+// fromProxy when the request was made from the address of one of the proxies.
+func (a *HeaderAuth) fromProxy(r *http.Request) bool {
+	addr, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+
+	for _, p := range a.proxies {
+		if p.Contains(addr.Addr().Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// APIKeyRepository where API keys are stored.
+type APIKeyRepository interface {
+	APIKey(ctx context.Context, hash []byte) (*todo.APIKey, error)
+}
+
+// APIKeyAuth authenticates scripts by an API key in the X-API-Key header, as the user who the key belongs to.
+type APIKeyAuth struct {
+	keys APIKeyRepository
+}
+
+// NewAPIKeyAuth for authenticating by the keys in the repository.
+func NewAPIKeyAuth(keys APIKeyRepository) *APIKeyAuth {
+	return &APIKeyAuth{keys: keys}
+}
+
+// Authenticate the user who the API key belongs to, limited to the scopes of the key. Keys which have expired or been
+// revoked are rejected the same as unknown keys.
+func (a *APIKeyAuth) Authenticate(r *http.Request) (*Identity, error) {
+	k := strings.TrimSpace(r.Header.Get("X-API-Key"))
+	if k == "" {
+		return nil, ErrNoCredentials
+	}
+
+	key, err := a.keys.APIKey(r.Context(), todo.HashAPIKey(k))
+	if nf := (todo.NotFoundError)(""); errors.As(err, &nf) {
+		return nil, todo.UnauthenticatedError("invalid api key")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.Active(time.Now()) {
+		return nil, todo.UnauthenticatedError("invalid api key")
+	}
+
+	addLogAttrs(r.Context(), slog.String("api_key.id", key.ID))
+
+	return &Identity{User: key.User, Scopes: key.Scopes}, nil
+}
+
+// authenticate who is making each request, who must have been granted the scope. The user is passed along within the
+// request context, and changes made by the request are recorded as made by them.
+func authenticate(h http.Handler, auth Authenticator, scope todo.Scope) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := auth.Authenticate(r)
+		if ue := (todo.UnauthenticatedError)(""); errors.As(err, &ue) {
+			writeError(w, r, &ErrorResponse{Status: http.StatusUnauthorized, Error: ue.Error()})
+			return
+		}
+
+		if err != nil {
+			writeError(w, r, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err})
+			return
+		}
+
+		addLogAttrs(r.Context(), slog.String("enduser.id", id.User.ID), slog.String("enduser.scope", id.Scopes.String()))
+
+		if !id.Scopes.Allow(scope) {
+			writeError(w, r, &ErrorResponse{Status: http.StatusForbidden, Error: fmt.Sprintf("%q scope is required", scope)})
+			return
+		}
+
+		ctx := todo.WithActor(todo.WithUser(r.Context(), id.User), id.User.Name)
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requiredScope for requests using the method, where only reading is allowed without the write scope.
+func requiredScope(method string) todo.Scope {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return todo.ScopeListsRead
+	default:
+		return todo.ScopeListsWrite
+	}
+}
@@ -0,0 +1,452 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestHeaderAuth(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		RemoteAddr string
+		Route      string
+		User       string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo, u *userRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	alice := &todo.User{ID: "7", Name: "alice"}
+
+	// Test requests are from 192.0.2.1, unless another remote address is given
+	proxies := []netip.Prefix{netip.MustParsePrefix("192.0.2.1/32"), netip.MustParsePrefix("2001:db8::/64")}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"No User": {
+			Args:   args{Route: "/api/v1/lists"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"Blank User": {
+			Args:   args{Route: "/api/v1/lists", User: "  "},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"Provisioning failure": {
+			Args: args{Route: "/api/v1/lists", User: "alice"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnProvisionUser(ctx, "alice").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Authenticated": {
+			Args: args{Route: "/api/v1/lists", User: "alice"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnProvisionUser(ctx, "alice").Return(alice, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
+		},
+		"Spoofed User, not from the Proxy": {
+			Args:   args{RemoteAddr: "203.0.113.9:41234", Route: "/api/v1/lists", User: "alice"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"Authenticated from an IPv6 Proxy": {
+			Args: args{RemoteAddr: "[2001:db8::1]:41234", Route: "/api/v1/lists", User: "alice"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnProvisionUser(ctx, "alice").Return(alice, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
+		},
+		"Ping without User": {
+			Args:   args{Route: "/ping"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `PONG`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var (
+				repo  listRepo
+				users userRepo
+			)
+
+			listsAPI := routes.NewListAPI(&repo)
+			auth := routes.NewHeaderAuth("X-Forwarded-User", proxies, &users)
+
+			tt.Fields.MockExpectations(ctx, &repo, &users)
+			defer mock.AssertExpectationsForObjects(t, &repo, &users)
+
+			h := routes.Handler(listsAPI, auth, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, tt.Args.Route, http.NoBody).WithContext(ctx)
+			if tt.Args.RemoteAddr != "" {
+				req.RemoteAddr = tt.Args.RemoteAddr
+			}
+
+			if tt.Args.User != "" {
+				req.Header.Set("X-Forwarded-User", tt.Args.User)
+			}
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			if tt.Args.Route == "/ping" {
+				assert.Equal(t, tt.Want.Body, string(body), "HTTP Response Body")
+				return
+			}
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Key    string
+		Method string
+		Route  string
+		User   string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo, u *userRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	alice := todo.User{ID: "7", Name: "alice"}
+	expired := time.Now().Add(-time.Hour)
+	expires := time.Now().Add(time.Hour)
+	revoked := time.Now().Add(-time.Minute)
+
+	readKey := &todo.APIKey{ID: "1", User: alice, Scopes: todo.Scopes{todo.ScopeListsRead}, Expires: &expires}
+	adminKey := &todo.APIKey{ID: "2", User: alice, Scopes: todo.Scopes{todo.ScopeAdmin}}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"No Key": {
+			Args:   args{Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"Unknown Key": {
+			Args: args{Key: "todo_unknown", Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_unknown")).Return(nil, todo.NotFoundError("api key does not exist"))
+				},
+			},
+			Want: want{Body: `{"error": "invalid api key"}`, Code: http.StatusUnauthorized},
+		},
+		"Key Query failure": {
+			Args: args{Key: "todo_read", Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_read")).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Expired Key": {
+			Args: args{Key: "todo_expired", Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					key := &todo.APIKey{ID: "3", User: alice, Scopes: todo.Scopes{todo.ScopeAdmin}, Expires: &expired}
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_expired")).Return(key, nil)
+				},
+			},
+			Want: want{Body: `{"error": "invalid api key"}`, Code: http.StatusUnauthorized},
+		},
+		"Revoked Key": {
+			Args: args{Key: "todo_revoked", Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					key := &todo.APIKey{ID: "4", User: alice, Scopes: todo.Scopes{todo.ScopeAdmin}, Revoked: &revoked}
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_revoked")).Return(key, nil)
+				},
+			},
+			Want: want{Body: `{"error": "invalid api key"}`, Code: http.StatusUnauthorized},
+		},
+		"Read Scope - Read": {
+			Args: args{Key: "todo_read", Method: http.MethodGet, Route: "/api/v1/lists"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_read")).Return(readKey, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
+		},
+		"Read Scope - Write": {
+			Args: args{Key: "todo_read", Method: http.MethodDelete, Route: "/api/v1/lists/1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_read")).Return(readKey, nil)
+				},
+			},
+			Want: want{Body: `{"error": "\"lists:write\" scope is required"}`, Code: http.StatusForbidden},
+		},
+		"Admin Scope - Write": {
+			Args: args{Key: "todo_admin", Method: http.MethodDelete, Route: "/api/v1/lists/1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_admin")).Return(adminKey, nil)
+					l.OnDeleteList(ctx, "1").Return(nil)
+				},
+			},
+			Want: want{Code: http.StatusNoContent},
+		},
+		"Key preferred over User Header": {
+			Args: args{Key: "todo_read", Method: http.MethodDelete, Route: "/api/v1/lists/1", User: "alice"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_read")).Return(readKey, nil)
+				},
+			},
+			Want: want{Body: `{"error": "\"lists:write\" scope is required"}`, Code: http.StatusForbidden},
+		},
+		"User Header without Key": {
+			Args: args{Method: http.MethodDelete, Route: "/api/v1/lists/1", User: "alice"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnProvisionUser(ctx, "alice").Return(&alice, nil)
+					l.OnDeleteList(ctx, "1").Return(nil)
+				},
+			},
+			Want: want{Code: http.StatusNoContent},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var (
+				repo  listRepo
+				users userRepo
+			)
+
+			listsAPI := routes.NewListAPI(&repo)
+			auth := routes.Authenticators{routes.NewAPIKeyAuth(&users), routes.NewHeaderAuth("X-Forwarded-User", []netip.Prefix{netip.MustParsePrefix("192.0.2.1/32")}, &users)}
+
+			tt.Fields.MockExpectations(ctx, &repo, &users)
+			defer mock.AssertExpectationsForObjects(t, &repo, &users)
+
+			h := routes.Handler(listsAPI, auth, testLogger, nil)
+
+			req := httptest.NewRequest(tt.Args.Method, tt.Args.Route, http.NoBody).WithContext(ctx)
+			if tt.Args.Key != "" {
+				req.Header.Set("X-API-Key", tt.Args.Key)
+			}
+
+			if tt.Args.User != "" {
+				req.Header.Set("X-Forwarded-User", tt.Args.User)
+			}
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			if tt.Want.Body == "" {
+				assert.Empty(t, body, "HTTP Response Body")
+				return
+			}
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+type userRepo struct {
+	mock.Mock
+}
+
+func (u *userRepo) ProvisionUser(ctx context.Context, name string) (*todo.User, error) {
+	args := u.Called(testContext(ctx), name)
+	return args.Get(0).(*todo.User), args.Error(1)
+}
+
+// OnProvisionUser provides a type-safe mock setup function, used instead of using 'On("ProvisionUser, ...)'
+func (u *userRepo) OnProvisionUser(ctx context.Context, name string) *call2[*todo.User, error] {
+	m := u.On("ProvisionUser", testContext(ctx), name)
+	return &call2[*todo.User, error]{m: m}
+}
+
+func (u *userRepo) APIKey(ctx context.Context, hash []byte) (*todo.APIKey, error) {
+	args := u.Called(testContext(ctx), hash)
+	return args.Get(0).(*todo.APIKey), args.Error(1)
+}
+
+// OnAPIKey provides a type-safe mock setup function, used instead of using 'On("APIKey, ...)'
+func (u *userRepo) OnAPIKey(ctx context.Context, hash []byte) *call2[*todo.APIKey, error] {
+	m := u.On("APIKey", testContext(ctx), hash)
+	return &call2[*todo.APIKey, error]{m: m}
+}
+
+func TestAPIKeyAuth_Default(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Key  string
+		User string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo, u *userRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	alice := todo.User{ID: "7", Name: "alice"}
+	readKey := &todo.APIKey{ID: "1", User: alice, Scopes: todo.Scopes{todo.ScopeListsRead}}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"No Key": {
+			Args:   args{},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"User Header without Key": {
+			Args:   args{User: "alice"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo, *userRepo) {}},
+			Want:   want{Body: `{"error": "authentication required"}`, Code: http.StatusUnauthorized},
+		},
+		"Key": {
+			Args: args{Key: "todo_read"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo, u *userRepo) {
+					u.OnAPIKey(ctx, todo.HashAPIKey("todo_read")).Return(readKey, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var (
+				repo  listRepo
+				users userRepo
+			)
+
+			listsAPI := routes.NewListAPI(&repo)
+			auth := routes.Authenticators{routes.NewAPIKeyAuth(&users)}
+
+			tt.Fields.MockExpectations(ctx, &repo, &users)
+			defer mock.AssertExpectationsForObjects(t, &repo, &users)
+
+			h := routes.Handler(listsAPI, auth, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lists", http.NoBody).WithContext(ctx)
+			if tt.Args.Key != "" {
+				req.Header.Set("X-API-Key", tt.Args.Key)
+			}
+
+			if tt.Args.User != "" {
+				req.Header.Set("X-Forwarded-User", tt.Args.User)
+			}
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
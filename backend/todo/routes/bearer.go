@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// BearerAuth authenticates users by JWTs in the Authorization header, as issued by single sign-on (OIDC). Tokens must
+// be signed by a key within the key set, from the issuer, for the audience, and unexpired.
+type BearerAuth struct {
+	keys   *JWKS
+	parser *jwt.Parser
+	claim  string
+	users  UserRepository
+}
+
+// NewBearerAuth for authenticating tokens from the issuer for the audience. The user is provisioned by the name within
+// the claim, such as "sub", "email" or "preferred_username".
+func NewBearerAuth(keys *JWKS, issuer, audience, claim string, users UserRepository) *BearerAuth {
+	parser := jwt.NewParser(
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithLeeway(30*time.Second),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+	)
+
+	return &BearerAuth{keys: keys, parser: parser, claim: claim, users: users}
+}
+
+// Authenticate the user named in the claim of the token, who may read and change their lists.
+func (a *BearerAuth) Authenticate(r *http.Request) (*Identity, error) {
+	scheme, raw, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(raw) == "" {
+		return nil, ErrNoCredentials
+	}
+
+	ctx := r.Context()
+
+	claims := jwt.MapClaims{}
+
+	_, err := a.parser.ParseWithClaims(strings.TrimSpace(raw), claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	})
+
+	// Failing to load the keys is not the fault of the token, so must not be reported as such
+	if je := (*JWKSError)(nil); errors.As(err, &je) {
+		return nil, err
+	}
+
+	if err != nil {
+		addLogAttrs(ctx, slog.String("auth.error", err.Error()))
+		return nil, todo.UnauthenticatedError("invalid bearer token")
+	}
+
+	// Tokens which never expire would remain usable indefinitely if leaked
+	if exp, _ := claims.GetExpirationTime(); exp == nil {
+		return nil, todo.UnauthenticatedError("bearer token must expire")
+	}
+
+	sub, _ := claims.GetSubject()
+
+	addLogAttrs(ctx, slog.String("auth.subject", sub))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("auth.subject", sub))
+
+	name, _ := claims[a.claim].(string)
+	if strings.TrimSpace(name) == "" {
+		return nil, todo.UnauthenticatedError(fmt.Sprintf("bearer token has no %q claim", a.claim))
+	}
+
+	user, err := a.users.ProvisionUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{User: *user, Scopes: todo.Scopes{todo.ScopeListsRead, todo.ScopeListsWrite}}, nil
+}
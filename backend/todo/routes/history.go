@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// HistoryBody included when retrieving the history of TODO lists and items.
+type HistoryBody struct {
+	Changes []todo.Change `json:"changes"`
+	// Next is the cursor to retrieve the following page of changes, when there is one.
+	Next string `json:"next,omitempty"`
+}
+
+// ListHistory of changes to a TODO list and its items, most recent first.
+func (l *ListsAPI) ListHistory(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		return l.history(w, r, listID, "")
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// ItemHistory of changes to a TODO item, most recent first.
+func (l *ListsAPI) ItemHistory(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		return l.history(w, r, listID, itemID)
+	}
+
+	handleRequest(h)(w, r)
+}
+
+func (l *ListsAPI) history(w http.ResponseWriter, r *http.Request, listID, itemID string) (*Response, *ErrorResponse) {
+	filter, errResp := historyFilterParams(r)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	filter.ItemID = itemID
+
+	page, errResp := pageParams(r)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	changes, next, err := l.repo.History(r.Context(), listID, filter, page)
+	if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+		return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+	}
+
+	if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+		return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+	}
+
+	if err != nil {
+		return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+	}
+
+	if changes == nil {
+		// Ensure we get an empty array in the response, not `null`
+		changes = []todo.Change{}
+	}
+
+	return &Response{Body: &HistoryBody{Changes: changes, Next: nextCursor(w, r, next)}}, nil
+}
+
+// historyFilterParams from the `since` and `until` query params, restricting changes to those made within that time.
+func historyFilterParams(r *http.Request) (todo.HistoryFilter, *ErrorResponse) {
+	var filter todo.HistoryFilter
+	var errResp *ErrorResponse
+
+	if filter.Since, errResp = timeParam(r, "since"); errResp != nil {
+		return todo.HistoryFilter{}, errResp
+	}
+
+	if filter.Until, errResp = timeParam(r, "until"); errResp != nil {
+		return todo.HistoryFilter{}, errResp
+	}
+
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return todo.HistoryFilter{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"since" query param must be before "until"`}
+	}
+
+	return filter, nil
+}
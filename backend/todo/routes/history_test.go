@@ -0,0 +1,189 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_History(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Route string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	changed := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty List ID Path Param": {
+			Args:   args{Route: "/api/v1/lists/%20/history"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Empty Item ID Path Param": {
+			Args:   args{Route: "/api/v1/lists/1/items/%20/history"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"item_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Since": {
+			Args:   args{Route: "/api/v1/lists/1/history?since=yesterday"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"since\" query param must be an RFC 3339 timestamp"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Until": {
+			Args:   args{Route: "/api/v1/lists/1/history?until=today"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"until\" query param must be an RFC 3339 timestamp"}`, Code: http.StatusBadRequest},
+		},
+		"Since not Before Until": {
+			Args:   args{Route: "/api/v1/lists/1/history?since=2023-07-01T00:00:00Z&until=2023-07-01T00:00:00Z"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"since\" query param must be before \"until\""}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Limit": {
+			Args:   args{Route: "/api/v1/lists/1/history?limit=0"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"limit\" query param must be an integer between 1 and 1000"}`, Code: http.StatusBadRequest},
+		},
+		"Query failure": {
+			Args: args{Route: "/api/v1/lists/1/history"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnHistory(ctx, "1", todo.HistoryFilter{}, todo.Page{Limit: 100}).Return(nil, "", errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"No Changes": {
+			Args: args{Route: "/api/v1/lists/1/history"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnHistory(ctx, "1", todo.HistoryFilter{}, todo.Page{Limit: 100}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"changes": []}`, Code: http.StatusOK},
+		},
+		"List History": {
+			Args: args{Route: "/api/v1/lists/2/history"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					changes := []todo.Change{
+						{
+							ID: "7", ListID: "2", ItemID: ptr("4"), Actor: ptr("alice"), Time: changed, Operation: todo.ChangeUpdate,
+							Fields: map[string]todo.FieldChange{"priority": {Old: todo.PriorityNormal, New: todo.PriorityHigh}},
+						},
+						{
+							ID: "5", ListID: "2", Time: changed, Operation: todo.ChangeCreate,
+							Fields: map[string]todo.FieldChange{"description": {New: "Chores"}},
+						},
+					}
+					l.OnHistory(ctx, "2", todo.HistoryFilter{}, todo.Page{Limit: 100}).Return(changes, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"changes": [
+						{"id": "7", "listId": "2", "itemId": "4", "actor": "alice", "time": "2023-07-03T09:00:00Z", "operation": "update", "fields": {"priority": {"old": "normal", "new": "high"}}},
+						{"id": "5", "listId": "2", "itemId": null, "actor": null, "time": "2023-07-03T09:00:00Z", "operation": "create", "fields": {"description": {"old": null, "new": "Chores"}}}
+					]
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Item History - Filtered, with more pages": {
+			Args: args{Route: "/api/v1/lists/2/items/4/history?limit=1&since=2023-07-01T00:00:00Z&until=2023-08-01T00:00:00Z"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					filter := todo.HistoryFilter{
+						ItemID: "4",
+						Since:  ptr(time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)),
+						Until:  ptr(time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)),
+					}
+					changes := []todo.Change{
+						{
+							ID: "8", ListID: "2", ItemID: ptr("4"), Time: changed, Operation: todo.ChangeTag,
+							Fields: map[string]todo.FieldChange{"tags": {New: "@home"}},
+						},
+					}
+					l.OnHistory(ctx, "2", filter, todo.Page{Limit: 1}).Return(changes, "8", nil)
+				},
+			},
+			Want: want{
+				Body:    `{"changes": [{"id": "8", "listId": "2", "itemId": "4", "actor": null, "time": "2023-07-03T09:00:00Z", "operation": "tag", "fields": {"tags": {"old": null, "new": "@home"}}}], "next": "OA"}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/lists/2/items/4/history?after=OA&limit=1&since=2023-07-01T00%3A00%3A00Z&until=2023-08-01T00%3A00%3A00Z>; rel="next"`}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, tt.Args.Route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+			assert.Equal(t, tt.Want.Headers.Values("Link"), res.Header.Values("Link"), "HTTP Link Header")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) History(ctx context.Context, listID string, filter todo.HistoryFilter, page todo.Page) ([]todo.Change, string, error) {
+	args := l.Called(testContext(ctx), listID, filter, page)
+	return args.Get(0).([]todo.Change), args.String(1), args.Error(2)
+}
+
+// OnHistory provides a type-safe mock setup function, used instead of using 'On("History, ...)'
+func (l *listRepo) OnHistory(ctx context.Context, listID string, filter todo.HistoryFilter, page todo.Page) *call3[[]todo.Change, string, error] {
+	m := l.On("History", testContext(ctx), listID, filter, page)
+	return &call3[[]todo.Change, string, error]{m: m}
+}
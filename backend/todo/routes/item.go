@@ -0,0 +1,354 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// ItemBody included when retrieving or modifying a single TODO item.
+type ItemBody struct {
+	Item *todo.Item `json:"item"`
+}
+
+// ItemRequest body received when creating or updating a TODO item.
+type ItemRequest struct {
+	Description *string                   `json:"description"`
+	Due         Optional[time.Time]       `json:"due"`
+	Priority    *todo.Priority            `json:"priority"`
+	Recurrence  Optional[todo.Recurrence] `json:"recurrence"`
+	// ParentID of the item to create this as a subtask of. Only accepted when creating an item.
+	ParentID     *string `json:"parentId"`
+	AutoComplete *bool   `json:"autoComplete"`
+}
+
+// MoveRequest body received when moving a TODO item, which must have exactly one of Before or After.
+type MoveRequest struct {
+	// Before is the ID of the item to place the moved item immediately before.
+	Before *string `json:"before"`
+	// After is the ID of the item to place the moved item immediately after.
+	After *string `json:"after"`
+}
+
+// Optional value in a request body, which distinguishes between a field being absent, and explicitly set to null.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	o.Set = true
+
+	if bytes.Equal(b, []byte("null")) {
+		o.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	o.Value = &v
+
+	return nil
+}
+
+// CreateItem on a TODO list.
+func (l *ListsAPI) CreateItem(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req ItemRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		if req.Description == nil || strings.TrimSpace(*req.Description) == "" {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+		}
+
+		if req.ParentID != nil && strings.TrimSpace(*req.ParentID) == "" {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"parentId" must not be blank`}
+		}
+
+		item := todo.Item{Description: strings.TrimSpace(*req.Description), Due: req.Due.Value, Recurrence: req.Recurrence.Value}
+		if req.Priority != nil {
+			item.Priority = *req.Priority
+		}
+
+		if req.ParentID != nil {
+			parentID := strings.TrimSpace(*req.ParentID)
+			item.ParentID = &parentID
+		}
+
+		if req.AutoComplete != nil {
+			item.AutoComplete = *req.AutoComplete
+		}
+
+		created, err := l.repo.CreateItem(r.Context(), listID, item)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		w.Header().Set("Location", "/api/v1/lists/"+listID+"/items/"+created.ID)
+
+		return &Response{Status: http.StatusCreated, Body: &ItemBody{Item: created}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// UpdateItem modifies the description, due date, priority, recurrence and/or auto-completion of a TODO item. Items
+// cannot be moved to a different parent.
+func (l *ListsAPI) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req ItemRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		update := todo.ItemUpdate{
+			Due:           req.Due.Value,
+			SetDue:        req.Due.Set,
+			Priority:      req.Priority,
+			Recurrence:    req.Recurrence.Value,
+			SetRecurrence: req.Recurrence.Set,
+			AutoComplete:  req.AutoComplete,
+		}
+
+		switch {
+		case req.ParentID != nil:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"parentId" cannot be changed`}
+		case req.Description == nil && !req.Due.Set && req.Priority == nil && !req.Recurrence.Set && req.AutoComplete == nil:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: "at least one field must be provided"}
+		case req.Description != nil && strings.TrimSpace(*req.Description) == "":
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+		case req.Description != nil:
+			desc := strings.TrimSpace(*req.Description)
+			update.Description = &desc
+		}
+
+		item, err := l.repo.UpdateItem(r.Context(), listID, itemID, update)
+
+		return itemResponse(item, err)
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// CompleteItem marks a TODO item as done, as of now.
+func (l *ListsAPI) CompleteItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemAction(l.repo.CompleteItem))(w, r)
+}
+
+// ReopenItem marks a completed TODO item as not yet done.
+func (l *ListsAPI) ReopenItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemAction(l.repo.ReopenItem))(w, r)
+}
+
+// MoveItem to a new position within its TODO list, immediately before or after another of its items.
+func (l *ListsAPI) MoveItem(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req MoveRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		var move todo.ItemMove
+
+		switch {
+		case (req.Before == nil) == (req.After == nil):
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `exactly one of "before" or "after" must be provided`}
+		case req.Before != nil && strings.TrimSpace(*req.Before) == "":
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"before" must not be blank`}
+		case req.After != nil && strings.TrimSpace(*req.After) == "":
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"after" must not be blank`}
+		case req.Before != nil:
+			move.Before = strings.TrimSpace(*req.Before)
+		default:
+			move.After = strings.TrimSpace(*req.After)
+		}
+
+		item, err := l.repo.MoveItem(r.Context(), listID, itemID, move)
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+		}
+
+		return itemResponse(item, err)
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// DeleteItem from a TODO list.
+func (l *ListsAPI) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		err := l.repo.DeleteItem(r.Context(), listID, itemID)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		return &Response{Status: http.StatusNoContent}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// itemAction which changes the state of a TODO item, without requiring any request body.
+func (l *ListsAPI) itemAction(action func(ctx context.Context, listID, itemID string) (*todo.Item, error)) func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+	return func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		item, err := action(r.Context(), listID, itemID)
+
+		return itemResponse(item, err)
+	}
+}
+
+// itemFilterParams from the `status`, `due_before`, `due_after`, `tag`, `tag_match`, `sort` and `tree` query params of
+// the request.
+func itemFilterParams(r *http.Request) (todo.ItemFilter, *ErrorResponse) {
+	q := r.URL.Query()
+
+	var filter todo.ItemFilter
+
+	switch s := todo.ItemStatus(q.Get("status")); s {
+	case "", todo.ItemStatusOpen, todo.ItemStatusCompleted, todo.ItemStatusOverdue:
+		filter.Status = s
+	default:
+		return todo.ItemFilter{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"status" query param must be one of: open, completed, overdue`}
+	}
+
+	var errResp *ErrorResponse
+
+	if filter.DueBefore, errResp = timeParam(r, "due_before"); errResp != nil {
+		return todo.ItemFilter{}, errResp
+	}
+
+	if filter.DueAfter, errResp = timeParam(r, "due_after"); errResp != nil {
+		return todo.ItemFilter{}, errResp
+	}
+
+	if filter.DueBefore != nil && filter.DueAfter != nil && !filter.DueAfter.Before(*filter.DueBefore) {
+		return todo.ItemFilter{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"due_after" query param must be before "due_before"`}
+	}
+
+	if filter.Tags, filter.TagMatch, errResp = tagParams(r); errResp != nil {
+		return todo.ItemFilter{}, errResp
+	}
+
+	switch s := todo.ItemSort(q.Get("sort")); s {
+	case "", todo.ItemSortPosition, todo.ItemSortID, todo.ItemSortDue, todo.ItemSortDueDesc, todo.ItemSortDescription:
+		filter.Sort = s
+	default:
+		return todo.ItemFilter{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"sort" query param must be one of: position, id, due, -due, description`}
+	}
+
+	if v := q.Get("tree"); v != "" {
+		tree, err := strconv.ParseBool(v)
+		if err != nil {
+			return todo.ItemFilter{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"tree" query param must be a boolean`}
+		}
+
+		filter.Tree = tree
+	}
+
+	if filter.Include, errResp = includeParam(r); errResp != nil {
+		return todo.ItemFilter{}, errResp
+	}
+
+	return filter, nil
+}
+
+// timeParam from the query of the request, which is nil when not provided.
+func timeParam(r *http.Request, name string) (*time.Time, *ErrorResponse) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf("%q query param must be an RFC 3339 timestamp", name)}
+	}
+
+	return &t, nil
+}
+
+func itemPathParams(r *http.Request) (listID, itemID string, errResp *ErrorResponse) {
+	if listID, errResp = pathParam(r, "list_id"); errResp != nil {
+		return "", "", errResp
+	}
+
+	if itemID, errResp = pathParam(r, "item_id"); errResp != nil {
+		return "", "", errResp
+	}
+
+	return listID, itemID, nil
+}
+
+func itemResponse(item *todo.Item, err error) (*Response, *ErrorResponse) {
+	if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+		return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+	}
+
+	if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+		return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+	}
+
+	if err != nil {
+		return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+	}
+
+	return &Response{Body: &ItemBody{Item: item}}, nil
+}
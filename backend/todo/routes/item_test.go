@@ -0,0 +1,774 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_CreateItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	goSyd := time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty List ID Path Param": {
+			Args:   args{Body: `{"description": "Washing"}`, ListID: "%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Description": {
+			Args:   args{Body: `{"description": " ", "due": null}`, ListID: "1"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"List Not Found": {
+			Args: args{Body: `{"description": "Washing"}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "2", todo.Item{Description: "Washing"}).Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"description": "Washing"}`, ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "1", todo.Item{Description: "Washing"}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Created": {
+			Args: args{Body: `{"description": "Attend & Present", "due": "2023-06-29T08:00:00Z"}`, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "3", todo.Item{Description: "Attend & Present", Due: &goSyd}).
+						Return(&todo.Item{ID: "7", Description: "Attend & Present", Due: &goSyd}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"item": {"id": "7", "description": "Attend & Present", "due": "2023-06-29T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/3/items/7"}},
+			},
+		},
+		"Invalid Priority": {
+			Args:   args{Body: `{"description": "Renew Passport", "parentId": null, "priority": "asap"}`, ListID: "3"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: priority must be one of: low, normal, high, urgent"}`, Code: http.StatusBadRequest},
+		},
+		"Created with Priority": {
+			Args: args{Body: `{"description": "Renew Passport", "parentId": null, "priority": "urgent"}`, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "3", todo.Item{Description: "Renew Passport", Priority: todo.PriorityUrgent}).
+						Return(&todo.Item{ID: "8", Description: "Renew Passport", Priority: todo.PriorityUrgent}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"item": {"id": "8", "description": "Renew Passport", "due": null, "completed": null, "parentId": null, "priority": "urgent", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/3/items/8"}},
+			},
+		},
+		"Blank Parent": {
+			Args:   args{Body: `{"description": "Slides", "parentId": " "}`, ListID: "3"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"parentId\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Subtask nested too deeply": {
+			Args: args{Body: `{"description": "Slides", "parentId": "7"}`, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "3", todo.Item{ParentID: ptr("7"), Description: "Slides"}).
+						Return(nil, todo.InvalidArgumentError("subtasks must not be nested more than 3 deep"))
+				},
+			},
+			Want: want{Body: `{"error": "subtasks must not be nested more than 3 deep"}`, Code: http.StatusBadRequest},
+		},
+		"Created Subtask": {
+			Args: args{Body: `{"description": "Slides", "parentId": "7", "autoComplete": true}`, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateItem(ctx, "3", todo.Item{ParentID: ptr("7"), Description: "Slides", AutoComplete: true}).
+						Return(&todo.Item{ID: "10", ParentID: ptr("7"), Description: "Slides", AutoComplete: true}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"item": {"id": "10", "description": "Slides", "due": null, "completed": null, "parentId": "7", "priority": "normal", "recurrence": null, "tags": [], "autoComplete": true, "progress": null}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/3/items/10"}},
+			},
+		},
+		"Invalid Recurrence": {
+			Args:   args{Body: `{"description": "Water Plants", "recurrence": "FREQ=HOURLY"}`, ListID: "3"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: recurrence FREQ must be one of: DAILY, WEEKLY, MONTHLY"}`, Code: http.StatusBadRequest},
+		},
+		"Created with Recurrence": {
+			Args: args{Body: `{"description": "Water Plants", "recurrence": "FREQ=WEEKLY;BYDAY=MO"}`, ListID: "3"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					weekly := &todo.Recurrence{Frequency: todo.FrequencyWeekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}}
+
+					l.OnCreateItem(ctx, "3", todo.Item{Description: "Water Plants", Recurrence: weekly}).
+						Return(&todo.Item{ID: "9", Description: "Water Plants", Recurrence: weekly}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"item": {"id": "9", "description": "Water Plants", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": "FREQ=WEEKLY;BYDAY=MO", "tags": [], "autoComplete": false, "progress": null}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/3/items/9"}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items", tt.Args.ListID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_UpdateItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	goSyd := time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty Item ID Path Param": {
+			Args:   args{Body: `{"description": "Washing"}`, ItemID: "%20", ListID: "1"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"item_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"No Fields": {
+			Args:   args{Body: `{}`, ItemID: "1", ListID: "1"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "at least one field must be provided"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Description": {
+			Args:   args{Body: `{"description": ""}`, ItemID: "1", ListID: "1"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Not Found": {
+			Args: args{Body: `{"description": "Washing"}`, ItemID: "9", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "1", "9", todo.ItemUpdate{Description: ptr("Washing")}).Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"description": "Washing"}`, ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "1", "1", todo.ItemUpdate{Description: ptr("Washing")}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Clear Due": {
+			Args: args{Body: `{"due": null}`, ItemID: "2", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "1", "2", todo.ItemUpdate{SetDue: true}).Return(&todo.Item{ID: "2", Description: "Washing"}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "2", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Set Due and Description": {
+			Args: args{Body: `{"description": "Present", "due": "2023-06-29T08:00:00Z"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "2", "3", todo.ItemUpdate{Description: ptr("Present"), Due: &goSyd, SetDue: true}).
+						Return(&todo.Item{ID: "3", Description: "Present", Due: &goSyd}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": "2023-06-29T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Set Priority": {
+			Args: args{Body: `{"parentId": null, "priority": "low"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "2", "3", todo.ItemUpdate{Priority: ptr(todo.PriorityLow)}).
+						Return(&todo.Item{ID: "3", Description: "Present", Priority: todo.PriorityLow}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "low", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Change Parent": {
+			Args:   args{Body: `{"parentId": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"parentId\" cannot be changed"}`, Code: http.StatusBadRequest},
+		},
+		"Set Auto-Complete": {
+			Args: args{Body: `{"autoComplete": true}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "2", "3", todo.ItemUpdate{AutoComplete: ptr(true)}).
+						Return(&todo.Item{ID: "3", Description: "Present", AutoComplete: true, Progress: &todo.Progress{Done: 2, Total: 3}}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": true, "progress": "2/3 done"}}`, Code: http.StatusOK},
+		},
+		"Clear Recurrence": {
+			Args: args{Body: `{"recurrence": null}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateItem(ctx, "2", "3", todo.ItemUpdate{SetRecurrence: true}).
+						Return(&todo.Item{ID: "3", Description: "Present"}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s", tt.Args.ListID, tt.Args.ItemID)
+			req := httptest.NewRequest(http.MethodPatch, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_ItemActions(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Action string
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	done := time.Date(2023, time.June, 29, 10, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Complete - Not Found": {
+			Args: args{Action: "complete", ItemID: "9", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCompleteItem(ctx, "1", "9").Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Complete - Query failure": {
+			Args: args{Action: "complete", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCompleteItem(ctx, "1", "1").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Complete": {
+			Args: args{Action: "complete", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCompleteItem(ctx, "1", "1").Return(&todo.Item{ID: "1", Description: "Washing", Completed: &done}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "1", "description": "Washing", "due": null, "completed": "2023-06-29T10:00:00Z", "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Reopen - Not Found": {
+			Args: args{Action: "reopen", ItemID: "9", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnReopenItem(ctx, "1", "9").Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Reopen": {
+			Args: args{Action: "reopen", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnReopenItem(ctx, "1", "1").Return(&todo.Item{ID: "1", Description: "Washing"}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "1", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Archive - Not Found": {
+			Args: args{Action: "archive", ItemID: "9", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnArchiveItem(ctx, "1", "9").Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Archive": {
+			Args: args{Action: "archive", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnArchiveItem(ctx, "1", "1").Return(&todo.Item{ID: "1", Description: "Washing", Archived: &done}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "1", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null, "archived": "2023-06-29T10:00:00Z"}}`, Code: http.StatusOK},
+		},
+		"Restore - Query failure": {
+			Args: args{Action: "restore", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRestoreItem(ctx, "1", "1").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Restore": {
+			Args: args{Action: "restore", ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRestoreItem(ctx, "1", "1").Return(&todo.Item{ID: "1", Description: "Washing"}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "1", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s/%s", tt.Args.ListID, tt.Args.ItemID, tt.Args.Action)
+			req := httptest.NewRequest(http.MethodPost, route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_MoveItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Neither Before nor After": {
+			Args:   args{Body: `{}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "exactly one of \"before\" or \"after\" must be provided"}`, Code: http.StatusBadRequest},
+		},
+		"Both Before and After": {
+			Args:   args{Body: `{"before": "4", "after": "5"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "exactly one of \"before\" or \"after\" must be provided"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Before": {
+			Args:   args{Body: `{"before": " "}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"before\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Relative to itself": {
+			Args: args{Body: `{"after": "3"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMoveItem(ctx, "2", "3", todo.ItemMove{After: "3"}).Return(nil, todo.InvalidArgumentError("an item cannot be moved relative to itself"))
+				},
+			},
+			Want: want{Body: `{"error": "an item cannot be moved relative to itself"}`, Code: http.StatusBadRequest},
+		},
+		"Not Found": {
+			Args: args{Body: `{"before": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMoveItem(ctx, "2", "3", todo.ItemMove{Before: "4"}).Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"before": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMoveItem(ctx, "2", "3", todo.ItemMove{Before: "4"}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Moved Before": {
+			Args: args{Body: `{"before": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMoveItem(ctx, "2", "3", todo.ItemMove{Before: "4"}).Return(&todo.Item{ID: "3", Description: "Present", Position: 1536}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Moved After": {
+			Args: args{Body: `{"after": " 5 "}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMoveItem(ctx, "2", "3", todo.ItemMove{After: "5"}).Return(&todo.Item{ID: "3", Description: "Present", Position: 4096}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s/move", tt.Args.ListID, tt.Args.ItemID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_DeleteItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Not Found": {
+			Args: args{ItemID: "9", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteItem(ctx, "1", "9").Return(todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteItem(ctx, "1", "1").Return(errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Deleted": {
+			Args: args{ItemID: "1", ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteItem(ctx, "1", "1").Return(nil)
+				},
+			},
+			Want: want{Code: http.StatusNoContent},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s", tt.Args.ListID, tt.Args.ItemID)
+			req := httptest.NewRequest(http.MethodDelete, route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			if tt.Want.Body == "" {
+				assert.Empty(t, body, "HTTP Response Body")
+				return
+			}
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) CreateItem(ctx context.Context, listID string, item todo.Item) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, item)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnCreateItem provides a type-safe mock setup function, used instead of using 'On("CreateItem, ...)'
+func (l *listRepo) OnCreateItem(ctx context.Context, listID string, item todo.Item) *call2[*todo.Item, error] {
+	m := l.On("CreateItem", testContext(ctx), listID, item)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) UpdateItem(ctx context.Context, listID, itemID string, update todo.ItemUpdate) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID, update)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnUpdateItem provides a type-safe mock setup function, used instead of using 'On("UpdateItem, ...)'
+func (l *listRepo) OnUpdateItem(ctx context.Context, listID, itemID string, update todo.ItemUpdate) *call2[*todo.Item, error] {
+	m := l.On("UpdateItem", testContext(ctx), listID, itemID, update)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) CompleteItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnCompleteItem provides a type-safe mock setup function, used instead of using 'On("CompleteItem, ...)'
+func (l *listRepo) OnCompleteItem(ctx context.Context, listID, itemID string) *call2[*todo.Item, error] {
+	m := l.On("CompleteItem", testContext(ctx), listID, itemID)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) MoveItem(ctx context.Context, listID, itemID string, move todo.ItemMove) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID, move)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnMoveItem provides a type-safe mock setup function, used instead of using 'On("MoveItem, ...)'
+func (l *listRepo) OnMoveItem(ctx context.Context, listID, itemID string, move todo.ItemMove) *call2[*todo.Item, error] {
+	m := l.On("MoveItem", testContext(ctx), listID, itemID, move)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) ReopenItem(ctx context.Context, listID, itemID string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnReopenItem provides a type-safe mock setup function, used instead of using 'On("ReopenItem, ...)'
+func (l *listRepo) OnReopenItem(ctx context.Context, listID, itemID string) *call2[*todo.Item, error] {
+	m := l.On("ReopenItem", testContext(ctx), listID, itemID)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) DeleteItem(ctx context.Context, listID, itemID string) error {
+	args := l.Called(testContext(ctx), listID, itemID)
+	return args.Error(0)
+}
+
+// OnDeleteItem provides a type-safe mock setup function, used instead of using 'On("DeleteItem, ...)'
+func (l *listRepo) OnDeleteItem(ctx context.Context, listID, itemID string) *call1[error] {
+	m := l.On("DeleteItem", testContext(ctx), listID, itemID)
+	return &call1[error]{m: m}
+}
@@ -0,0 +1,320 @@
+package routes
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minJWKSReload between attempts to reload the keys, such as for tokens signed by unknown keys, so that tokens with
+// made up key IDs cannot flood the source of the keys. Reloading is never delayed beyond the refresh interval though.
+const minJWKSReload = 30 * time.Second
+
+// jwksTimeout for loading the keys, which is independent of the request that needed them, so that a request which is
+// cancelled does not fail the load for the other requests waiting on it.
+const jwksTimeout = 10 * time.Second
+
+// JWKS is a JSON Web Key Set, with the public keys which tokens are signed by. The keys are cached, and reloaded from
+// their source once older than the refresh interval, or when a token is signed by an unknown key, so that rotated keys
+// are picked up.
+type JWKS struct {
+	source  string
+	refresh time.Duration
+	client  *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any
+	err       error
+	loaded    time.Time
+	attempted time.Time
+	loading   chan struct{}
+}
+
+// NewJWKS loaded from the source, which is either an http(s) URL or a file path.
+func NewJWKS(source string, refresh time.Duration, client *http.Client) *JWKS {
+	return &JWKS{source: source, refresh: refresh, client: client}
+}
+
+// Key with the ID, which is one of the crypto public key types. When the keys cannot be reloaded, the previously loaded
+// keys continue to be used.
+//
+// The keys are loaded without holding the lock, so that requests with known keys are not held up by a slow source.
+// Only one load happens at a time, which requests for unknown keys wait on until their context is done.
+func (s *JWKS) Key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+
+	now := time.Now()
+
+	retry := minJWKSReload
+	if s.refresh < retry {
+		retry = s.refresh
+	}
+
+	key, ok := s.keys[kid]
+	stale := now.Sub(s.loaded) >= s.refresh
+
+	// Until the keys have been loaded, they are also only attempted once within the retry interval, so that the source is
+	// not flooded while it is unavailable
+	if s.loading == nil && (stale || !ok) && now.Sub(s.attempted) >= retry {
+		s.attempted = now
+		s.loading = make(chan struct{})
+
+		go s.reload(s.loading)
+	}
+
+	// Stale keys continue to be used while they are reloaded, while unknown keys wait on the reload
+	if loading := s.loading; loading != nil && !ok {
+		s.mu.Unlock()
+
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		s.mu.Lock()
+
+		key, ok = s.keys[kid]
+	}
+
+	// Requests fail with why the keys could not be loaded, until they have been
+	if s.keys == nil {
+		err := s.err
+		s.mu.Unlock()
+
+		return nil, err
+	}
+
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not within the key set", kid)
+	}
+
+	return key, nil
+}
+
+// reload the keys, keeping those previously loaded when they cannot be, then close done.
+func (s *JWKS) reload(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksTimeout)
+	defer cancel()
+
+	keys, err := s.load(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.keys = keys
+		s.loaded = time.Now()
+	}
+
+	s.err = err
+	s.loading = nil
+
+	close(done)
+}
+
+func (s *JWKS) load(ctx context.Context) (map[string]any, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	if strings.HasPrefix(s.source, "http://") || strings.HasPrefix(s.source, "https://") {
+		b, err = s.fetch(ctx)
+	} else {
+		b, err = os.ReadFile(s.source)
+	}
+
+	if err != nil {
+		return nil, &JWKSError{Err: fmt.Errorf("failed to load key set from %q: %w", s.source, err)}
+	}
+
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return nil, &JWKSError{Err: fmt.Errorf("failed to parse key set from %q: %w", s.source, err)}
+	}
+
+	return keys, nil
+}
+
+func (s *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, 1<<20))
+}
+
+// JWKSError occurs when the key set cannot be loaded, which is a failure of the server rather than of the token.
+type JWKSError struct {
+	Err error
+}
+
+func (e *JWKSError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *JWKSError) Unwrap() error {
+	return e.Err
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS into public keys by their IDs. Keys which are not for signatures, or are of unsupported types, are skipped
+// so that the other keys of the set can still be used.
+func parseJWKS(b []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if errors.Is(err, errUnsupportedKey) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+var errUnsupportedKey = errors.New("unsupported key type")
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64Int(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+
+		e, err := base64Int(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+
+		// Exponents are small in practice, with larger ones refused by crypto/rsa, while they would overflow an int
+		if !e.IsInt64() || e.Int64() > math.MaxInt32 {
+			return nil, errors.New("invalid exponent: too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var crv elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			crv = elliptic.P256()
+		case "P-384":
+			crv = elliptic.P384()
+		case "P-521":
+			crv = elliptic.P521()
+		default:
+			return nil, errUnsupportedKey
+		}
+
+		x, err := base64Int(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+
+		y, err := base64Int(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+
+		if !crv.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: crv, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errUnsupportedKey
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key must be %d bytes", ed25519.PublicKeySize)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errUnsupportedKey
+	}
+}
+
+func base64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("must not be empty")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
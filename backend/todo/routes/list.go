@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"golang.org/x/exp/slog"
@@ -15,7 +18,9 @@ import (
 
 // Response to be encoded and transmitted to the client.
 type Response struct {
-	Body interface{}
+	// Status code of the response. Defaults to 200 OK when not set.
+	Status int
+	Body   interface{}
 }
 
 // ErrorResponse to be encoded and transmitted to the client on failure.
@@ -28,24 +33,70 @@ type ErrorResponse struct {
 // ItemsBody included when retrieving TODO items.
 type ItemsBody struct {
 	Items []todo.Item `json:"items"`
+	// Next is the cursor to retrieve the following page of items, when there is one.
+	Next string `json:"next,omitempty"`
 }
 
 // ListBody included when retrieving a TODO list.
 type ListBody struct {
-	List     *todo.List  `json:"list"`
-	DueItems []todo.Item `json:"dueItems"`
+	List     *todo.List    `json:"list"`
+	DueItems []todo.Item   `json:"dueItems"`
+	Horizon  todo.Duration `json:"horizon"`
+	// UrgentDue is the number of the due items which are of urgent priority.
+	UrgentDue int `json:"urgentDue"`
+}
+
+// SavedListBody included when a TODO list has been created or updated.
+type SavedListBody struct {
+	List *todo.List `json:"list"`
 }
 
-// ListsBody included when retrieving TODO lists.
+// ListsBody included when retrieving TODO lists, where those owned by the user are separate from those which have been
+// shared with them.
 type ListsBody struct {
-	Lists []todo.DueList `json:"lists"`
+	Lists  []todo.DueList `json:"lists"`
+	Shared []todo.DueList `json:"shared"`
+	// Next is the cursor to retrieve the following page of lists, when there is one.
+	Next string `json:"next,omitempty"`
+}
+
+// ListRequest body received when creating or updating a TODO list.
+type ListRequest struct {
+	Description *string                 `json:"description"`
+	DueHorizon  Optional[todo.Duration] `json:"dueHorizon"`
 }
 
 // ListRepository where TODO lists and items are stored.
 type ListRepository interface {
-	Items(ctx context.Context, listID string) ([]todo.Item, error)
-	List(ctx context.Context, listID string) (*todo.DueList, error)
-	Lists(ctx context.Context) ([]todo.DueList, error)
+	Items(ctx context.Context, listID string, filter todo.ItemFilter, page todo.Page) ([]todo.Item, string, error)
+	List(ctx context.Context, listID string, horizon *time.Duration, include todo.Include) (*todo.DueList, error)
+	Lists(ctx context.Context, page todo.Page, horizon *time.Duration, include todo.Include) ([]todo.DueList, string, error)
+	CreateList(ctx context.Context, list todo.List) (*todo.List, error)
+	UpdateList(ctx context.Context, listID string, update todo.ListUpdate) (*todo.List, error)
+	DeleteList(ctx context.Context, listID string) error
+	ArchiveList(ctx context.Context, listID string) (*todo.List, error)
+	RestoreList(ctx context.Context, listID string) (*todo.List, error)
+	CreateItem(ctx context.Context, listID string, item todo.Item) (*todo.Item, error)
+	UpdateItem(ctx context.Context, listID, itemID string, update todo.ItemUpdate) (*todo.Item, error)
+	CompleteItem(ctx context.Context, listID, itemID string) (*todo.Item, error)
+	ReopenItem(ctx context.Context, listID, itemID string) (*todo.Item, error)
+	MoveItem(ctx context.Context, listID, itemID string, move todo.ItemMove) (*todo.Item, error)
+	TransferItems(ctx context.Context, listID string, transfer todo.ItemTransfer) ([]todo.Item, error)
+	DeleteItem(ctx context.Context, listID, itemID string) error
+	ArchiveItem(ctx context.Context, listID, itemID string) (*todo.Item, error)
+	RestoreItem(ctx context.Context, listID, itemID string) (*todo.Item, error)
+	Tags(ctx context.Context, page todo.Page) ([]todo.Tag, string, error)
+	TagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error)
+	UntagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error)
+	Search(ctx context.Context, query string, page todo.Page) ([]todo.SearchResult, string, error)
+	Templates(ctx context.Context, page todo.Page) ([]todo.Template, string, error)
+	Template(ctx context.Context, templateID string) (*todo.Template, []todo.TemplateItem, error)
+	SaveTemplate(ctx context.Context, listID string, save todo.TemplateSave) (*todo.Template, []todo.TemplateItem, error)
+	InstantiateTemplate(ctx context.Context, templateID string, instance todo.TemplateInstance) (*todo.List, []todo.Item, error)
+	History(ctx context.Context, listID string, filter todo.HistoryFilter, page todo.Page) ([]todo.Change, string, error)
+	Members(ctx context.Context, listID string) ([]todo.Member, error)
+	AddMember(ctx context.Context, listID, name string, role todo.Role) (*todo.Member, error)
+	RemoveMember(ctx context.Context, listID, name string) error
 }
 
 // ListsAPI manages TODO lists.
@@ -61,14 +112,30 @@ func NewListAPI(repo ListRepository) *ListsAPI {
 // Items of a TODO list.
 func (l *ListsAPI) Items(w http.ResponseWriter, r *http.Request) {
 	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
-		params := httprouter.ParamsFromContext(r.Context())
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		filter, errResp := itemFilterParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
 
-		listID := strings.TrimSpace(params.ByName("list_id"))
-		if listID == "" {
-			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"list_id" path param must not be blank`}
+		page, errResp := pageParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		items, next, err := l.repo.Items(r.Context(), listID, filter, page)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
 		}
 
-		items, err := l.repo.Items(r.Context(), listID)
 		if err != nil {
 			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
 		}
@@ -78,7 +145,7 @@ func (l *ListsAPI) Items(w http.ResponseWriter, r *http.Request) {
 			items = []todo.Item{}
 		}
 
-		return &Response{Body: &ItemsBody{Items: items}}, nil
+		return &Response{Body: &ItemsBody{Items: items, Next: nextCursor(w, r, next)}}, nil
 	}
 
 	handleRequest(h)(w, r)
@@ -87,23 +154,35 @@ func (l *ListsAPI) Items(w http.ResponseWriter, r *http.Request) {
 // List which contains TODO items.
 func (l *ListsAPI) List(w http.ResponseWriter, r *http.Request) {
 	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
-		params := httprouter.ParamsFromContext(r.Context())
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
 
-		listID := strings.TrimSpace(params.ByName("list_id"))
-		if listID == "" {
-			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"list_id" path param must not be blank`}
+		horizon, errResp := horizonParam(r)
+		if errResp != nil {
+			return nil, errResp
 		}
 
-		list, err := l.repo.List(r.Context(), listID)
+		include, errResp := includeParam(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		list, err := l.repo.List(r.Context(), listID, horizon, include)
 		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
 			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
 		}
 
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
 		if err != nil {
 			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
 		}
 
-		return &Response{Body: &ListBody{List: &list.List, DueItems: list.DueItems}}, nil
+		return &Response{Body: &ListBody{List: &list.List, DueItems: list.DueItems, Horizon: list.Horizon, UrgentDue: list.UrgentDue}}, nil
 	}
 
 	handleRequest(h)(w, r)
@@ -111,42 +190,228 @@ func (l *ListsAPI) List(w http.ResponseWriter, r *http.Request) {
 
 func (l *ListsAPI) Lists(w http.ResponseWriter, r *http.Request) {
 	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
-		lists, err := l.repo.Lists(r.Context())
+		page, errResp := pageParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		horizon, errResp := horizonParam(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		include, errResp := includeParam(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		lists, next, err := l.repo.Lists(r.Context(), page, horizon, include)
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+		}
+
 		if err != nil {
 			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
 		}
 
-		if lists == nil {
-			// Ensure we get an empty array in the response, not `null`
-			lists = []todo.DueList{}
+		// Ensure we get empty arrays in the response, not `null`
+		body := &ListsBody{Lists: []todo.DueList{}, Shared: []todo.DueList{}, Next: nextCursor(w, r, next)}
+
+		for _, list := range lists {
+			if list.Shared {
+				body.Shared = append(body.Shared, list)
+			} else {
+				body.Lists = append(body.Lists, list)
+			}
+		}
+
+		return &Response{Body: body}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// CreateList of TODO items, which starts out empty.
+func (l *ListsAPI) CreateList(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		var req ListRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		if req.Description == nil || strings.TrimSpace(*req.Description) == "" {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+		}
+
+		if h := req.DueHorizon.Value; h != nil && *h < 0 {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"dueHorizon" must not be negative`}
+		}
+
+		list, err := l.repo.CreateList(r.Context(), todo.List{Description: strings.TrimSpace(*req.Description), DueHorizon: req.DueHorizon.Value})
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		w.Header().Set("Location", "/api/v1/lists/"+list.ID)
+
+		return &Response{Status: http.StatusCreated, Body: &SavedListBody{List: list}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// UpdateList replaces (PUT) or modifies (PATCH) the fields of a TODO list.
+func (l *ListsAPI) UpdateList(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req ListRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		// PUT replaces the list, so an absent horizon is cleared, whereas PATCH only changes what is provided
+		update := todo.ListUpdate{DueHorizon: req.DueHorizon.Value, SetDueHorizon: req.DueHorizon.Set || r.Method == http.MethodPut}
+
+		switch {
+		case req.Description == nil && r.Method == http.MethodPut:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" is required`}
+		case req.Description == nil && !req.DueHorizon.Set:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: "at least one field must be provided"}
+		case req.Description != nil && strings.TrimSpace(*req.Description) == "":
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+		case update.DueHorizon != nil && *update.DueHorizon < 0:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"dueHorizon" must not be negative`}
+		case req.Description != nil:
+			desc := strings.TrimSpace(*req.Description)
+			update.Description = &desc
 		}
 
-		return &Response{Body: &ListsBody{Lists: lists}}, nil
+		list, err := l.repo.UpdateList(r.Context(), listID, update)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		return &Response{Body: &SavedListBody{List: list}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// DeleteList along with all of its TODO items, by moving it to the trash until it is restored or purged.
+func (l *ListsAPI) DeleteList(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		err := l.repo.DeleteList(r.Context(), listID)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		return &Response{Status: http.StatusNoContent}, nil
 	}
 
 	handleRequest(h)(w, r)
 }
 
+// pathParam which must be present and not blank.
+func pathParam(r *http.Request, name string) (string, *ErrorResponse) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	v := strings.TrimSpace(params.ByName(name))
+	if v == "" {
+		return "", &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf("%q path param must not be blank", name)}
+	}
+
+	return v, nil
+}
+
+// horizonParam from the `horizon` query param, overriding which items are considered due soon. Nil when not provided.
+func horizonParam(r *http.Request) (*time.Duration, *ErrorResponse) {
+	v := r.URL.Query().Get("horizon")
+	if v == "" {
+		return nil, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"horizon" query param must be a non-negative duration, such as "36h"`}
+	}
+
+	return &d, nil
+}
+
+// maxBodyBytes which will be read from a request body.
+const maxBodyBytes = 1 << 20
+
+// decodeBody of the request as JSON into v. Unknown fields are rejected, so that typos are not silently ignored.
+func decodeBody(r *http.Request, v any) *ErrorResponse {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf("invalid request body: %s", err)}
+	}
+
+	if dec.More() {
+		return &ErrorResponse{Status: http.StatusBadRequest, Error: "invalid request body: must contain a single JSON object"}
+	}
+
+	return nil
+}
+
 func handleRequest(h func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		enc := json.NewEncoder(w)
 
 		resp, err := h(w, r)
 		if err != nil {
-			type errPayload struct {
-				Error string `json:"error"`
-			}
-
-			w.WriteHeader(err.Status)
-			enc.Encode(&errPayload{Error: err.Error})
+			writeError(w, r, err)
+			return
+		}
 
-			if c := err.Cause; c != nil {
-				addLogAttrs(r.Context(), slog.String("error_cause", c.Error()))
-			}
+		if resp.Status != 0 {
+			w.WriteHeader(resp.Status)
+		}
 
-			return
+		if resp.Body != nil {
+			enc.Encode(resp.Body)
 		}
+	}
+}
+
+// writeError response to the client, logging the cause of the failure when there is one.
+func writeError(w http.ResponseWriter, r *http.Request, err *ErrorResponse) {
+	type errPayload struct {
+		Error string `json:"error"`
+	}
+
+	w.WriteHeader(err.Status)
+	json.NewEncoder(w).Encode(&errPayload{Error: err.Error})
 
-		enc.Encode(resp.Body)
+	if c := err.Cause; c != nil {
+		addLogAttrs(r.Context(), slog.Any("error_cause", c))
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ func TestListsAPI_Items(t *testing.T) {
 
 	type args struct {
 		ListID string
+		Query  string
 	}
 
 	type fields struct {
@@ -48,7 +50,7 @@ func TestListsAPI_Items(t *testing.T) {
 			Args: args{ListID: "1"},
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnItems(ctx, "1").Return(nil, errors.New("query failure"))
+					l.OnItems(ctx, "1", todo.ItemFilter{}, todo.Page{Limit: 100}).Return(nil, "", errors.New("query failure"))
 				},
 			},
 			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
@@ -57,7 +59,7 @@ func TestListsAPI_Items(t *testing.T) {
 			Args: args{ListID: "2"},
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnItems(ctx, "2").Return(nil, nil)
+					l.OnItems(ctx, "2", todo.ItemFilter{}, todo.Page{Limit: 100}).Return(nil, "", nil)
 				},
 			},
 			Want: want{Body: `{"items": []}`, Code: http.StatusOK},
@@ -71,19 +73,172 @@ func TestListsAPI_Items(t *testing.T) {
 						{ID: "1", Description: "Relax"},
 						{ID: "2", Description: "Golang-Syd Meetup June 2023", Due: &goSyd},
 					}
-					l.OnItems(ctx, "3").Return(items, nil)
+					l.OnItems(ctx, "3", todo.ItemFilter{}, todo.Page{Limit: 100}).Return(items, "", nil)
 				},
 			},
 			Want: want{
 				Body: `{
 					"items": [
-						{"id": "1", "description": "Relax", "due": null, "completed": null},
-						{"id": "2", "description": "Golang-Syd Meetup June 2023", "due": "2023-06-29T08:00:00Z", "completed": null}
+						{"id": "1", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+						{"id": "2", "description": "Golang-Syd Meetup June 2023", "due": "2023-06-29T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
 					]
 				}`,
 				Code: http.StatusOK,
 			},
 		},
+		"Invalid Limit": {
+			Args:   args{ListID: "3", Query: "limit=0"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"limit\" query param must be an integer between 1 and 1000"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Cursor": {
+			Args:   args{ListID: "3", Query: "after=!!"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"after\" query param is not a valid cursor"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Status": {
+			Args:   args{ListID: "3", Query: "status=done"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"status\" query param must be one of: open, completed, overdue"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Due Before": {
+			Args:   args{ListID: "3", Query: "due_before=tomorrow"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"due_before\" query param must be an RFC 3339 timestamp"}`, Code: http.StatusBadRequest},
+		},
+		"Due After not Before Due Before": {
+			Args:   args{ListID: "3", Query: "due_after=2023-06-29T00:00:00Z&due_before=2023-06-29T00:00:00Z"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"due_after\" query param must be before \"due_before\""}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Sort": {
+			Args:   args{ListID: "3", Query: "sort=priority"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"sort\" query param must be one of: position, id, due, -due, description"}`, Code: http.StatusBadRequest},
+		},
+		"Cursor not valid for Sort": {
+			Args: args{ListID: "3", Query: "sort=due&after=Mg"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnItems(ctx, "3", todo.ItemFilter{Sort: todo.ItemSortDue}, todo.Page{After: "2", Limit: 100}).
+						Return(nil, "", todo.InvalidArgumentError(`page key is not valid for sort "due"`))
+				},
+			},
+			Want: want{Body: `{"error": "page key is not valid for sort \"due\""}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Tag": {
+			Args:   args{ListID: "3", Query: "tag=%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tag\" query param must not be blank, or exceed 64 bytes"}`, Code: http.StatusBadRequest},
+		},
+		"Tag Match without Tag": {
+			Args:   args{ListID: "3", Query: "tag_match=all"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tag_match\" query param requires \"tag\""}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Tag Match": {
+			Args:   args{ListID: "3", Query: "tag=@home&tag_match=some"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tag_match\" query param must be one of: any, all"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Include": {
+			Args:   args{ListID: "3", Query: "include=hidden"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"include\" query param must be one of: archived, deleted"}`, Code: http.StatusBadRequest},
+		},
+		"Including Archived and Deleted": {
+			Args: args{ListID: "3", Query: "include=archived,deleted"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+					filter := todo.ItemFilter{Include: todo.Include{Archived: true, Deleted: true}}
+					items := []todo.Item{{ID: "1", Description: "Relax", Archived: &archived}}
+					l.OnItems(ctx, "3", filter, todo.Page{Limit: 100}).Return(items, "", nil)
+				},
+			},
+			Want: want{Body: `{"items": [{"id": "1", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null, "archived": "2023-07-03T09:00:00Z"}]}`, Code: http.StatusOK},
+		},
+		"Filtered by all Tags": {
+			Args: args{ListID: "3", Query: "tag=@Home&tag=@work&tag=@home&tag_match=all"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					filter := todo.ItemFilter{Tags: []string{"@home", "@work"}, TagMatch: todo.TagMatchAll}
+					items := []todo.Item{{ID: "1", Description: "Relax", Tags: todo.Tags{"@home", "@work"}}}
+					l.OnItems(ctx, "3", filter, todo.Page{Limit: 100}).Return(items, "", nil)
+				},
+			},
+			Want: want{Body: `{"items": [{"id": "1", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": ["@home", "@work"], "autoComplete": false, "progress": null}]}`, Code: http.StatusOK},
+		},
+		"Filtered and Sorted": {
+			Args: args{ListID: "3", Query: "status=overdue&due_after=2023-06-01T00:00:00Z&due_before=2023-07-01T00:00:00Z&sort=-due"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					filter := todo.ItemFilter{
+						Status:    todo.ItemStatusOverdue,
+						DueBefore: ptr(time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)),
+						DueAfter:  ptr(time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)),
+						Sort:      todo.ItemSortDueDesc,
+					}
+					l.OnItems(ctx, "3", filter, todo.Page{Limit: 100}).Return([]todo.Item{{ID: "1", Description: "Relax"}}, "", nil)
+				},
+			},
+			Want: want{Body: `{"items": [{"id": "1", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}]}`, Code: http.StatusOK},
+		},
+		"Invalid Tree": {
+			Args:   args{ListID: "3", Query: "tree=maybe"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tree\" query param must be a boolean"}`, Code: http.StatusBadRequest},
+		},
+		"Tree of Subtasks": {
+			Args: args{ListID: "3", Query: "tree=true"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					items := []todo.Item{{
+						ID:          "1",
+						Description: "Relax",
+						Progress:    &todo.Progress{Done: 1, Total: 2},
+						Subtasks: []todo.Item{
+							{ID: "2", ParentID: ptr("1"), Description: "Read", Completed: ptr(time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC))},
+							{ID: "3", ParentID: ptr("1"), Description: "Nap"},
+						},
+					}}
+					l.OnItems(ctx, "3", todo.ItemFilter{Tree: true}, todo.Page{Limit: 100}).Return(items, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{"items": [{
+					"id": "1", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": "1/2 done",
+					"subtasks": [
+						{"id": "2", "description": "Read", "due": null, "completed": "2023-06-29T08:00:00Z", "parentId": "1", "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+						{"id": "3", "description": "Nap", "due": null, "completed": null, "parentId": "1", "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
+					]
+				}]}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Page with Next": {
+			Args: args{ListID: "3", Query: "limit=2&after=Mg"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					items := []todo.Item{
+						{ID: "3", Description: "Relax"},
+						{ID: "4", Description: "Washing"},
+					}
+					l.OnItems(ctx, "3", todo.ItemFilter{}, todo.Page{After: "2", Limit: 2}).Return(items, "4", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"items": [
+						{"id": "3", "description": "Relax", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+						{"id": "4", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
+					],
+					"next": "NA"
+				}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/lists/3/items?after=NA&limit=2>; rel="next"`}},
+			},
+		},
 	}
 
 	for name, tt := range testTable {
@@ -103,9 +258,9 @@ func TestListsAPI_Items(t *testing.T) {
 			tt.Fields.MockExpectations(ctx, &repo)
 			defer mock.AssertExpectationsForObjects(t, &repo)
 
-			h := routes.Handler(listsAPI, testLogger)
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
 
-			route := fmt.Sprintf("/api/v1/lists/%s/items", tt.Args.ListID)
+			route := fmt.Sprintf("/api/v1/lists/%s/items?%s", tt.Args.ListID, tt.Args.Query)
 			req := httptest.NewRequest(http.MethodGet, route, http.NoBody).WithContext(ctx)
 			rec := httptest.NewRecorder()
 
@@ -114,6 +269,7 @@ func TestListsAPI_Items(t *testing.T) {
 			res := rec.Result()
 
 			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+			assert.Equal(t, tt.Want.Headers.Values("Link"), res.Header.Values("Link"), "HTTP Link Header")
 
 			body, err := io.ReadAll(res.Body)
 			assert.NoError(t, err, "Body Read Error")
@@ -127,6 +283,7 @@ func TestListsAPI_List(t *testing.T) {
 
 	type args struct {
 		ListID string
+		Query  string
 	}
 
 	type fields struct {
@@ -153,7 +310,7 @@ func TestListsAPI_List(t *testing.T) {
 			Args: args{ListID: "1"},
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnList(ctx, "1").Return(nil, errors.New("query failure"))
+					l.OnList(ctx, "1", nil, todo.Include{}).Return(nil, errors.New("query failure"))
 				},
 			},
 			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
@@ -162,7 +319,7 @@ func TestListsAPI_List(t *testing.T) {
 			Args: args{ListID: "2"},
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnList(ctx, "2").Return(nil, todo.NotFoundError("list not found"))
+					l.OnList(ctx, "2", nil, todo.Include{}).Return(nil, todo.NotFoundError("list not found"))
 				},
 			},
 			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
@@ -171,14 +328,87 @@ func TestListsAPI_List(t *testing.T) {
 			Args: args{ListID: "1"},
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					list := &todo.DueList{List: todo.List{ID: "1", Description: "Golang-Syd Meetup June 2023"}}
-					l.OnList(ctx, "1").Return(list, nil)
+					list := &todo.DueList{Horizon: todo.Duration(24 * time.Hour), List: todo.List{ID: "1", Description: "Golang-Syd Meetup June 2023"}}
+					l.OnList(ctx, "1", nil, todo.Include{}).Return(list, nil)
 				},
 			},
 			Want: want{
 				Body: `{
 					"list": {"id": "1", "description": "Golang-Syd Meetup June 2023"},
-					"dueItems": null
+					"dueItems": null,
+					"horizon": "24h0m0s",
+					"urgentDue": 0
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Urgent Items Due": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					list := &todo.DueList{
+						Horizon:   todo.Duration(24 * time.Hour),
+						List:      todo.List{ID: "1", Description: "Golang-Syd Meetup June 2023"},
+						DueItems:  []todo.Item{{ID: "1", Description: "Book Venue", Priority: todo.PriorityUrgent}},
+						UrgentDue: 1,
+					}
+					l.OnList(ctx, "1", nil, todo.Include{}).Return(list, nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"list": {"id": "1", "description": "Golang-Syd Meetup June 2023"},
+					"dueItems": [{"id": "1", "description": "Book Venue", "due": null, "completed": null, "parentId": null, "priority": "urgent", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}],
+					"horizon": "24h0m0s",
+					"urgentDue": 1
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Invalid Horizon": {
+			Args:   args{ListID: "1", Query: "horizon=-1h"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"horizon\" query param must be a non-negative duration, such as \"36h\""}`, Code: http.StatusBadRequest},
+		},
+		"Horizon Override": {
+			Args: args{ListID: "1", Query: "horizon=36h"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					list := &todo.DueList{
+						Horizon: todo.Duration(36 * time.Hour),
+						List:    todo.List{ID: "1", Description: "Holiday", DueHorizon: ptr(todo.Duration(7 * 24 * time.Hour))},
+					}
+					l.OnList(ctx, "1", ptr(36*time.Hour), todo.Include{}).Return(list, nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"list": {"id": "1", "description": "Holiday", "dueHorizon": "168h0m0s"},
+					"dueItems": null,
+					"horizon": "36h0m0s",
+					"urgentDue": 0
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Including Deleted": {
+			Args: args{ListID: "1", Query: "include=deleted"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					deleted := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+					list := &todo.DueList{
+						Horizon: todo.Duration(24 * time.Hour),
+						List:    todo.List{ID: "1", Description: "Holiday", Deleted: &deleted},
+					}
+					l.OnList(ctx, "1", nil, todo.Include{Deleted: true}).Return(list, nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"list": {"id": "1", "description": "Holiday", "deleted": "2023-07-03T09:00:00Z"},
+					"dueItems": null,
+					"horizon": "24h0m0s",
+					"urgentDue": 0
 				}`,
 				Code: http.StatusOK,
 			},
@@ -188,24 +418,27 @@ func TestListsAPI_List(t *testing.T) {
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
 					list := &todo.DueList{
-						List: todo.List{ID: "1", Description: "Golang-Syd Meetup June 2023"},
+						Horizon: todo.Duration(24 * time.Hour),
+						List:    todo.List{ID: "1", Description: "Golang-Syd Meetup June 2023"},
 						DueItems: []todo.Item{
 							{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 							{ID: "2", Description: "Mop Floors", Due: ptr(time.Date(2023, time.June, 21, 10, 0, 0, 0, time.UTC))},
 							{ID: "3", Description: "Groceries", Due: ptr(time.Date(2023, time.June, 22, 2, 0, 0, 0, time.UTC))},
 						},
 					}
-					l.OnList(ctx, "1").Return(list, nil)
+					l.OnList(ctx, "1", nil, todo.Include{}).Return(list, nil)
 				},
 			},
 			Want: want{
 				Body: `{
 					"list": {"id": "1", "description": "Golang-Syd Meetup June 2023"},
 					"dueItems": [
-						{"id": "1", "description": "Washing", "due": "2023-06-20T08:00:00Z", "completed": null},
-						{"id": "2", "description": "Mop Floors", "due": "2023-06-21T10:00:00Z", "completed": null},
-						{"id": "3", "description": "Groceries", "due": "2023-06-22T02:00:00Z", "completed": null}
-					]
+						{"id": "1", "description": "Washing", "due": "2023-06-20T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+						{"id": "2", "description": "Mop Floors", "due": "2023-06-21T10:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+						{"id": "3", "description": "Groceries", "due": "2023-06-22T02:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
+					],
+					"horizon": "24h0m0s",
+					"urgentDue": 0
 				}`,
 				Code: http.StatusOK,
 			},
@@ -229,9 +462,9 @@ func TestListsAPI_List(t *testing.T) {
 			tt.Fields.MockExpectations(ctx, &repo)
 			defer mock.AssertExpectationsForObjects(t, &repo)
 
-			h := routes.Handler(listsAPI, testLogger)
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
 
-			route := fmt.Sprintf("/api/v1/lists/%s", tt.Args.ListID)
+			route := fmt.Sprintf("/api/v1/lists/%s?%s", tt.Args.ListID, tt.Args.Query)
 			req := httptest.NewRequest(http.MethodGet, route, http.NoBody).WithContext(ctx)
 			rec := httptest.NewRecorder()
 
@@ -251,6 +484,10 @@ func TestListsAPI_List(t *testing.T) {
 func TestListsAPI_Lists(t *testing.T) {
 	t.Parallel()
 
+	type args struct {
+		Query string
+	}
+
 	type fields struct {
 		MockExpectations func(ctx context.Context, l *listRepo)
 	}
@@ -262,31 +499,42 @@ func TestListsAPI_Lists(t *testing.T) {
 	}
 
 	testTable := map[string]struct {
+		Args   args
 		Fields fields
 		Want   want
 	}{
 		"Query failure": {
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnLists(ctx).Return(nil, errors.New("query failure"))
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", errors.New("query failure"))
 				},
 			},
 			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
 		},
+		"Tampered Cursor": {
+			Args: args{Query: "after=YWJj"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnLists(ctx, todo.Page{After: "abc", Limit: 100}, nil, todo.Include{}).Return(nil, "", todo.InvalidArgumentError("page key is not valid"))
+				},
+			},
+			Want: want{Body: `{"error": "page key is not valid"}`, Code: http.StatusBadRequest},
+		},
 		"No Lists": {
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
-					l.OnLists(ctx).Return(nil, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(nil, "", nil)
 				},
 			},
-			Want: want{Body: `{"lists": []}`, Code: http.StatusOK},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
 		},
 		"Lists": {
 			Fields: fields{
 				MockExpectations: func(ctx context.Context, l *listRepo) {
 					lists := []todo.DueList{
 						{
-							List: todo.List{ID: "1", Description: "Chores"},
+							Horizon: todo.Duration(24 * time.Hour),
+							List:    todo.List{ID: "1", Description: "Chores"},
 							DueItems: []todo.Item{
 								{ID: "1", Description: "Washing", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 								{ID: "2", Description: "Mop Floors", Due: ptr(time.Date(2023, time.June, 21, 10, 0, 0, 0, time.UTC))},
@@ -294,7 +542,8 @@ func TestListsAPI_Lists(t *testing.T) {
 							},
 						},
 						{
-							List: todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023"},
+							Horizon: todo.Duration(48 * time.Hour),
+							List:    todo.List{ID: "2", Description: "Golang-Syd Meetup June 2023", DueHorizon: ptr(todo.Duration(48 * time.Hour))},
 							DueItems: []todo.Item{
 								{ID: "4", Description: "Prepare Presentation", Due: ptr(time.Date(2023, time.June, 20, 8, 0, 0, 0, time.UTC))},
 								{ID: "5", Description: "Practice", Due: ptr(time.Date(2023, time.June, 26, 0, 0, 0, 0, time.UTC))},
@@ -302,7 +551,7 @@ func TestListsAPI_Lists(t *testing.T) {
 							},
 						},
 					}
-					l.OnLists(ctx).Return(lists, nil)
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(lists, "", nil)
 				},
 			},
 			Want: want{
@@ -310,25 +559,115 @@ func TestListsAPI_Lists(t *testing.T) {
 					"lists": [
 						{
 							"list": {"id": "1", "description": "Chores"},
+							"horizon": "24h0m0s",
+							"urgentDue": 0,
 							"dueItems": [
-								{"id": "1", "description": "Washing", "due": "2023-06-20T08:00:00Z", "completed": null},
-								{"id": "2", "description": "Mop Floors", "due": "2023-06-21T10:00:00Z", "completed": null},
-								{"id": "3", "description": "Groceries", "due": "2023-06-22T02:00:00Z", "completed": null}
+								{"id": "1", "description": "Washing", "due": "2023-06-20T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+								{"id": "2", "description": "Mop Floors", "due": "2023-06-21T10:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+								{"id": "3", "description": "Groceries", "due": "2023-06-22T02:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
 							]
 						},
 						{
-							"list": {"id": "2", "description": "Golang-Syd Meetup June 2023"},
+							"list": {"id": "2", "description": "Golang-Syd Meetup June 2023", "dueHorizon": "48h0m0s"},
+							"horizon": "48h0m0s",
+							"urgentDue": 0,
 							"dueItems": [
-								{"id": "4", "description": "Prepare Presentation", "due": "2023-06-20T08:00:00Z", "completed": null},
-								{"id": "5", "description": "Practice", "due": "2023-06-26T00:00:00Z", "completed": null},
-								{"id": "6", "description": "Attend & Present", "due": "2023-06-29T08:00:00Z", "completed": null}
+								{"id": "4", "description": "Prepare Presentation", "due": "2023-06-20T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+								{"id": "5", "description": "Practice", "due": "2023-06-26T00:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+								{"id": "6", "description": "Attend & Present", "due": "2023-06-29T08:00:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
 							]
 						}
-					]
+					],
+					"shared": []
 				}`,
 				Code: http.StatusOK,
 			},
 		},
+		"Owned and Shared Lists": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					lists := []todo.DueList{
+						{Horizon: todo.Duration(24 * time.Hour), List: todo.List{ID: "1", Description: "Chores"}, Role: todo.RoleOwner},
+						{Horizon: todo.Duration(24 * time.Hour), List: todo.List{ID: "2", Description: "Holiday"}, Role: todo.RoleEditor, Shared: true},
+					}
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{}).Return(lists, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"lists": [{"list": {"id": "1", "description": "Chores"}, "dueItems": null, "horizon": "24h0m0s", "urgentDue": 0, "role": "owner"}],
+					"shared": [{"list": {"id": "2", "description": "Holiday"}, "dueItems": null, "horizon": "24h0m0s", "urgentDue": 0, "role": "editor"}]
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Invalid Limit": {
+			Args:   args{Query: "limit=1001"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"limit\" query param must be an integer between 1 and 1000"}`, Code: http.StatusBadRequest},
+		},
+		"Horizon Override": {
+			Args: args{Query: "horizon=0s"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnLists(ctx, todo.Page{Limit: 100}, ptr(time.Duration(0)), todo.Include{}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"lists": [], "shared": []}`, Code: http.StatusOK},
+		},
+		"Including Archived": {
+			Args: args{Query: "include=archived"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					archived := time.Date(2023, time.July, 3, 9, 0, 0, 0, time.UTC)
+					lists := []todo.DueList{{Horizon: todo.Duration(24 * time.Hour), List: todo.List{ID: "1", Description: "Chores", Archived: &archived}}}
+					l.OnLists(ctx, todo.Page{Limit: 100}, nil, todo.Include{Archived: true}).Return(lists, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"lists": [{"list": {"id": "1", "description": "Chores", "archived": "2023-07-03T09:00:00Z"}, "dueItems": null, "horizon": "24h0m0s", "urgentDue": 0}],
+					"shared": []
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Invalid Include": {
+			Args:   args{Query: "include=archived&include=all"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"include\" query param must be one of: archived, deleted"}`, Code: http.StatusBadRequest},
+		},
+		"Page with Next": {
+			Args: args{Query: "limit=1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					lists := []todo.DueList{{List: todo.List{ID: "1", Description: "Chores"}}}
+					l.OnLists(ctx, todo.Page{Limit: 1}, nil, todo.Include{}).Return(lists, "1", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"lists": [{"list": {"id": "1", "description": "Chores"}, "dueItems": null, "horizon": "0s", "urgentDue": 0}],
+					"shared": [],
+					"next": "MQ"
+				}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/lists?after=MQ&limit=1>; rel="next"`}},
+			},
+		},
+		"Last Page": {
+			Args: args{Query: "limit=1&after=MQ"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					lists := []todo.DueList{{List: todo.List{ID: "2", Description: "Holiday"}}}
+					l.OnLists(ctx, todo.Page{After: "1", Limit: 1}, nil, todo.Include{}).Return(lists, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{"lists": [{"list": {"id": "2", "description": "Holiday"}, "dueItems": null, "horizon": "0s", "urgentDue": 0}], "shared": []}`,
+				Code: http.StatusOK,
+			},
+		},
 	}
 
 	for name, tt := range testTable {
@@ -348,9 +687,9 @@ func TestListsAPI_Lists(t *testing.T) {
 			tt.Fields.MockExpectations(ctx, &repo)
 			defer mock.AssertExpectationsForObjects(t, &repo)
 
-			h := routes.Handler(listsAPI, testLogger)
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
 
-			req := httptest.NewRequest(http.MethodGet, "/api/v1/lists", http.NoBody).WithContext(ctx)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lists?"+tt.Args.Query, http.NoBody).WithContext(ctx)
 			rec := httptest.NewRecorder()
 
 			h.ServeHTTP(rec, req)
@@ -358,6 +697,7 @@ func TestListsAPI_Lists(t *testing.T) {
 			res := rec.Result()
 
 			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+			assert.Equal(t, tt.Want.Headers.Values("Link"), res.Header.Values("Link"), "HTTP Link Header")
 
 			body, err := io.ReadAll(res.Body)
 			assert.NoError(t, err, "Body Read Error")
@@ -366,41 +706,440 @@ func TestListsAPI_Lists(t *testing.T) {
 	}
 }
 
+func TestListsAPI_CreateList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Invalid JSON": {
+			Args:   args{Body: `{"description":`},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: unexpected EOF"}`, Code: http.StatusBadRequest},
+		},
+		"Unknown Field": {
+			Args:   args{Body: `{"title": "Chores"}`},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: json: unknown field \"title\""}`, Code: http.StatusBadRequest},
+		},
+		"Missing Description": {
+			Args:   args{Body: `{}`},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Description": {
+			Args:   args{Body: `{"description": "  "}`},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Query failure": {
+			Args: args{Body: `{"description": "Chores"}`},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateList(ctx, todo.List{Description: "Chores"}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Invalid Horizon": {
+			Args:   args{Body: `{"description": "Chores", "dueHorizon": "1 day"}`},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: time: unknown unit \" day\" in duration \"1 day\""}`, Code: http.StatusBadRequest},
+		},
+		"Created with Horizon": {
+			Args: args{Body: `{"description": "Chores", "dueHorizon": "36h"}`},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					horizon := ptr(todo.Duration(36 * time.Hour))
+					l.OnCreateList(ctx, todo.List{Description: "Chores", DueHorizon: horizon}).
+						Return(&todo.List{ID: "5001", Description: "Chores", DueHorizon: horizon}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"list": {"id": "5001", "description": "Chores", "dueHorizon": "36h0m0s"}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/5001"}},
+			},
+		},
+		"Created": {
+			Args: args{Body: `{"description": " Chores "}`},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnCreateList(ctx, todo.List{Description: "Chores"}).Return(&todo.List{ID: "5001", Description: "Chores"}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"list": {"id": "5001", "description": "Chores"}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/5001"}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lists", strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_UpdateList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ListID string
+		Method string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty List ID Path Param": {
+			Args:   args{Body: `{"description": "Chores"}`, ListID: "%20", Method: http.MethodPut},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"PUT Missing Description": {
+			Args:   args{Body: `{}`, ListID: "1", Method: http.MethodPut},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" is required"}`, Code: http.StatusBadRequest},
+		},
+		"PATCH No Fields": {
+			Args:   args{Body: `{}`, ListID: "1", Method: http.MethodPatch},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "at least one field must be provided"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Description": {
+			Args:   args{Body: `{"description": ""}`, ListID: "1", Method: http.MethodPatch},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Not Found": {
+			Args: args{Body: `{"description": "Chores"}`, ListID: "2", Method: http.MethodPut},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateList(ctx, "2", todo.ListUpdate{Description: ptr("Chores"), SetDueHorizon: true}).Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"description": "Chores"}`, ListID: "1", Method: http.MethodPut},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateList(ctx, "1", todo.ListUpdate{Description: ptr("Chores"), SetDueHorizon: true}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"PUT Updated": {
+			Args: args{Body: `{"description": "Chores"}`, ListID: "1", Method: http.MethodPut},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateList(ctx, "1", todo.ListUpdate{Description: ptr("Chores"), SetDueHorizon: true}).Return(&todo.List{ID: "1", Description: "Chores"}, nil)
+				},
+			},
+			Want: want{Body: `{"list": {"id": "1", "description": "Chores"}}`, Code: http.StatusOK},
+		},
+		"Negative Horizon": {
+			Args:   args{Body: `{"dueHorizon": "-1h"}`, ListID: "1", Method: http.MethodPatch},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"dueHorizon\" must not be negative"}`, Code: http.StatusBadRequest},
+		},
+		"PATCH Horizon": {
+			Args: args{Body: `{"dueHorizon": "48h"}`, ListID: "3", Method: http.MethodPatch},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					horizon := ptr(todo.Duration(48 * time.Hour))
+					l.OnUpdateList(ctx, "3", todo.ListUpdate{DueHorizon: horizon, SetDueHorizon: true}).
+						Return(&todo.List{ID: "3", Description: "Holiday", DueHorizon: horizon}, nil)
+				},
+			},
+			Want: want{Body: `{"list": {"id": "3", "description": "Holiday", "dueHorizon": "48h0m0s"}}`, Code: http.StatusOK},
+		},
+		"PATCH Updated": {
+			Args: args{Body: `{"description": "Holiday"}`, ListID: "3", Method: http.MethodPatch},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUpdateList(ctx, "3", todo.ListUpdate{Description: ptr("Holiday")}).Return(&todo.List{ID: "3", Description: "Holiday"}, nil)
+				},
+			},
+			Want: want{Body: `{"list": {"id": "3", "description": "Holiday"}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s", tt.Args.ListID)
+			req := httptest.NewRequest(tt.Args.Method, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_DeleteList(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Empty List ID Path Param": {
+			Args:   args{ListID: "%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Not Found": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteList(ctx, "2").Return(todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Forbidden": {
+			Args: args{ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteList(ctx, "2").Return(todo.ForbiddenError(`owner role on list "2" is required`))
+				},
+			},
+			Want: want{Body: `{"error": "owner role on list \"2\" is required"}`, Code: http.StatusForbidden},
+		},
+		"Query failure": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteList(ctx, "1").Return(errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Deleted": {
+			Args: args{ListID: "1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnDeleteList(ctx, "1").Return(nil)
+				},
+			},
+			Want: want{Code: http.StatusNoContent},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s", tt.Args.ListID)
+			req := httptest.NewRequest(http.MethodDelete, route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			if tt.Want.Body == "" {
+				assert.Empty(t, body, "HTTP Response Body")
+				return
+			}
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
 type listRepo struct {
 	mock.Mock
 }
 
-func (l *listRepo) Items(ctx context.Context, listID string) ([]todo.Item, error) {
-	args := l.Called(testContext(ctx), listID)
-	return args.Get(0).([]todo.Item), args.Error(1)
+func (l *listRepo) Items(ctx context.Context, listID string, filter todo.ItemFilter, page todo.Page) ([]todo.Item, string, error) {
+	args := l.Called(testContext(ctx), listID, filter, page)
+	return args.Get(0).([]todo.Item), args.String(1), args.Error(2)
 }
 
 // OnItems provides a type-safe mock setup function, used instead of using 'On("Items, ...)'
-func (l *listRepo) OnItems(ctx context.Context, listID string) *call2[[]todo.Item, error] {
-	m := l.On("Items", testContext(ctx), listID)
-	return &call2[[]todo.Item, error]{m: m}
+func (l *listRepo) OnItems(ctx context.Context, listID string, filter todo.ItemFilter, page todo.Page) *call3[[]todo.Item, string, error] {
+	m := l.On("Items", testContext(ctx), listID, filter, page)
+	return &call3[[]todo.Item, string, error]{m: m}
 }
 
-func (l *listRepo) List(ctx context.Context, listID string) (*todo.DueList, error) {
-	args := l.Called(testContext(ctx), listID)
+func (l *listRepo) List(ctx context.Context, listID string, horizon *time.Duration, include todo.Include) (*todo.DueList, error) {
+	args := l.Called(testContext(ctx), listID, horizon, include)
 	return args.Get(0).(*todo.DueList), args.Error(1)
 }
 
 // OnList provides a type-safe mock setup function, used instead of using 'On("List, ...)'
-func (l *listRepo) OnList(ctx context.Context, listID string) *call2[*todo.DueList, error] {
-	m := l.On("List", testContext(ctx), listID)
+func (l *listRepo) OnList(ctx context.Context, listID string, horizon *time.Duration, include todo.Include) *call2[*todo.DueList, error] {
+	m := l.On("List", testContext(ctx), listID, horizon, include)
 	return &call2[*todo.DueList, error]{m: m}
 }
 
-func (l *listRepo) Lists(ctx context.Context) ([]todo.DueList, error) {
-	args := l.Called(testContext(ctx))
-	return args.Get(0).([]todo.DueList), args.Error(1)
+func (l *listRepo) Lists(ctx context.Context, page todo.Page, horizon *time.Duration, include todo.Include) ([]todo.DueList, string, error) {
+	args := l.Called(testContext(ctx), page, horizon, include)
+	return args.Get(0).([]todo.DueList), args.String(1), args.Error(2)
 }
 
 // OnLists provides a type-safe mock setup function, used instead of using 'On("Lists, ...)'
-func (l *listRepo) OnLists(ctx context.Context) *call2[[]todo.DueList, error] {
-	m := l.On("Lists", testContext(ctx))
-	return &call2[[]todo.DueList, error]{m: m}
+func (l *listRepo) OnLists(ctx context.Context, page todo.Page, horizon *time.Duration, include todo.Include) *call3[[]todo.DueList, string, error] {
+	m := l.On("Lists", testContext(ctx), page, horizon, include)
+	return &call3[[]todo.DueList, string, error]{m: m}
+}
+
+func (l *listRepo) CreateList(ctx context.Context, list todo.List) (*todo.List, error) {
+	args := l.Called(testContext(ctx), list)
+	return args.Get(0).(*todo.List), args.Error(1)
+}
+
+// OnCreateList provides a type-safe mock setup function, used instead of using 'On("CreateList, ...)'
+func (l *listRepo) OnCreateList(ctx context.Context, list todo.List) *call2[*todo.List, error] {
+	m := l.On("CreateList", testContext(ctx), list)
+	return &call2[*todo.List, error]{m: m}
+}
+
+func (l *listRepo) UpdateList(ctx context.Context, listID string, update todo.ListUpdate) (*todo.List, error) {
+	args := l.Called(testContext(ctx), listID, update)
+	return args.Get(0).(*todo.List), args.Error(1)
+}
+
+// OnUpdateList provides a type-safe mock setup function, used instead of using 'On("UpdateList, ...)'
+func (l *listRepo) OnUpdateList(ctx context.Context, listID string, update todo.ListUpdate) *call2[*todo.List, error] {
+	m := l.On("UpdateList", testContext(ctx), listID, update)
+	return &call2[*todo.List, error]{m: m}
+}
+
+func (l *listRepo) DeleteList(ctx context.Context, listID string) error {
+	args := l.Called(testContext(ctx), listID)
+	return args.Error(0)
+}
+
+// OnDeleteList provides a type-safe mock setup function, used instead of using 'On("DeleteList, ...)'
+func (l *listRepo) OnDeleteList(ctx context.Context, listID string) *call1[error] {
+	m := l.On("DeleteList", testContext(ctx), listID)
+	return &call1[error]{m: m}
 }
 
 func ptr[T any](t T) *T {
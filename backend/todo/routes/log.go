@@ -2,9 +2,19 @@ package routes
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 )
 
@@ -21,13 +31,24 @@ func withLogAttrs(ctx context.Context) (context.Context, *reqLog) {
 	return context.WithValue(ctx, logCtxKey, l), l
 }
 
+// addLogAttrs to the log of the request. Attrs with error values are also recorded as events of the span of the
+// request, which is marked as failed.
 func addLogAttrs(ctx context.Context, attrs ...slog.Attr) {
 	v := ctx.Value(logCtxKey).(*reqLog)
 
 	v.attrs = append(v.attrs, attrs...)
+
+	span := trace.SpanFromContext(ctx)
+
+	for _, a := range attrs {
+		if err, ok := a.Value.Any().(error); ok {
+			span.RecordError(err, trace.WithAttributes(attribute.String("log.attr", a.Key)))
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
 }
 
-func requestLog(h http.Handler, logger *slog.Logger, route string) http.Handler {
+func requestLog(h http.Handler, logger *slog.Logger, accessLog *AccessLog, route string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, rl := withLogAttrs(r.Context())
 
@@ -38,8 +59,7 @@ func requestLog(h http.Handler, logger *slog.Logger, route string) http.Handler
 		dur := time.Since(start)
 
 		sc := cw.StatusCode()
-		// TODO: more HTTP attributes...
-		log := logger.With(slog.String("http.path", r.URL.Path), slog.String("http.route", route), slog.Int("http.status", sc), slog.String("http.request_duration", dur.String()))
+		log := logger.With(httpLogAttrs(r, route, cw, dur)...)
 
 		lvl := codeToLevel(sc)
 
@@ -52,6 +72,10 @@ func requestLog(h http.Handler, logger *slog.Logger, route string) http.Handler
 			log = log.With(attr...)
 		}
 
+		if accessLog != nil {
+			accessLog.write(r, cw, start, rl.attrs)
+		}
+
 		if sc >= http.StatusBadRequest {
 			log.Log(r.Context(), lvl, "HTTP Request Error")
 			return
@@ -61,6 +85,179 @@ func requestLog(h http.Handler, logger *slog.Logger, route string) http.Handler
 	})
 }
 
+// httpLogAttrs of the request and its response, named by the OpenTelemetry HTTP semantic conventions. The duration is
+// in milliseconds, the same as the duration metric.
+func httpLogAttrs(r *http.Request, route string, cw *CaptureWriter, dur time.Duration) []any {
+	client, socket := clientAddress(r)
+
+	attrs := []any{
+		slog.String(string(semconv.HTTPRequestMethodKey), r.Method),
+		slog.String(string(semconv.URLPathKey), r.URL.Path),
+		slog.String(string(semconv.HTTPRouteKey), route),
+		slog.Int(string(semconv.HTTPResponseStatusCodeKey), cw.StatusCode()),
+		slog.Int(string(semconv.HTTPResponseBodySizeKey), cw.bytes),
+		slog.Float64("http.server.duration", float64(dur)/float64(time.Millisecond)),
+		slog.String(string(semconv.NetworkProtocolNameKey), "http"),
+		slog.String(string(semconv.NetworkProtocolVersionKey), protocolVersion(r)),
+		slog.String(string(semconv.ClientAddressKey), client),
+	}
+
+	if socket != client {
+		attrs = append(attrs, slog.String(string(semconv.ClientSocketAddressKey), socket))
+	}
+
+	if r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String(string(semconv.URLQueryKey), r.URL.RawQuery))
+	}
+
+	// Content length is unknown (-1) for chunked requests
+	if r.ContentLength >= 0 {
+		attrs = append(attrs, slog.Int64(string(semconv.HTTPRequestBodySizeKey), r.ContentLength))
+	}
+
+	if ua := r.UserAgent(); ua != "" {
+		attrs = append(attrs, slog.String(string(semconv.UserAgentOriginalKey), ua))
+	}
+
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		attrs = append(attrs, slog.String("http.request.header.x-request-id", id))
+	}
+
+	return attrs
+}
+
+// clientAddress of the request, as forwarded by a proxy in front of the server, along with the address of the socket
+// which the request was received from. Forwarded addresses are only logged, so are not required to be trusted.
+func clientAddress(r *http.Request) (client, socket string) {
+	socket = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		socket = host
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		// The first address is the client, followed by any proxies which the request passed through
+		client, _, _ = strings.Cut(fwd, ",")
+		return strings.TrimSpace(client), socket
+	}
+
+	return socket, socket
+}
+
+// protocolVersion of HTTP which the request was made with, such as 1.1 or 2.
+func protocolVersion(r *http.Request) string {
+	if r.ProtoMajor >= 2 {
+		return strconv.Itoa(r.ProtoMajor)
+	}
+
+	return fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)
+}
+
+// AccessLogFormat of the lines written to an access log.
+type AccessLogFormat string
+
+const (
+	// CommonLogFormat of the NCSA HTTPd, with the client, user, time, request line, status and response size.
+	CommonLogFormat AccessLogFormat = "common"
+	// CombinedLogFormat also includes the referer and user agent.
+	CombinedLogFormat AccessLogFormat = "combined"
+)
+
+// ParseAccessLogFormat which must be one of the known formats.
+func ParseAccessLogFormat(s string) (AccessLogFormat, error) {
+	f := AccessLogFormat(s)
+	if f != CommonLogFormat && f != CombinedLogFormat {
+		return "", fmt.Errorf("unknown access log format %q, must be one of %q or %q", s, CommonLogFormat, CombinedLogFormat)
+	}
+
+	return f, nil
+}
+
+// AccessLog where a line is written for each request, in addition to the structured request logs, for tools which only
+// read the traditional log formats.
+type AccessLog struct {
+	format AccessLogFormat
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewAccessLog(w io.Writer, format AccessLogFormat) *AccessLog {
+	return &AccessLog{format: format, w: w}
+}
+
+var accessLogEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// write the line for the request, where the user is the ID of the authenticated user, if any.
+func (l *AccessLog) write(r *http.Request, cw *CaptureWriter, start time.Time, attrs []slog.Attr) {
+	client, _ := clientAddress(r)
+
+	user := "-"
+	for _, a := range attrs {
+		if a.Key == "enduser.id" {
+			user = a.Value.String()
+		}
+	}
+
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	size := "-"
+	if cw.bytes > 0 {
+		size = strconv.Itoa(cw.bytes)
+	}
+
+	line := fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`, client, user, start.Format("02/Jan/2006:15:04:05 -0700"), r.Method, accessLogEscaper.Replace(uri), r.Proto, cw.StatusCode(), size)
+
+	if l.format == CombinedLogFormat {
+		line += fmt.Sprintf(` "%s" "%s"`, accessLogField(r.Referer()), accessLogField(r.UserAgent()))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	io.WriteString(l.w, line+"\n")
+}
+
+// accessLogField which is quoted, or "-" when empty.
+func accessLogField(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return accessLogEscaper.Replace(s)
+}
+
+// TraceLogHandler adds the IDs of the trace and span within the context of each record, so that logs can be found from
+// traces, and traces from logs.
+type TraceLogHandler struct {
+	slog.Handler
+}
+
+// NewTraceLogHandler which wraps the handler, where records are written.
+func NewTraceLogHandler(h slog.Handler) *TraceLogHandler {
+	return &TraceLogHandler{Handler: h}
+}
+
+func (h *TraceLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		// The record is shared with any other handlers, so must not be modified
+		r = r.Clone()
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *TraceLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceLogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *TraceLogHandler) WithGroup(name string) slog.Handler {
+	return &TraceLogHandler{Handler: h.Handler.WithGroup(name)}
+}
+
 func codeToLevel(c int) slog.Level {
 	var l slog.Level
 
@@ -0,0 +1,299 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestTraceLogHandler(t *testing.T) {
+	t.Parallel()
+
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	sc := span.SpanContext()
+
+	testTable := map[string]struct {
+		Context context.Context
+		Want    map[string]any
+	}{
+		"No Span": {
+			Context: context.Background(),
+			Want:    map[string]any{"msg": "hello", "group": map[string]any{"key": "value"}},
+		},
+		"Span": {
+			Context: ctx,
+			Want: map[string]any{
+				"msg":   "hello",
+				"group": map[string]any{"key": "value", "trace_id": sc.TraceID().String(), "span_id": sc.SpanID().String()},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			h := routes.NewTraceLogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					// Exclude values which vary between runs
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			slog.New(h).WithGroup("group").Log(tt.Context, slog.LevelInfo, "hello", slog.String("key", "value"))
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "Log Record Decoding")
+
+			assert.Equal(t, tt.Want, got, "Log Record")
+		})
+	}
+}
+
+func TestRequestLog_ErrorSpan(t *testing.T) {
+	t.Parallel()
+
+	defer failOnPanic(t)
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	testLogger := NewTestLogger(t)
+	ctx := withTestContext(context.Background(), t)
+
+	var repo listRepo
+	listsAPI := routes.NewListAPI(&repo)
+
+	repo.OnList(ctx, "1", (*time.Duration)(nil), todo.Include{}).Return(nil, errors.New("query failure"))
+	defer mock.AssertExpectationsForObjects(t, &repo)
+
+	spanCtx, span := tp.Tracer("test").Start(ctx, "GET /api/v1/lists/:list_id")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lists/1", http.NoBody).WithContext(spanCtx)
+	rec := httptest.NewRecorder()
+
+	routes.Handler(listsAPI, testAuth{}, testLogger, nil).ServeHTTP(rec, req)
+	span.End()
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "HTTP Status Code")
+
+	// The server span of the request is a child of the span it was made within, and ends first
+	spans := sr.Ended()
+	require.Len(t, spans, 2, "Spans")
+
+	server := spans[0]
+	assert.Equal(t, span.SpanContext().SpanID(), server.Parent().SpanID(), "Server Span Parent")
+
+	// The status is set again from the response code once the request has been handled, which has no description
+	assert.Equal(t, sdktrace.Status{Code: codes.Error}, server.Status(), "Span Status")
+
+	events := server.Events()
+	require.Len(t, events, 1, "Span Events")
+	assert.Equal(t, "exception", events[0].Name, "Span Event Name")
+
+	attrs := map[string]string{}
+	for _, a := range events[0].Attributes {
+		attrs[string(a.Key)] = a.Value.Emit()
+	}
+
+	assert.Equal(t, "query failure", attrs["exception.message"], "Exception Message")
+	assert.Equal(t, "error_cause", attrs["log.attr"], "Log Attribute")
+}
+
+func TestRequestLog_ServerSpan(t *testing.T) {
+	// The tracer provider is global, so the test cannot be run in parallel
+
+	defer failOnPanic(t)
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	otel.SetTracerProvider(tp)
+
+	var buf bytes.Buffer
+	logger := slog.New(routes.NewTraceLogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{})))
+
+	ctx := withTestContext(context.Background(), t)
+
+	var repo listRepo
+	listsAPI := routes.NewListAPI(&repo)
+
+	repo.OnList(ctx, "1", (*time.Duration)(nil), todo.Include{}).Return(nil, errors.New("query failure"))
+	defer mock.AssertExpectationsForObjects(t, &repo)
+
+	// The request is made without a span, which the handler starts
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lists/1", http.NoBody).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	routes.Handler(listsAPI, testAuth{}, logger, nil).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "HTTP Status Code")
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1, "Spans")
+
+	server := spans[0]
+	assert.Equal(t, "GET /api/v1/lists/:list_id", server.Name(), "Span Name")
+	assert.Equal(t, codes.Error, server.Status().Code, "Span Status")
+
+	events := server.Events()
+	require.Len(t, events, 1, "Span Events")
+	assert.Equal(t, "exception", events[0].Name, "Span Event Name")
+
+	attrs := map[string]string{}
+	for _, a := range events[0].Attributes {
+		attrs[string(a.Key)] = a.Value.Emit()
+	}
+
+	assert.Equal(t, "query failure", attrs["exception.message"], "Exception Message")
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "Log Record Decoding")
+
+	assert.Equal(t, "HTTP Request Error", got["msg"], "Log Message")
+	assert.Equal(t, server.SpanContext().TraceID().String(), got["trace_id"], "Log Trace ID")
+	assert.Equal(t, server.SpanContext().SpanID().String(), got["span_id"], "Log Span ID")
+}
+
+func TestRequestLog(t *testing.T) {
+	t.Parallel()
+
+	defer failOnPanic(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{}))
+
+	var repo listRepo
+	h := routes.Handler(routes.NewListAPI(&repo), testAuth{}, logger, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping?verbose=true", http.NoBody)
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	req.Header.Set("X-Request-Id", "req-123")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "Log Record Decoding")
+
+	assert.IsType(t, float64(0), got["http.server.duration"], "Duration")
+
+	for _, k := range []string{slog.TimeKey, "http.server.duration"} {
+		delete(got, k)
+	}
+
+	want := map[string]any{
+		"level":                            "INFO",
+		"msg":                              "HTTP Request Success",
+		"http.request.method":              "GET",
+		"url.path":                         "/ping",
+		"url.query":                        "verbose=true",
+		"http.route":                       "/ping",
+		"http.response.status_code":        float64(200),
+		"http.response.body.size":          float64(4),
+		"http.request.body.size":           float64(0),
+		"network.protocol.name":            "http",
+		"network.protocol.version":         "1.1",
+		"client.address":                   "203.0.113.7",
+		"client.socket.address":            "192.0.2.1",
+		"user_agent.original":              "test-agent/1.0",
+		"http.request.header.x-request-id": "req-123",
+	}
+
+	assert.Equal(t, want, got, "Log Record")
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		Line *regexp.Regexp
+	}
+
+	testTable := map[string]struct {
+		Format routes.AccessLogFormat
+		Want   want
+	}{
+		"Common": {
+			Format: routes.CommonLogFormat,
+			Want:   want{Line: regexp.MustCompile(`^192\.0\.2\.1 - 1 \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /api/v1/lists/1\?include=archived HTTP/1\.1" 500 \d+\n$`)},
+		},
+		"Combined": {
+			Format: routes.CombinedLogFormat,
+			Want:   want{Line: regexp.MustCompile(`^192\.0\.2\.1 - 1 \[[^]]+\] "GET /api/v1/lists/1\?include=archived HTTP/1\.1" 500 \d+ "https://example\.com/" "test-agent/1\.0 \\"quoted\\""\n$`)},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			repo.OnList(ctx, "1", (*time.Duration)(nil), todo.Include{Archived: true}).Return(nil, errors.New("query failure"))
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			var buf bytes.Buffer
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, routes.NewAccessLog(&buf, tt.Format))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/lists/1?include=archived", http.NoBody).WithContext(ctx)
+			req.Header.Set("Referer", "https://example.com/")
+			req.Header.Set("User-Agent", `test-agent/1.0 "quoted"`)
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Regexp(t, tt.Want.Line, buf.String(), "Access Log Line")
+		})
+	}
+}
+
+func TestParseAccessLogFormat(t *testing.T) {
+	t.Parallel()
+
+	f, err := routes.ParseAccessLogFormat("combined")
+	require.NoError(t, err, "Parse Error")
+	assert.Equal(t, routes.CombinedLogFormat, f, "Format")
+
+	_, err = routes.ParseAccessLogFormat("json")
+	assert.EqualError(t, err, `unknown access log format "json", must be one of "common" or "combined"`, "Parse Error")
+}
@@ -0,0 +1,135 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// MembersBody included when retrieving the members of a TODO list.
+type MembersBody struct {
+	Members []todo.Member `json:"members"`
+}
+
+// MemberBody included when a TODO list has been shared with a member.
+type MemberBody struct {
+	Member *todo.Member `json:"member"`
+}
+
+// MemberRequest body received when sharing a TODO list with a user, or changing their role.
+type MemberRequest struct {
+	// User to share the list with, by their name.
+	User *string `json:"user"`
+	// Role of the user on the list: viewer, editor or owner.
+	Role *string `json:"role"`
+}
+
+// Members of a TODO list, being its owner and the users it has been shared with.
+func (l *ListsAPI) Members(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		members, err := l.repo.Members(r.Context(), listID)
+		if errResp := memberErrResponse(err); errResp != nil {
+			return nil, errResp
+		}
+
+		if members == nil {
+			// Ensure we get an empty array in the response, not `null`
+			members = []todo.Member{}
+		}
+
+		return &Response{Body: &MembersBody{Members: members}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// AddMember shares a TODO list with a user in the role, or changes the role of a user it is already shared with. Only
+// owners of the list may do so.
+func (l *ListsAPI) AddMember(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req MemberRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		if req.User == nil || strings.TrimSpace(*req.User) == "" {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"user" must not be blank`}
+		}
+
+		if req.Role == nil {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"role" is required`}
+		}
+
+		role, err := todo.ParseRole(strings.TrimSpace(*req.Role))
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: err.Error()}
+		}
+
+		member, err := l.repo.AddMember(r.Context(), listID, strings.TrimSpace(*req.User), role)
+		if errResp := memberErrResponse(err); errResp != nil {
+			return nil, errResp
+		}
+
+		return &Response{Body: &MemberBody{Member: member}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// RemoveMember of a TODO list named by the `user` query param, so that it is no longer shared with them. Owners may
+// remove anyone other than the owner of the list, while other members may only remove themselves.
+func (l *ListsAPI) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		user := strings.TrimSpace(r.URL.Query().Get("user"))
+		if user == "" {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"user" query param must not be blank`}
+		}
+
+		err := l.repo.RemoveMember(r.Context(), listID, user)
+		if errResp := memberErrResponse(err); errResp != nil {
+			return nil, errResp
+		}
+
+		return &Response{Status: http.StatusNoContent}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// memberErrResponse for a failure to manage the members of a list, which is nil when there was no failure.
+func memberErrResponse(err error) *ErrorResponse {
+	if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+		return &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+	}
+
+	if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+		return &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+	}
+
+	if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+		return &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+	}
+
+	if err != nil {
+		return &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+	}
+
+	return nil
+}
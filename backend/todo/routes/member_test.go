@@ -0,0 +1,226 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_Members(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		Method string
+		Route  string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"List - Empty List ID Path Param": {
+			Args:   args{Method: http.MethodGet, Route: "/api/v1/lists/%20/members"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"list_id\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"List - Not Found": {
+			Args: args{Method: http.MethodGet, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMembers(ctx, "1").Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"List - Query failure": {
+			Args: args{Method: http.MethodGet, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnMembers(ctx, "1").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"List": {
+			Args: args{Method: http.MethodGet, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					members := []todo.Member{
+						{User: todo.User{ID: "1", Name: "demo"}, Role: todo.RoleOwner},
+						{User: todo.User{ID: "7", Name: "bob"}, Role: todo.RoleViewer},
+					}
+					l.OnMembers(ctx, "1").Return(members, nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"members": [
+						{"user": {"id": "1", "name": "demo"}, "role": "owner"},
+						{"user": {"id": "7", "name": "bob"}, "role": "viewer"}
+					]
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Add - Blank User": {
+			Args:   args{Body: `{"user": " ", "role": "viewer"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"user\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Add - Missing Role": {
+			Args:   args{Body: `{"user": "bob"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"role\" is required"}`, Code: http.StatusBadRequest},
+		},
+		"Add - Unknown Role": {
+			Args:   args{Body: `{"user": "bob", "role": "admin"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "unknown role \"admin\", must be one of \"viewer\", \"editor\" or \"owner\""}`, Code: http.StatusBadRequest},
+		},
+		"Add - Forbidden": {
+			Args: args{Body: `{"user": "bob", "role": "viewer"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnAddMember(ctx, "1", "bob", todo.RoleViewer).Return(nil, todo.ForbiddenError(`owner role on list "1" is required`))
+				},
+			},
+			Want: want{Body: `{"error": "owner role on list \"1\" is required"}`, Code: http.StatusForbidden},
+		},
+		"Add - Owner of the List": {
+			Args: args{Body: `{"user": "demo", "role": "viewer"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnAddMember(ctx, "1", "demo", todo.RoleViewer).Return(nil, todo.InvalidArgumentError(`user "demo" already owns list "1"`))
+				},
+			},
+			Want: want{Body: `{"error": "user \"demo\" already owns list \"1\""}`, Code: http.StatusBadRequest},
+		},
+		"Add": {
+			Args: args{Body: `{"user": " bob ", "role": "editor"}`, Method: http.MethodPost, Route: "/api/v1/lists/1/members"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					member := &todo.Member{User: todo.User{ID: "7", Name: "bob"}, Role: todo.RoleEditor}
+					l.OnAddMember(ctx, "1", "bob", todo.RoleEditor).Return(member, nil)
+				},
+			},
+			Want: want{Body: `{"member": {"user": {"id": "7", "name": "bob"}, "role": "editor"}}`, Code: http.StatusOK},
+		},
+		"Remove - Blank User": {
+			Args:   args{Method: http.MethodDelete, Route: "/api/v1/lists/1/members?user=%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"user\" query param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Remove - Not a Member": {
+			Args: args{Method: http.MethodDelete, Route: "/api/v1/lists/1/members?user=bob"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRemoveMember(ctx, "1", "bob").Return(todo.NotFoundError(`user "bob" is not a member of list "1"`))
+				},
+			},
+			Want: want{Body: `{"error": "user \"bob\" is not a member of list \"1\""}`, Code: http.StatusNotFound},
+		},
+		"Remove": {
+			Args: args{Method: http.MethodDelete, Route: "/api/v1/lists/1/members?user=bob"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnRemoveMember(ctx, "1", "bob").Return(nil)
+				},
+			},
+			Want: want{Code: http.StatusNoContent},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(tt.Args.Method, tt.Args.Route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+
+			if tt.Want.Body == "" {
+				assert.Empty(t, body, "HTTP Response Body")
+				return
+			}
+
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) Members(ctx context.Context, listID string) ([]todo.Member, error) {
+	args := l.Called(testContext(ctx), listID)
+	return args.Get(0).([]todo.Member), args.Error(1)
+}
+
+// OnMembers provides a type-safe mock setup function, used instead of using 'On("Members, ...)'
+func (l *listRepo) OnMembers(ctx context.Context, listID string) *call2[[]todo.Member, error] {
+	m := l.On("Members", testContext(ctx), listID)
+	return &call2[[]todo.Member, error]{m: m}
+}
+
+func (l *listRepo) AddMember(ctx context.Context, listID, name string, role todo.Role) (*todo.Member, error) {
+	args := l.Called(testContext(ctx), listID, name, role)
+	return args.Get(0).(*todo.Member), args.Error(1)
+}
+
+// OnAddMember provides a type-safe mock setup function, used instead of using 'On("AddMember, ...)'
+func (l *listRepo) OnAddMember(ctx context.Context, listID, name string, role todo.Role) *call2[*todo.Member, error] {
+	m := l.On("AddMember", testContext(ctx), listID, name, role)
+	return &call2[*todo.Member, error]{m: m}
+}
+
+func (l *listRepo) RemoveMember(ctx context.Context, listID, name string) error {
+	args := l.Called(testContext(ctx), listID, name)
+	return args.Error(0)
+}
+
+// OnRemoveMember provides a type-safe mock setup function, used instead of using 'On("RemoveMember, ...)'
+func (l *listRepo) OnRemoveMember(ctx context.Context, listID, name string) *call1[error] {
+	m := l.On("RemoveMember", testContext(ctx), listID, name)
+	return &call1[error]{m: m}
+}
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// httpMetrics of the rate, errors and duration (RED) of requests, by their route, method and response status.
+type httpMetrics struct {
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// newHTTPMetrics from the global meter provider, so that they are exported once it has been set up.
+func newHTTPMetrics() *httpMetrics {
+	meter := otel.Meter("github.com/dackroyd/todo-list/backend/todo/routes")
+
+	// Errors only occur for invalid instrument names, where the instruments are still usable, so are handled globally
+	// rather than failing to serve requests
+	requests, err := meter.Int64Counter("http.server.requests", metric.WithDescription("Number of HTTP requests handled"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	failures, err := meter.Int64Counter("http.server.errors", metric.WithDescription("Number of HTTP requests which failed with a server error"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	// Named apart from the http.server.duration recorded by otelhttp, which does not have the route of the request
+	duration, err := meter.Float64Histogram("http.server.route.duration", metric.WithUnit("ms"), metric.WithDescription("Duration of handling HTTP requests"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &httpMetrics{requests: requests, errors: failures, duration: duration}
+}
+
+// requestMetrics recorded for each request to the route.
+func requestMetrics(h http.Handler, m *httpMetrics, method, route string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &CaptureWriter{w: w}
+
+		start := time.Now()
+		h.ServeHTTP(cw, r)
+		dur := time.Since(start)
+
+		sc := cw.StatusCode()
+
+		attrs := metric.WithAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.HTTPResponseStatusCode(sc),
+		)
+
+		ctx := r.Context()
+
+		m.requests.Add(ctx, 1, attrs)
+		m.duration.Record(ctx, float64(dur)/float64(time.Millisecond), attrs)
+
+		if sc >= http.StatusInternalServerError {
+			m.errors.Add(ctx, 1, attrs)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	defer failOnPanic(t)
+
+	setupMetrics(t)
+
+	testLogger := NewTestLogger(t)
+	ctx := withTestContext(context.Background(), t)
+
+	var repo listRepo
+	listsAPI := routes.NewListAPI(&repo)
+
+	repo.OnList(ctx, "1", (*time.Duration)(nil), todo.Include{}).Return(nil, errors.New("query failure"))
+	defer mock.AssertExpectationsForObjects(t, &repo)
+
+	h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+	for _, route := range []string{"/ping", "/api/v1/lists/1"} {
+		req := httptest.NewRequest(http.MethodGet, route, http.NoBody).WithContext(ctx)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	res := rec.Result()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode, "HTTP Status Code")
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err, "Body Read Error")
+
+	// Other tests make requests concurrently, so only the presence of the series can be relied upon, not their values
+	series := []string{
+		`http_server_requests_total{http_request_method="GET",http_response_status_code="200",http_route="/ping",`,
+		`http_server_requests_total{http_request_method="GET",http_response_status_code="500",http_route="/api/v1/lists/:list_id",`,
+		`http_server_errors_total{http_request_method="GET",http_response_status_code="500",http_route="/api/v1/lists/:list_id",`,
+		`http_server_route_duration_milliseconds_count{http_request_method="GET",http_response_status_code="200",http_route="/ping",`,
+		`http_server_route_duration_milliseconds_bucket{http_request_method="GET",http_response_status_code="500",http_route="/api/v1/lists/:list_id",`,
+	}
+
+	for _, s := range series {
+		assert.Regexp(t, regexp.MustCompile(`(?m)^`+regexp.QuoteMeta(s)+`[^}]*\} [1-9]`), string(body), "Metric Series")
+	}
+
+	assert.NotContains(t, string(body), `http_server_errors_total{http_request_method="GET",http_response_status_code="200",http_route="/ping",`, "Errors of Successful Requests")
+}
+
+var metricsOnce sync.Once
+
+// setupMetrics as the global meter provider, exported to the default Prometheus registry which is served by the
+// handler. This can only be done once, as the exporter cannot be registered again.
+func setupMetrics(t *testing.T) {
+	metricsOnce.Do(func() {
+		exp, err := otelprom.New()
+		require.NoError(t, err, "Metric Exporter Error")
+
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(exp)))
+	})
+}
@@ -3,10 +3,14 @@ package routes_test
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/exp/slog"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
 )
 
 // failOnPanic to prevent Testify assertion mismatch panics from bubbling out of a subtest, ensuring correct reporting
@@ -35,6 +39,13 @@ func (w *testLogWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// testAuth authenticates every request as the same user, for testing routes which require authentication.
+type testAuth struct{}
+
+func (testAuth) Authenticate(*http.Request) (*routes.Identity, error) {
+	return &routes.Identity{User: todo.User{ID: "1", Name: "demo"}, Scopes: todo.Scopes{todo.ScopeAdmin}}, nil
+}
+
 type testCtxKey string
 
 const testCtx testCtxKey = "test"
@@ -80,6 +91,17 @@ func testContext(ctx context.Context) contextFromTest {
 	return v.(contextFromTest)
 }
 
+// call1 value type safety for mocking calls which return a single value.
+type call1[T any] struct {
+	m *mock.Call
+}
+
+func (c *call1[T]) Return(t T) *call1[T] {
+	c.m.Return(t)
+
+	return c
+}
+
 // call2 value type safety for mocking calls which return two values.
 type call2[T any, U any] struct {
 	m *mock.Call
@@ -90,3 +112,14 @@ func (c *call2[T, U]) Return(t T, u U) *call2[T, U] {
 
 	return c
 }
+
+// call3 value type safety for mocking calls which return three values.
+type call3[T any, U any, V any] struct {
+	m *mock.Call
+}
+
+func (c *call3[T, U, V]) Return(t T, u U, v V) *call3[T, U, V] {
+	c.m.Return(t, u, v)
+
+	return c
+}
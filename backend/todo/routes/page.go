@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+const (
+	// defaultPageLimit of results, when the client does not specify a limit.
+	defaultPageLimit = 100
+	// maxPageLimit of results which may be requested for a single page.
+	maxPageLimit = 1000
+)
+
+// pageParams from the `limit` and `after` query params of the request.
+func pageParams(r *http.Request) (todo.Page, *ErrorResponse) {
+	q := r.URL.Query()
+
+	page := todo.Page{Limit: defaultPageLimit}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return todo.Page{}, &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf(`"limit" query param must be an integer between 1 and %d`, maxPageLimit)}
+		}
+
+		page.Limit = limit
+	}
+
+	if v := q.Get("after"); v != "" {
+		after, err := decodeCursor(v)
+		if err != nil {
+			return todo.Page{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"after" query param is not a valid cursor`}
+		}
+
+		page.After = after
+	}
+
+	return page, nil
+}
+
+// nextCursor for the page following the one being returned, which is empty when there are no further pages. A Link
+// header (RFC 8288) referencing the next page is also included in the response.
+func nextCursor(w http.ResponseWriter, r *http.Request, next string) string {
+	if next == "" {
+		return ""
+	}
+
+	cursor := encodeCursor(next)
+
+	u := *r.URL
+	q := u.Query()
+	q.Set("after", cursor)
+	u.RawQuery = q.Encode()
+
+	w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+
+	return cursor
+}
+
+// encodeCursor so that clients treat it as opaque, rather than depending upon the key it contains.
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -4,30 +4,72 @@ import (
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/exp/slog"
 )
 
-func Handler(lists *ListsAPI, logger *slog.Logger) http.Handler {
-	m := mux{logger: logger, router: httprouter.New()}
+// Handler for the API, where the user making each request is authenticated, except for pinging the server and scraping
+// its metrics. Metrics are served from the default Prometheus registry. Requests are also written to the access log,
+// unless it is nil.
+func Handler(lists *ListsAPI, auth Authenticator, logger *slog.Logger, accessLog *AccessLog) http.Handler {
+	m := mux{accessLog: accessLog, auth: auth, logger: logger, metrics: newHTTPMetrics(), router: httprouter.New()}
 
 	m.handlerFunc(http.MethodGet, "/api/v1/lists", lists.Lists)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists", lists.CreateList)
 	m.handlerFunc(http.MethodGet, "/api/v1/lists/:list_id", lists.List)
+	m.handlerFunc(http.MethodPut, "/api/v1/lists/:list_id", lists.UpdateList)
+	m.handlerFunc(http.MethodPatch, "/api/v1/lists/:list_id", lists.UpdateList)
+	m.handlerFunc(http.MethodDelete, "/api/v1/lists/:list_id", lists.DeleteList)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/archive", lists.ArchiveList)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/restore", lists.RestoreList)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/move-to", lists.TransferItems)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/template", lists.SaveTemplate)
+	m.handlerFunc(http.MethodGet, "/api/v1/lists/:list_id/history", lists.ListHistory)
+	m.handlerFunc(http.MethodGet, "/api/v1/lists/:list_id/members", lists.Members)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/members", lists.AddMember)
+	m.handlerFunc(http.MethodDelete, "/api/v1/lists/:list_id/members", lists.RemoveMember)
 	m.handlerFunc(http.MethodGet, "/api/v1/lists/:list_id/items", lists.Items)
-	m.handlerFunc(http.MethodGet, "/ping", Ping)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items", lists.CreateItem)
+	m.handlerFunc(http.MethodPatch, "/api/v1/lists/:list_id/items/:item_id", lists.UpdateItem)
+	m.handlerFunc(http.MethodDelete, "/api/v1/lists/:list_id/items/:item_id", lists.DeleteItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/archive", lists.ArchiveItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/restore", lists.RestoreItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/complete", lists.CompleteItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/reopen", lists.ReopenItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/move", lists.MoveItem)
+	m.handlerFunc(http.MethodPost, "/api/v1/lists/:list_id/items/:item_id/move-to", lists.TransferItem)
+	m.handlerFunc(http.MethodGet, "/api/v1/lists/:list_id/items/:item_id/history", lists.ItemHistory)
+	m.handlerFunc(http.MethodPut, "/api/v1/lists/:list_id/items/:item_id/tags/:tag", lists.TagItem)
+	m.handlerFunc(http.MethodDelete, "/api/v1/lists/:list_id/items/:item_id/tags/:tag", lists.UntagItem)
+	m.handlerFunc(http.MethodGet, "/api/v1/search", lists.Search)
+	m.handlerFunc(http.MethodGet, "/api/v1/tags", lists.Tags)
+	m.handlerFunc(http.MethodGet, "/api/v1/templates", lists.Templates)
+	m.handlerFunc(http.MethodGet, "/api/v1/templates/:template_id", lists.Template)
+	m.handlerFunc(http.MethodPost, "/api/v1/templates/:template_id/instantiate", lists.InstantiateTemplate)
+	m.publicHandler(http.MethodGet, "/ping", http.HandlerFunc(Ping))
+	m.publicHandler(http.MethodGet, "/metrics", promhttp.Handler())
 
 	return m.router
 }
 
 type mux struct {
-	logger *slog.Logger
-	router *httprouter.Router
+	accessLog *AccessLog
+	auth      Authenticator
+	logger    *slog.Logger
+	metrics   *httpMetrics
+	router    *httprouter.Router
 }
 
 func (m *mux) handler(method, route string, h http.Handler) {
-	w := requestLog(h, m.logger, route)
-	// Instrument HTTP Handlers: Uncomment the line below
-	//w = otelhttp.NewHandler(w, method+" "+route)
+	m.publicHandler(method, route, authenticate(h, m.auth, requiredScope(method)))
+}
+
+// publicHandler for a route which may be requested without authenticating.
+func (m *mux) publicHandler(method, route string, h http.Handler) {
+	w := requestLog(h, m.logger, m.accessLog, route)
+	w = requestMetrics(w, m.metrics, method, route)
+	w = otelhttp.NewHandler(w, method+" "+route)
 
 	m.router.Handler(method, route, w)
 }
@@ -35,6 +77,3 @@ func (m *mux) handler(method, route string, h http.Handler) {
 func (m *mux) handlerFunc(method, route string, h http.HandlerFunc) {
 	m.handler(method, route, h)
 }
-
-// HACK: forcing the import to be kept, so that enabling instrumentation only requires uncommenting in 'handler'
-var _ = otelhttp.NewHandler
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// maxSearchQueryLen in bytes which may be searched for.
+const maxSearchQueryLen = 256
+
+// SearchBody included when searching TODO lists and items.
+type SearchBody struct {
+	Results []todo.SearchResult `json:"results"`
+	// Next is the cursor to retrieve the following page of results, when there is one.
+	Next string `json:"next,omitempty"`
+}
+
+// Search TODO lists and items by their description, with the best matches first. Snippets of the matches are escaped
+// HTML, which may be rendered as is.
+func (l *ListsAPI) Search(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		switch {
+		case query == "":
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"q" query param must not be blank`}
+		case len(query) > maxSearchQueryLen:
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"q" query param must not exceed 256 bytes`}
+		}
+
+		page, errResp := pageParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		results, next, err := l.repo.Search(r.Context(), query, page)
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		if results == nil {
+			// Ensure we get an empty array in the response, not `null`
+			results = []todo.SearchResult{}
+		}
+
+		return &Response{Body: &SearchBody{Results: results, Next: nextCursor(w, r, next)}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
@@ -0,0 +1,177 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_Search(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Query string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Missing Query": {
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"q\" query param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Query": {
+			Args:   args{Query: "q=%20%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"q\" query param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Query too Long": {
+			Args:   args{Query: "q=" + strings.Repeat("a", 257)},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"q\" query param must not exceed 256 bytes"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Limit": {
+			Args:   args{Query: "q=washing&limit=1001"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"limit\" query param must be an integer between 1 and 1000"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Page Key": {
+			Args: args{Query: "q=washing&after=Mg"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSearch(ctx, "washing", todo.Page{After: "2", Limit: 100}).
+						Return(nil, "", todo.InvalidArgumentError("page key is not valid for search"))
+				},
+			},
+			Want: want{Body: `{"error": "page key is not valid for search"}`, Code: http.StatusBadRequest},
+		},
+		"Search failure": {
+			Args: args{Query: "q=washing"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSearch(ctx, "washing", todo.Page{Limit: 100}).Return(nil, "", errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"No Results": {
+			Args: args{Query: "q=washing"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSearch(ctx, "washing", todo.Page{Limit: 100}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"results": []}`, Code: http.StatusOK},
+		},
+		"Lists and Items": {
+			Args: args{Query: "q=%20golang%20meetup%20"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					results := []todo.SearchResult{
+						{Kind: todo.SearchKindList, ID: "2", ListID: "2", Description: "Golang-Syd Meetup", Snippet: "<mark>Golang</mark>-Syd <mark>Meetup</mark>", Rank: 0.099},
+						{Kind: todo.SearchKindItem, ID: "6", ListID: "2", Description: "Attend Golang Meetup", Snippet: "Attend <mark>Golang</mark> <mark>Meetup</mark>", Rank: 0.099},
+					}
+					l.OnSearch(ctx, "golang meetup", todo.Page{Limit: 100}).Return(results, "", nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"results": [
+						{"kind": "list", "id": "2", "listId": "2", "description": "Golang-Syd Meetup", "snippet": "<mark>Golang</mark>-Syd <mark>Meetup</mark>", "rank": 0.099},
+						{"kind": "item", "id": "6", "listId": "2", "description": "Attend Golang Meetup", "snippet": "Attend <mark>Golang</mark> <mark>Meetup</mark>", "rank": 0.099}
+					]
+				}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Page with Next": {
+			Args: args{Query: "q=washing&limit=1"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					results := []todo.SearchResult{
+						{Kind: todo.SearchKindItem, ID: "1", ListID: "1", Description: "Washing", Snippet: "<mark>Washing</mark>", Rank: 0.06},
+					}
+					l.OnSearch(ctx, "washing", todo.Page{Limit: 1}).Return(results, `{"rank":0.06,"kind":"item","id":"1"}`, nil)
+				},
+			},
+			Want: want{
+				Body: `{
+					"results": [
+						{"kind": "item", "id": "1", "listId": "1", "description": "Washing", "snippet": "<mark>Washing</mark>", "rank": 0.06}
+					],
+					"next": "eyJyYW5rIjowLjA2LCJraW5kIjoiaXRlbSIsImlkIjoiMSJ9"
+				}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/search?after=eyJyYW5rIjowLjA2LCJraW5kIjoiaXRlbSIsImlkIjoiMSJ9&limit=1&q=washing>; rel="next"`}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+tt.Args.Query, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+			assert.Equal(t, tt.Want.Headers.Values("Link"), res.Header.Values("Link"), "HTTP Link Header")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) Search(ctx context.Context, query string, page todo.Page) ([]todo.SearchResult, string, error) {
+	args := l.Called(testContext(ctx), query, page)
+	return args.Get(0).([]todo.SearchResult), args.String(1), args.Error(2)
+}
+
+// OnSearch provides a type-safe mock setup function, used instead of using 'On("Search, ...)'
+func (l *listRepo) OnSearch(ctx context.Context, query string, page todo.Page) *call3[[]todo.SearchResult, string, error] {
+	m := l.On("Search", testContext(ctx), query, page)
+	return &call3[[]todo.SearchResult, string, error]{m: m}
+}
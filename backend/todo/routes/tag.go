@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// maxTagLen in bytes of the name of a tag.
+const maxTagLen = 64
+
+// TagsBody included when retrieving tags.
+type TagsBody struct {
+	Tags []todo.Tag `json:"tags"`
+	// Next is the cursor to retrieve the following page of tags, when there is one.
+	Next string `json:"next,omitempty"`
+}
+
+// Tags applied to TODO items, with the number of items having each.
+func (l *ListsAPI) Tags(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		page, errResp := pageParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		tags, next, err := l.repo.Tags(r.Context(), page)
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		if tags == nil {
+			// Ensure we get an empty array in the response, not `null`
+			tags = []todo.Tag{}
+		}
+
+		return &Response{Body: &TagsBody{Tags: tags, Next: nextCursor(w, r, next)}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// TagItem with a tag, such as "@home".
+func (l *ListsAPI) TagItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemTagAction(l.repo.TagItem))(w, r)
+}
+
+// UntagItem by removing a tag from it.
+func (l *ListsAPI) UntagItem(w http.ResponseWriter, r *http.Request) {
+	handleRequest(l.itemTagAction(l.repo.UntagItem))(w, r)
+}
+
+// itemTagAction which changes a tag of a TODO item, identified by the `tag` path param.
+func (l *ListsAPI) itemTagAction(action func(ctx context.Context, listID, itemID, tag string) (*todo.Item, error)) func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+	return func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		tag, errResp := pathParam(r, "tag")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		tag, ok := tagName(tag)
+		if !ok {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf(`"tag" path param must not exceed %d bytes`, maxTagLen)}
+		}
+
+		item, err := action(r.Context(), listID, itemID, tag)
+
+		return itemResponse(item, err)
+	}
+}
+
+// tagParams from the `tag` and `tag_match` query params of the request. Tags may be repeated to filter on several.
+func tagParams(r *http.Request) ([]string, todo.TagMatch, *ErrorResponse) {
+	q := r.URL.Query()
+
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, v := range q["tag"] {
+		tag, ok := tagName(v)
+		if !ok || tag == "" {
+			return nil, "", &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf(`"tag" query param must not be blank, or exceed %d bytes`, maxTagLen)}
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	switch m := todo.TagMatch(q.Get("tag_match")); {
+	case m != "" && len(tags) == 0:
+		return nil, "", &ErrorResponse{Status: http.StatusBadRequest, Error: `"tag_match" query param requires "tag"`}
+	case m == "", m == todo.TagMatchAny, m == todo.TagMatchAll:
+		return tags, m, nil
+	default:
+		return nil, "", &ErrorResponse{Status: http.StatusBadRequest, Error: `"tag_match" query param must be one of: any, all`}
+	}
+}
+
+// tagName normalised, so that tags differing only by case or surrounding whitespace are the same. Not ok when the name
+// is too long.
+func tagName(v string) (string, bool) {
+	tag := strings.ToLower(strings.TrimSpace(v))
+
+	return tag, len(tag) <= maxTagLen
+}
@@ -0,0 +1,263 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_Tags(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Query string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTags(ctx, todo.Page{Limit: 100}).Return(nil, "", errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"No Tags": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTags(ctx, todo.Page{Limit: 100}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"tags": []}`, Code: http.StatusOK},
+		},
+		"Invalid Limit": {
+			Args:   args{Query: "limit=abc"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"limit\" query param must be an integer between 1 and 1000"}`, Code: http.StatusBadRequest},
+		},
+		"Page with Next": {
+			Args: args{Query: "limit=2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					tags := []todo.Tag{{Name: "@errands", Count: 1}, {Name: "@home", Count: 12}}
+					l.OnTags(ctx, todo.Page{Limit: 2}).Return(tags, "@home", nil)
+				},
+			},
+			Want: want{
+				Body:    `{"tags": [{"name": "@errands", "count": 1}, {"name": "@home", "count": 12}], "next": "QGhvbWU"}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/tags?after=QGhvbWU&limit=2>; rel="next"`}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tags?"+tt.Args.Query, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+			assert.Equal(t, tt.Want.Headers.Values("Link"), res.Header.Values("Link"), "HTTP Link Header")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_ItemTags(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ItemID string
+		ListID string
+		Method string
+		Tag    string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Tag - Too Long": {
+			Args:   args{Method: http.MethodPut, ItemID: "2", ListID: "1", Tag: strings.Repeat("a", 65)},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tag\" path param must not exceed 64 bytes"}`, Code: http.StatusBadRequest},
+		},
+		"Tag - Blank": {
+			Args:   args{Method: http.MethodPut, ItemID: "2", ListID: "1", Tag: "%20"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"tag\" path param must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Tag - Not Found": {
+			Args: args{Method: http.MethodPut, ItemID: "9", ListID: "1", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTagItem(ctx, "1", "9", "@home").Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Tag - Query failure": {
+			Args: args{Method: http.MethodPut, ItemID: "2", ListID: "1", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTagItem(ctx, "1", "2", "@home").Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Tag - Normalised": {
+			Args: args{Method: http.MethodPut, ItemID: "2", ListID: "1", Tag: "%20@Home%20"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTagItem(ctx, "1", "2", "@home").Return(&todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{"@home"}}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "2", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": ["@home"], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+		"Untag - Not Found": {
+			Args: args{Method: http.MethodDelete, ItemID: "9", ListID: "1", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUntagItem(ctx, "1", "9", "@home").Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Untag": {
+			Args: args{Method: http.MethodDelete, ItemID: "2", ListID: "1", Tag: "@home"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnUntagItem(ctx, "1", "2", "@home").Return(&todo.Item{ID: "2", Description: "Washing", Tags: todo.Tags{"@weekend"}}, nil)
+				},
+			},
+			Want: want{Body: `{"item": {"id": "2", "description": "Washing", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": ["@weekend"], "autoComplete": false, "progress": null}}`, Code: http.StatusOK},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s/tags/%s", tt.Args.ListID, tt.Args.ItemID, tt.Args.Tag)
+			req := httptest.NewRequest(tt.Args.Method, route, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) Tags(ctx context.Context, page todo.Page) ([]todo.Tag, string, error) {
+	args := l.Called(testContext(ctx), page)
+	return args.Get(0).([]todo.Tag), args.String(1), args.Error(2)
+}
+
+// OnTags provides a type-safe mock setup function, used instead of using 'On("Tags, ...)'
+func (l *listRepo) OnTags(ctx context.Context, page todo.Page) *call3[[]todo.Tag, string, error] {
+	m := l.On("Tags", testContext(ctx), page)
+	return &call3[[]todo.Tag, string, error]{m: m}
+}
+
+func (l *listRepo) TagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID, tag)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnTagItem provides a type-safe mock setup function, used instead of using 'On("TagItem, ...)'
+func (l *listRepo) OnTagItem(ctx context.Context, listID, itemID, tag string) *call2[*todo.Item, error] {
+	m := l.On("TagItem", testContext(ctx), listID, itemID, tag)
+	return &call2[*todo.Item, error]{m: m}
+}
+
+func (l *listRepo) UntagItem(ctx context.Context, listID, itemID, tag string) (*todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, itemID, tag)
+	return args.Get(0).(*todo.Item), args.Error(1)
+}
+
+// OnUntagItem provides a type-safe mock setup function, used instead of using 'On("UntagItem, ...)'
+func (l *listRepo) OnUntagItem(ctx context.Context, listID, itemID, tag string) *call2[*todo.Item, error] {
+	m := l.On("UntagItem", testContext(ctx), listID, itemID, tag)
+	return &call2[*todo.Item, error]{m: m}
+}
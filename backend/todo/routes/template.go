@@ -0,0 +1,219 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// TemplatesBody included when retrieving templates of TODO lists.
+type TemplatesBody struct {
+	Templates []todo.Template `json:"templates"`
+	// Next is the cursor to retrieve the following page of templates, when there is one.
+	Next string `json:"next,omitempty"`
+}
+
+// TemplateBody included when retrieving or saving a template of a TODO list.
+type TemplateBody struct {
+	Template *todo.Template      `json:"template"`
+	Items    []todo.TemplateItem `json:"items"`
+}
+
+// InstantiatedListBody included when a TODO list has been created from a template.
+type InstantiatedListBody struct {
+	List  *todo.List  `json:"list"`
+	Items []todo.Item `json:"items"`
+}
+
+// SaveTemplateRequest body received when saving a TODO list as a template.
+type SaveTemplateRequest struct {
+	// Description of the template, which defaults to that of the list.
+	Description *string `json:"description"`
+	// Anchor date which due dates are saved relative to, such as "2023-07-01". Defaults to the date of the earliest
+	// due item.
+	Anchor *string `json:"anchor"`
+}
+
+// InstantiateRequest body received when creating a TODO list from a template.
+type InstantiateRequest struct {
+	// Description of the list, which defaults to that of the template.
+	Description *string `json:"description"`
+	// Anchor date which due dates are resolved from, such as "2023-07-01".
+	Anchor *string `json:"anchor"`
+}
+
+// Templates of TODO lists.
+func (l *ListsAPI) Templates(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		page, errResp := pageParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		templates, next, err := l.repo.Templates(r.Context(), page)
+		if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		if templates == nil {
+			// Ensure we get an empty array in the response, not `null`
+			templates = []todo.Template{}
+		}
+
+		return &Response{Body: &TemplatesBody{Templates: templates, Next: nextCursor(w, r, next)}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// Template of a TODO list, along with its items.
+func (l *ListsAPI) Template(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		templateID, errResp := pathParam(r, "template_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		template, items, err := l.repo.Template(r.Context(), templateID)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		return &Response{Body: templateBody(template, items)}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// SaveTemplate of a TODO list, so that lists having the same items can be created from it.
+func (l *ListsAPI) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req SaveTemplateRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		var save todo.TemplateSave
+
+		if req.Description != nil {
+			if save.Description = strings.TrimSpace(*req.Description); save.Description == "" {
+				return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+			}
+		}
+
+		if req.Anchor != nil {
+			anchor, errResp := anchorDate(*req.Anchor)
+			if errResp != nil {
+				return nil, errResp
+			}
+
+			save.Anchor = &anchor
+		}
+
+		template, items, err := l.repo.SaveTemplate(r.Context(), listID, save)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+			return nil, &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		w.Header().Set("Location", "/api/v1/templates/"+template.ID)
+
+		return &Response{Status: http.StatusCreated, Body: templateBody(template, items)}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// InstantiateTemplate as a new TODO list, with due dates resolved from an anchor date.
+func (l *ListsAPI) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		templateID, errResp := pathParam(r, "template_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req InstantiateRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		if req.Anchor == nil {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"anchor" must be provided`}
+		}
+
+		anchor, errResp := anchorDate(*req.Anchor)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		instance := todo.TemplateInstance{Anchor: anchor}
+
+		if req.Description != nil {
+			if instance.Description = strings.TrimSpace(*req.Description); instance.Description == "" {
+				return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"description" must not be blank`}
+			}
+		}
+
+		list, items, err := l.repo.InstantiateTemplate(r.Context(), templateID, instance)
+		if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+			return nil, &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+		}
+
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		if items == nil {
+			// Ensure we get an empty array in the response, not `null`
+			items = []todo.Item{}
+		}
+
+		w.Header().Set("Location", "/api/v1/lists/"+list.ID)
+
+		return &Response{Status: http.StatusCreated, Body: &InstantiatedListBody{List: list, Items: items}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+func templateBody(template *todo.Template, items []todo.TemplateItem) *TemplateBody {
+	if items == nil {
+		// Ensure we get an empty array in the response, not `null`
+		items = []todo.TemplateItem{}
+	}
+
+	return &TemplateBody{Template: template, Items: items}
+}
+
+// anchorDate from a date such as "2023-07-01".
+func anchorDate(v string) (time.Time, *ErrorResponse) {
+	anchor, err := time.Parse(time.DateOnly, strings.TrimSpace(v))
+	if err != nil {
+		return time.Time{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"anchor" must be a date, such as 2023-07-01`}
+	}
+
+	return anchor, nil
+}
@@ -0,0 +1,508 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_Templates(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Query string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTemplates(ctx, todo.Page{Limit: 100}).Return(nil, "", errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"No Templates": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTemplates(ctx, todo.Page{Limit: 100}).Return(nil, "", nil)
+				},
+			},
+			Want: want{Body: `{"templates": []}`, Code: http.StatusOK},
+		},
+		"Page with Next": {
+			Args: args{Query: "limit=2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					templates := []todo.Template{{ID: "1", Description: "Moving House"}, {ID: "2", Description: "Holiday", DueHorizon: ptr(todo.Duration(72 * time.Hour))}}
+					l.OnTemplates(ctx, todo.Page{Limit: 2}).Return(templates, "2", nil)
+				},
+			},
+			Want: want{
+				Body:    `{"templates": [{"id": "1", "description": "Moving House"}, {"id": "2", "description": "Holiday", "dueHorizon": "72h0m0s"}], "next": "Mg"}`,
+				Code:    http.StatusOK,
+				Headers: http.Header{"Link": []string{`</api/v1/templates?after=Mg&limit=2>; rel="next"`}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/templates?"+tt.Args.Query, http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_Template(t *testing.T) {
+	t.Parallel()
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	testTable := map[string]struct {
+		Fields fields
+		Want   want
+	}{
+		"Not Found": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTemplate(ctx, "4").Return(nil, nil, todo.NotFoundError("template not found"))
+				},
+			},
+			Want: want{Body: `{"error": "template not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTemplate(ctx, "4").Return(nil, nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"No Items": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTemplate(ctx, "4").Return(&todo.Template{ID: "4", Description: "Moving House"}, nil, nil)
+				},
+			},
+			Want: want{Body: `{"template": {"id": "4", "description": "Moving House"}, "items": []}`, Code: http.StatusOK},
+		},
+		"Found": {
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					items := []todo.TemplateItem{
+						{ID: "7", Description: "Book removalist", DueOffset: &todo.DueOffset{Days: -14, TimeOfDay: 9 * time.Hour}, Priority: todo.PriorityHigh, Position: 1024},
+						{ID: "8", ParentID: ptr("7"), Description: "Get quotes", Tags: todo.Tags{"@phone"}, Position: 2048},
+					}
+
+					l.OnTemplate(ctx, "4").Return(&todo.Template{ID: "4", Description: "Moving House"}, items, nil)
+				},
+			},
+			Want: want{
+				Body: `{"template": {"id": "4", "description": "Moving House"}, "items": [
+					{"id": "7", "parentId": null, "description": "Book removalist", "dueOffset": "-14 days 09:00", "priority": "high", "recurrence": null, "tags": [], "autoComplete": false},
+					{"id": "8", "parentId": "7", "description": "Get quotes", "dueOffset": null, "priority": "normal", "recurrence": null, "tags": ["@phone"], "autoComplete": false}
+				]}`,
+				Code: http.StatusOK,
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/templates/4", http.NoBody).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_SaveTemplate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	items := []todo.TemplateItem{{ID: "10", Description: "Pack", DueOffset: &todo.DueOffset{TimeOfDay: 8 * time.Hour}, Position: 1024}}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Blank Description": {
+			Args:   args{Body: `{"description": " "}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Anchor": {
+			Args:   args{Body: `{"anchor": "1 July"}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"anchor\" must be a date, such as 2023-07-01"}`, Code: http.StatusBadRequest},
+		},
+		"List Not Found": {
+			Args: args{Body: `{}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSaveTemplate(ctx, "2", todo.TemplateSave{}).Return(nil, nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSaveTemplate(ctx, "2", todo.TemplateSave{}).Return(nil, nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Saved": {
+			Args: args{Body: `{}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnSaveTemplate(ctx, "2", todo.TemplateSave{}).Return(&todo.Template{ID: "5", Description: "Holiday"}, items, nil)
+				},
+			},
+			Want: want{
+				Body: `{"template": {"id": "5", "description": "Holiday"}, "items": [
+					{"id": "10", "parentId": null, "description": "Pack", "dueOffset": "+0 days 08:00", "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false}
+				]}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/templates/5"}},
+			},
+		},
+		"Saved with Description and Anchor": {
+			Args: args{Body: `{"description": " Beach Holiday ", "anchor": "2023-07-01"}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					save := todo.TemplateSave{Description: "Beach Holiday", Anchor: ptr(time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC))}
+					l.OnSaveTemplate(ctx, "2", save).Return(&todo.Template{ID: "5", Description: "Beach Holiday"}, nil, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"template": {"id": "5", "description": "Beach Holiday"}, "items": []}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/templates/5"}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/template", tt.Args.ListID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_InstantiateTemplate(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body       string
+		TemplateID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	anchor := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	packDue := time.Date(2023, time.August, 3, 17, 30, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Missing Anchor": {
+			Args:   args{Body: `{}`, TemplateID: "4"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"anchor\" must be provided"}`, Code: http.StatusBadRequest},
+		},
+		"Invalid Anchor": {
+			Args:   args{Body: `{"anchor": "2023-02-30"}`, TemplateID: "4"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"anchor\" must be a date, such as 2023-07-01"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Description": {
+			Args:   args{Body: `{"anchor": "2023-08-01", "description": ""}`, TemplateID: "4"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"description\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Template Not Found": {
+			Args: args{Body: `{"anchor": "2023-08-01"}`, TemplateID: "4"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnInstantiateTemplate(ctx, "4", todo.TemplateInstance{Anchor: anchor}).Return(nil, nil, todo.NotFoundError("template not found"))
+				},
+			},
+			Want: want{Body: `{"error": "template not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"anchor": "2023-08-01"}`, TemplateID: "4"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnInstantiateTemplate(ctx, "4", todo.TemplateInstance{Anchor: anchor}).Return(nil, nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Created": {
+			Args: args{Body: `{"anchor": "2023-08-01", "description": "Moving to Melbourne"}`, TemplateID: "4"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					items := []todo.Item{{ID: "22", Description: "Pack", Due: &packDue, Position: 3072}}
+
+					l.OnInstantiateTemplate(ctx, "4", todo.TemplateInstance{Anchor: anchor, Description: "Moving to Melbourne"}).
+						Return(&todo.List{ID: "6", Description: "Moving to Melbourne"}, items, nil)
+				},
+			},
+			Want: want{
+				Body: `{"list": {"id": "6", "description": "Moving to Melbourne"}, "items": [
+					{"id": "22", "description": "Pack", "due": "2023-08-03T17:30:00Z", "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
+				]}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/6"}},
+			},
+		},
+		"Created without Items": {
+			Args: args{Body: `{"anchor": "2023-08-01"}`, TemplateID: "4"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnInstantiateTemplate(ctx, "4", todo.TemplateInstance{Anchor: anchor}).Return(&todo.List{ID: "6", Description: "Moving House"}, nil, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"list": {"id": "6", "description": "Moving House"}, "items": []}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/6"}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/templates/%s/instantiate", tt.Args.TemplateID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) Templates(ctx context.Context, page todo.Page) ([]todo.Template, string, error) {
+	args := l.Called(testContext(ctx), page)
+	return args.Get(0).([]todo.Template), args.String(1), args.Error(2)
+}
+
+// OnTemplates provides a type-safe mock setup function, used instead of using 'On("Templates, ...)'
+func (l *listRepo) OnTemplates(ctx context.Context, page todo.Page) *call3[[]todo.Template, string, error] {
+	m := l.On("Templates", testContext(ctx), page)
+	return &call3[[]todo.Template, string, error]{m: m}
+}
+
+func (l *listRepo) Template(ctx context.Context, templateID string) (*todo.Template, []todo.TemplateItem, error) {
+	args := l.Called(testContext(ctx), templateID)
+	return args.Get(0).(*todo.Template), args.Get(1).([]todo.TemplateItem), args.Error(2)
+}
+
+// OnTemplate provides a type-safe mock setup function, used instead of using 'On("Template, ...)'
+func (l *listRepo) OnTemplate(ctx context.Context, templateID string) *call3[*todo.Template, []todo.TemplateItem, error] {
+	m := l.On("Template", testContext(ctx), templateID)
+	return &call3[*todo.Template, []todo.TemplateItem, error]{m: m}
+}
+
+func (l *listRepo) SaveTemplate(ctx context.Context, listID string, save todo.TemplateSave) (*todo.Template, []todo.TemplateItem, error) {
+	args := l.Called(testContext(ctx), listID, save)
+	return args.Get(0).(*todo.Template), args.Get(1).([]todo.TemplateItem), args.Error(2)
+}
+
+// OnSaveTemplate provides a type-safe mock setup function, used instead of using 'On("SaveTemplate, ...)'
+func (l *listRepo) OnSaveTemplate(ctx context.Context, listID string, save todo.TemplateSave) *call3[*todo.Template, []todo.TemplateItem, error] {
+	m := l.On("SaveTemplate", testContext(ctx), listID, save)
+	return &call3[*todo.Template, []todo.TemplateItem, error]{m: m}
+}
+
+func (l *listRepo) InstantiateTemplate(ctx context.Context, templateID string, instance todo.TemplateInstance) (*todo.List, []todo.Item, error) {
+	args := l.Called(testContext(ctx), templateID, instance)
+	return args.Get(0).(*todo.List), args.Get(1).([]todo.Item), args.Error(2)
+}
+
+// OnInstantiateTemplate provides a type-safe mock setup function, used instead of using 'On("InstantiateTemplate, ...)'
+func (l *listRepo) OnInstantiateTemplate(ctx context.Context, templateID string, instance todo.TemplateInstance) *call3[*todo.List, []todo.Item, error] {
+	m := l.On("InstantiateTemplate", testContext(ctx), templateID, instance)
+	return &call3[*todo.List, []todo.Item, error]{m: m}
+}
@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+// maxTransferItems which may be transferred by a single request.
+const maxTransferItems = 100
+
+// TransferRequest body received when moving or copying a TODO item to another list.
+type TransferRequest struct {
+	// ListID of the list to transfer the item to.
+	ListID *string `json:"listId"`
+	// Copy the item rather than moving it, leaving the original where it is.
+	Copy bool `json:"copy"`
+}
+
+// BulkTransferRequest body received when moving or copying many TODO items to another list.
+type BulkTransferRequest struct {
+	TransferRequest
+	// ItemIDs of the items to transfer.
+	ItemIDs []string `json:"itemIds"`
+}
+
+// TransferItem of a TODO list to another list, either moving it or copying it along with its subtasks. Copies are
+// created as new items, whose location is provided.
+func (l *ListsAPI) TransferItem(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, itemID, errResp := itemPathParams(r)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req TransferRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		transfer, errResp := req.transfer([]string{itemID})
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		items, err := l.repo.TransferItems(r.Context(), listID, transfer)
+		if errResp := transferErrResponse(err); errResp != nil {
+			return nil, errResp
+		}
+
+		if len(items) != 1 {
+			err := fmt.Errorf("transferring item %q of todo list %q resulted in %d items", itemID, listID, len(items))
+			return nil, &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+		}
+
+		if !transfer.Copy {
+			return &Response{Body: &ItemBody{Item: &items[0]}}, nil
+		}
+
+		w.Header().Set("Location", "/api/v1/lists/"+transfer.ListID+"/items/"+items[0].ID)
+
+		return &Response{Status: http.StatusCreated, Body: &ItemBody{Item: &items[0]}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+// TransferItems of a TODO list to another list, either moving or copying them along with their subtasks. Either all
+// the items are transferred, or none are.
+func (l *ListsAPI) TransferItems(w http.ResponseWriter, r *http.Request) {
+	h := func(w http.ResponseWriter, r *http.Request) (*Response, *ErrorResponse) {
+		listID, errResp := pathParam(r, "list_id")
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		var req BulkTransferRequest
+		if errResp := decodeBody(r, &req); errResp != nil {
+			return nil, errResp
+		}
+
+		if len(req.ItemIDs) == 0 {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"itemIds" must not be empty`}
+		}
+
+		if len(req.ItemIDs) > maxTransferItems {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: fmt.Sprintf(`"itemIds" must not contain more than %d items`, maxTransferItems)}
+		}
+
+		seen := make(map[string]bool, len(req.ItemIDs))
+		itemIDs := make([]string, 0, len(req.ItemIDs))
+
+		for _, id := range req.ItemIDs {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: `"itemIds" must not contain blank IDs`}
+			}
+
+			if !seen[id] {
+				seen[id] = true
+				itemIDs = append(itemIDs, id)
+			}
+		}
+
+		transfer, errResp := req.transfer(itemIDs)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		items, err := l.repo.TransferItems(r.Context(), listID, transfer)
+		if errResp := transferErrResponse(err); errResp != nil {
+			return nil, errResp
+		}
+
+		if transfer.Copy {
+			return &Response{Status: http.StatusCreated, Body: &ItemsBody{Items: items}}, nil
+		}
+
+		return &Response{Body: &ItemsBody{Items: items}}, nil
+	}
+
+	handleRequest(h)(w, r)
+}
+
+func (t TransferRequest) transfer(itemIDs []string) (todo.ItemTransfer, *ErrorResponse) {
+	if t.ListID == nil || strings.TrimSpace(*t.ListID) == "" {
+		return todo.ItemTransfer{}, &ErrorResponse{Status: http.StatusBadRequest, Error: `"listId" must not be blank`}
+	}
+
+	return todo.ItemTransfer{ItemIDs: itemIDs, ListID: strings.TrimSpace(*t.ListID), Copy: t.Copy}, nil
+}
+
+// transferErrResponse for a failure to transfer items, which is nil when there was no failure. Lists which have not been
+// shared with the user are not found, so that their existence is not revealed, while those which they may only view
+// are forbidden.
+func transferErrResponse(err error) *ErrorResponse {
+	if nfe := (todo.NotFoundError)(""); errors.As(err, &nfe) {
+		return &ErrorResponse{Status: http.StatusNotFound, Error: nfe.Error()}
+	}
+
+	if fe := (todo.ForbiddenError)(""); errors.As(err, &fe) {
+		return &ErrorResponse{Status: http.StatusForbidden, Error: fe.Error()}
+	}
+
+	if iae := (todo.InvalidArgumentError)(""); errors.As(err, &iae) {
+		return &ErrorResponse{Status: http.StatusBadRequest, Error: iae.Error()}
+	}
+
+	if err != nil {
+		return &ErrorResponse{Status: http.StatusInternalServerError, Error: "Internal Server Error", Cause: err}
+	}
+
+	return nil
+}
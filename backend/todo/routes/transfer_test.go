@@ -0,0 +1,296 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+	"github.com/dackroyd/todo-list/backend/todo/routes"
+)
+
+func TestListsAPI_TransferItem(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ItemID string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body    string
+		Code    int
+		Headers http.Header
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Missing List ID": {
+			Args:   args{Body: `{}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"listId\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Blank List ID": {
+			Args:   args{Body: `{"listId": " "}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"listId\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Unknown field": {
+			Args:   args{Body: `{"listId": "4", "itemIds": ["3"]}`, ItemID: "3", ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "invalid request body: json: unknown field \"itemIds\""}`, Code: http.StatusBadRequest},
+		},
+		"Target List Not Found": {
+			Args: args{Body: `{"listId": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4"}).Return(nil, todo.NotFoundError("list not found"))
+				},
+			},
+			Want: want{Body: `{"error": "list not found"}`, Code: http.StatusNotFound},
+		},
+		"Same List": {
+			Args: args{Body: `{"listId": "2"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "2"}).Return(nil, todo.InvalidArgumentError("items are already in list"))
+				},
+			},
+			Want: want{Body: `{"error": "items are already in list"}`, Code: http.StatusBadRequest},
+		},
+		"Query failure": {
+			Args: args{Body: `{"listId": "4"}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4"}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Moved": {
+			Args: args{Body: `{"listId": " 4 "}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4"}).
+						Return([]todo.Item{{ID: "3", Description: "Present", Position: 5120}}, nil)
+				},
+			},
+			Want: want{
+				Body: `{"item": {"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Copied": {
+			Args: args{Body: `{"listId": "4", "copy": true}`, ItemID: "3", ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4", Copy: true}).
+						Return([]todo.Item{{ID: "12", Description: "Present", Tags: todo.Tags{"@home"}, Position: 5120}}, nil)
+				},
+			},
+			Want: want{
+				Body:    `{"item": {"id": "12", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": ["@home"], "autoComplete": false, "progress": null}}`,
+				Code:    http.StatusCreated,
+				Headers: http.Header{"Location": []string{"/api/v1/lists/4/items/12"}},
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/items/%s/move-to", tt.Args.ListID, tt.Args.ItemID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			for k := range tt.Want.Headers {
+				assert.Equal(t, tt.Want.Headers.Values(k), res.Header.Values(k), "HTTP Header %q", k)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func TestListsAPI_TransferItems(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Body   string
+		ListID string
+	}
+
+	type fields struct {
+		MockExpectations func(ctx context.Context, l *listRepo)
+	}
+
+	type want struct {
+		Body string
+		Code int
+	}
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprint(i+1))
+	}
+
+	testTable := map[string]struct {
+		Args   args
+		Fields fields
+		Want   want
+	}{
+		"Missing Item IDs": {
+			Args:   args{Body: `{"listId": "4"}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"itemIds\" must not be empty"}`, Code: http.StatusBadRequest},
+		},
+		"Too many Item IDs": {
+			Args:   args{Body: `{"listId": "4", "itemIds": [` + strings.Join(tooMany, ",") + `]}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"itemIds\" must not contain more than 100 items"}`, Code: http.StatusBadRequest},
+		},
+		"Blank Item ID": {
+			Args:   args{Body: `{"listId": "4", "itemIds": ["3", " "]}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"itemIds\" must not contain blank IDs"}`, Code: http.StatusBadRequest},
+		},
+		"Blank List ID": {
+			Args:   args{Body: `{"listId": "", "itemIds": ["3"]}`, ListID: "2"},
+			Fields: fields{MockExpectations: func(context.Context, *listRepo) {}},
+			Want:   want{Body: `{"error": "\"listId\" must not be blank"}`, Code: http.StatusBadRequest},
+		},
+		"Item Not Found": {
+			Args: args{Body: `{"listId": "4", "itemIds": ["3", "5"]}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3", "5"}, ListID: "4"}).Return(nil, todo.NotFoundError("item not found"))
+				},
+			},
+			Want: want{Body: `{"error": "item not found"}`, Code: http.StatusNotFound},
+		},
+		"Query failure": {
+			Args: args{Body: `{"listId": "4", "itemIds": ["3"]}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4"}).Return(nil, errors.New("query failure"))
+				},
+			},
+			Want: want{Body: `{"error": "Internal Server Error"}`, Code: http.StatusInternalServerError},
+		},
+		"Moved": {
+			Args: args{Body: `{"listId": "4", "itemIds": ["5", " 3 ", "5"]}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"5", "3"}, ListID: "4"}).
+						Return([]todo.Item{{ID: "3", Description: "Present", Position: 5120}, {ID: "5", Description: "Wrap", Position: 6144}}, nil)
+				},
+			},
+			Want: want{
+				Body: `{"items": [
+					{"id": "3", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null},
+					{"id": "5", "description": "Wrap", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}
+				]}`,
+				Code: http.StatusOK,
+			},
+		},
+		"Copied": {
+			Args: args{Body: `{"listId": "4", "itemIds": ["3"], "copy": true}`, ListID: "2"},
+			Fields: fields{
+				MockExpectations: func(ctx context.Context, l *listRepo) {
+					l.OnTransferItems(ctx, "2", todo.ItemTransfer{ItemIDs: []string{"3"}, ListID: "4", Copy: true}).
+						Return([]todo.Item{{ID: "12", Description: "Present", Position: 5120}}, nil)
+				},
+			},
+			Want: want{
+				Body: `{"items": [{"id": "12", "description": "Present", "due": null, "completed": null, "parentId": null, "priority": "normal", "recurrence": null, "tags": [], "autoComplete": false, "progress": null}]}`,
+				Code: http.StatusCreated,
+			},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer failOnPanic(t)
+
+			testLogger := NewTestLogger(t)
+			ctx := withTestContext(context.Background(), t)
+
+			var repo listRepo
+			listsAPI := routes.NewListAPI(&repo)
+
+			tt.Fields.MockExpectations(ctx, &repo)
+			defer mock.AssertExpectationsForObjects(t, &repo)
+
+			h := routes.Handler(listsAPI, testAuth{}, testLogger, nil)
+
+			route := fmt.Sprintf("/api/v1/lists/%s/move-to", tt.Args.ListID)
+			req := httptest.NewRequest(http.MethodPost, route, strings.NewReader(tt.Args.Body)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			res := rec.Result()
+
+			assert.Equal(t, tt.Want.Code, res.StatusCode, "HTTP Status Code")
+
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err, "Body Read Error")
+			assert.JSONEq(t, tt.Want.Body, string(body), "HTTP Response Body")
+		})
+	}
+}
+
+func (l *listRepo) TransferItems(ctx context.Context, listID string, transfer todo.ItemTransfer) ([]todo.Item, error) {
+	args := l.Called(testContext(ctx), listID, transfer)
+	return args.Get(0).([]todo.Item), args.Error(1)
+}
+
+// OnTransferItems provides a type-safe mock setup function, used instead of using 'On("TransferItems, ...)'
+func (l *listRepo) OnTransferItems(ctx context.Context, listID string, transfer todo.ItemTransfer) *call2[[]todo.Item, error] {
+	m := l.On("TransferItems", testContext(ctx), listID, transfer)
+	return &call2[[]todo.Item, error]{m: m}
+}
@@ -0,0 +1,154 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Template of a TODO list, from which lists having the same items can be created. The due dates of template items are
+// relative to an anchor date, which is chosen when a list is created from the template.
+type Template struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// DueHorizon of the lists created from the template. Nil when the server default is used.
+	DueHorizon *Duration `json:"dueHorizon,omitempty"`
+}
+
+// TemplateItem which is created as an item of each list created from its template.
+type TemplateItem struct {
+	ID string `json:"id"`
+	// ParentID of the template item which this is a subtask of. Nil for top-level items.
+	ParentID    *string `json:"parentId"`
+	Description string  `json:"description"`
+	// DueOffset from the anchor date of when the item is due. Nil when the item has no due date.
+	DueOffset    *DueOffset  `json:"dueOffset"`
+	Priority     Priority    `json:"priority"`
+	Recurrence   *Recurrence `json:"recurrence"`
+	Tags         Tags        `json:"tags"`
+	AutoComplete bool        `json:"autoComplete"`
+	// Position of the item relative to the other items of the template, which is not meaningful to clients.
+	Position int64 `json:"-"`
+}
+
+// TemplateSave of a TODO list as a template.
+type TemplateSave struct {
+	// Description of the template. When empty, the description of the list is used.
+	Description string
+	// Anchor date which the due dates of the items are made relative to. When nil, the date of the earliest due item
+	// is used.
+	Anchor *time.Time
+}
+
+// TemplateInstance of a template, which is created as a new TODO list.
+type TemplateInstance struct {
+	// Anchor date which the due dates of the items are resolved from.
+	Anchor time.Time
+	// Description of the list. When empty, the description of the template is used.
+	Description string
+}
+
+// DueOffset of a due date from an anchor date, as a number of days after the anchor date and a time of day. Offsets
+// are represented as strings such as "+3 days", or "-1 day 17:30" for 5:30pm the day before the anchor date.
+//
+// Days are calendar days rather than 24-hour periods, so that items remain due at the same local time of day across
+// daylight saving changes.
+type DueOffset struct {
+	Days int
+	// TimeOfDay at which the item is due, as the time since midnight.
+	TimeOfDay time.Duration
+}
+
+var dueOffsetPattern = regexp.MustCompile(`^([+-]?\d+) days?(?: (\d{2}):(\d{2})(?::(\d{2}))?)?$`)
+
+// ParseDueOffset from a string such as "+3 days" or "+1 day 09:00".
+func ParseDueOffset(s string) (*DueOffset, error) {
+	m := dueOffsetPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf(`due offset %q must be a number of days and an optional time of day, such as "+3 days" or "+1 day 09:00"`, s)
+	}
+
+	days, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil, fmt.Errorf("due offset days %q are out of range", m[1])
+	}
+
+	var hms [3]int
+	for i, v := range m[2:] {
+		if v != "" {
+			hms[i], _ = strconv.Atoi(v)
+		}
+	}
+
+	if hms[0] > 23 || hms[1] > 59 || hms[2] > 59 {
+		return nil, fmt.Errorf("due offset %q has an invalid time of day", s)
+	}
+
+	tod := time.Duration(hms[0])*time.Hour + time.Duration(hms[1])*time.Minute + time.Duration(hms[2])*time.Second
+
+	return &DueOffset{Days: days, TimeOfDay: tod}, nil
+}
+
+// DueOffsetFrom the anchor date of the due date, where the time of day is that of the due date in the location.
+func DueOffsetFrom(anchor, due time.Time, loc *time.Location) DueOffset {
+	due = due.In(loc)
+
+	h, m, s := due.Clock()
+	tod := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	return DueOffset{Days: civilDay(due) - civilDay(anchor), TimeOfDay: tod}
+}
+
+// Due date resolved from the anchor date, in the location.
+func (o DueOffset) Due(anchor time.Time, loc *time.Location) time.Time {
+	h := int(o.TimeOfDay / time.Hour)
+	m := int(o.TimeOfDay % time.Hour / time.Minute)
+	s := int(o.TimeOfDay % time.Minute / time.Second)
+
+	return time.Date(anchor.Year(), anchor.Month(), anchor.Day()+o.Days, h, m, s, 0, loc)
+}
+
+func (o DueOffset) String() string {
+	unit := "days"
+	if o.Days == 1 || o.Days == -1 {
+		unit = "day"
+	}
+
+	s := fmt.Sprintf("%+d %s", o.Days, unit)
+
+	if o.TimeOfDay == 0 {
+		return s
+	}
+
+	h := int(o.TimeOfDay / time.Hour)
+	m := int(o.TimeOfDay % time.Hour / time.Minute)
+	s += fmt.Sprintf(" %02d:%02d", h, m)
+
+	if sec := int(o.TimeOfDay % time.Minute / time.Second); sec != 0 {
+		s += fmt.Sprintf(":%02d", sec)
+	}
+
+	return s
+}
+
+func (o DueOffset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+func (o *DueOffset) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("due offset must be a string: %w", err)
+	}
+
+	v, err := ParseDueOffset(s)
+	if err != nil {
+		return err
+	}
+
+	*o = *v
+
+	return nil
+}
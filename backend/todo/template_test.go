@@ -0,0 +1,133 @@
+package todo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dackroyd/todo-list/backend/todo"
+)
+
+func TestParseDueOffset(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		Error  string
+		Offset *todo.DueOffset
+		String string
+	}
+
+	testTable := map[string]struct {
+		Offset string
+		Want   want
+	}{
+		"Days": {
+			Offset: "+3 days",
+			Want:   want{Offset: &todo.DueOffset{Days: 3}, String: "+3 days"},
+		},
+		"Single Day": {
+			Offset: "1 day",
+			Want:   want{Offset: &todo.DueOffset{Days: 1}, String: "+1 day"},
+		},
+		"Anchor Date": {
+			Offset: "+0 days",
+			Want:   want{Offset: &todo.DueOffset{}, String: "+0 days"},
+		},
+		"Days before with Time of Day": {
+			Offset: "-1 day 17:30",
+			Want:   want{Offset: &todo.DueOffset{Days: -1, TimeOfDay: 17*time.Hour + 30*time.Minute}, String: "-1 day 17:30"},
+		},
+		"Time of Day with Seconds": {
+			Offset: "+2 days 09:00:15",
+			Want:   want{Offset: &todo.DueOffset{Days: 2, TimeOfDay: 9*time.Hour + 15*time.Second}, String: "+2 days 09:00:15"},
+		},
+		"Missing Days": {
+			Offset: "09:00",
+			Want:   want{Error: `due offset "09:00" must be a number of days and an optional time of day, such as "+3 days" or "+1 day 09:00"`},
+		},
+		"Invalid Time of Day": {
+			Offset: "+1 day 24:00",
+			Want:   want{Error: `due offset "+1 day 24:00" has an invalid time of day`},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			o, err := todo.ParseDueOffset(tt.Offset)
+
+			if tt.Want.Error != "" {
+				assert.EqualError(t, err, tt.Want.Error, "Parse error")
+				return
+			}
+
+			require.NoError(t, err, "Parse error")
+			assert.Equal(t, tt.Want.Offset, o, "Due Offset")
+			assert.Equal(t, tt.Want.String, o.String(), "String")
+		})
+	}
+}
+
+func TestDueOffset(t *testing.T) {
+	t.Parallel()
+
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err, "Loading location")
+
+	type args struct {
+		Anchor time.Time
+		Due    time.Time
+		Loc    *time.Location
+	}
+
+	type want struct {
+		Due    time.Time
+		Offset todo.DueOffset
+	}
+
+	testTable := map[string]struct {
+		Args args
+		Want want
+	}{
+		"Same Day": {
+			Args: args{Anchor: time.Date(2023, time.June, 29, 0, 0, 0, 0, time.UTC), Due: time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC), Loc: time.UTC},
+			Want: want{Offset: todo.DueOffset{TimeOfDay: 8 * time.Hour}, Due: time.Date(2023, time.June, 29, 8, 0, 0, 0, time.UTC)},
+		},
+		"Before the Anchor Date": {
+			Args: args{Anchor: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC), Due: time.Date(2023, time.June, 29, 17, 30, 0, 0, time.UTC), Loc: time.UTC},
+			Want: want{Offset: todo.DueOffset{Days: -2, TimeOfDay: 17*time.Hour + 30*time.Minute}, Due: time.Date(2023, time.June, 29, 17, 30, 0, 0, time.UTC)},
+		},
+		"Across Months": {
+			Args: args{Anchor: time.Date(2023, time.January, 30, 0, 0, 0, 0, time.UTC), Due: time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC), Loc: time.UTC},
+			Want: want{Offset: todo.DueOffset{Days: 31}, Due: time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		"Date in the Location": {
+			// 9am in Sydney is 23:00 UTC on the day before
+			Args: args{Anchor: time.Date(2023, time.June, 29, 0, 0, 0, 0, time.UTC), Due: time.Date(2023, time.June, 29, 23, 0, 0, 0, time.UTC), Loc: sydney},
+			Want: want{Offset: todo.DueOffset{Days: 1, TimeOfDay: 9 * time.Hour}, Due: time.Date(2023, time.June, 30, 9, 0, 0, 0, sydney)},
+		},
+		"Across Daylight Saving ending": {
+			Args: args{Anchor: time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC), Due: time.Date(2023, time.April, 3, 9, 0, 0, 0, sydney), Loc: sydney},
+			Want: want{Offset: todo.DueOffset{Days: 2, TimeOfDay: 9 * time.Hour}, Due: time.Date(2023, time.April, 3, 9, 0, 0, 0, sydney)},
+		},
+	}
+
+	for name, tt := range testTable {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			offset := todo.DueOffsetFrom(tt.Args.Anchor, tt.Args.Due, tt.Args.Loc)
+			assert.Equal(t, tt.Want.Offset, offset, "Due Offset")
+
+			due := offset.Due(tt.Args.Anchor, tt.Args.Loc)
+			assert.True(t, tt.Want.Due.Equal(due), "Due: expected %s, got %s", tt.Want.Due, due)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package todo
+
+import "context"
+
+// User who owns TODO lists, and may only access their own lists.
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UnauthenticatedError occurs when the user making a request cannot be identified, such as when no credentials are
+// provided.
+type UnauthenticatedError string
+
+func (u UnauthenticatedError) Error() string {
+	return string(u)
+}
+
+type userKey struct{}
+
+// WithUser who is authenticated within the context, which TODO lists are scoped to.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext who is authenticated. Not ok when no user has been authenticated.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok
+}
@@ -2,16 +2,239 @@
 
 CREATE SCHEMA todo AUTHORIZATION todo;
 
+CREATE TABLE users(
+  id      SERIAL    PRIMARY KEY,
+  name    TEXT      NOT NULL UNIQUE,
+  created TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- API keys authenticating scripts as the user. Only a hash of each key is stored, so that keys cannot be recovered.
+CREATE TABLE api_keys(
+  id       SERIAL    PRIMARY KEY,
+  user_id  INT       NOT NULL,
+  name     TEXT      NOT NULL,
+  key_hash BYTEA     NOT NULL UNIQUE,       -- SHA-256 of the key
+  scopes   TEXT[]    NOT NULL,              -- lists:read, lists:write, admin
+  created  TIMESTAMP NOT NULL DEFAULT now(),
+  expires  TIMESTAMP,                       -- NULL for keys which never expire
+  revoked  TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
 CREATE TABLE lists(
   id          SERIAL PRIMARY KEY,
-  description TEXT
+  owner_id    INT    NOT NULL,
+  description TEXT,
+  due_horizon INTERVAL,
+  archived    TIMESTAMP,
+  deleted     TIMESTAMP, -- moved to the trash, purged once older than the retention period
+  search      TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', COALESCE(description, ''))) STORED,
+  FOREIGN KEY (owner_id) REFERENCES users (id)
 );
 
+CREATE INDEX lists_owner_id_idx ON lists (owner_id, id);
+
+CREATE INDEX lists_search_idx ON lists USING GIN (search);
+CREATE INDEX lists_deleted_idx ON lists (deleted) WHERE deleted IS NOT NULL;
+
+-- Members who lists are shared with, in addition to their owner
+CREATE TABLE list_members(
+  list_id INT       NOT NULL,
+  user_id INT       NOT NULL,
+  role    TEXT      NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+  added   TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (list_id, user_id),
+  FOREIGN KEY (list_id) REFERENCES lists (id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX list_members_user_id_idx ON list_members (user_id, list_id);
+
 CREATE TABLE items(
-  id          SERIAL    PRIMARY KEY,
-  list_id     INT       NOT NULL,
-  description TEXT      NOT NULL,
-  due         TIMESTAMP,
-  completed   TIMESTAMP,
-  FOREIGN KEY (list_id) REFERENCES lists (id)
+  id            SERIAL    PRIMARY KEY,
+  list_id       INT       NOT NULL,
+  parent_id     INT,                            -- item which this is a subtask of
+  description   TEXT      NOT NULL,
+  due           TIMESTAMP,
+  completed     TIMESTAMP,
+  priority      SMALLINT  NOT NULL DEFAULT 0,   -- -1 low, 0 normal, 1 high, 2 urgent
+  recurrence    TEXT,                           -- RRULE, such as FREQ=WEEKLY;BYDAY=MO
+  auto_complete BOOLEAN   NOT NULL DEFAULT false, -- complete once all subtasks are
+  position      BIGINT    NOT NULL,               -- rank within the list, with gaps to move items between
+  archived      TIMESTAMP,
+  deleted       TIMESTAMP,                      -- moved to the trash, purged once older than the retention period
+  search        TSVECTOR  GENERATED ALWAYS AS (to_tsvector('english', description)) STORED,
+  FOREIGN KEY (list_id) REFERENCES lists (id),
+  FOREIGN KEY (parent_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+CREATE INDEX items_parent_id_idx ON items (parent_id);
+CREATE INDEX items_list_id_position_idx ON items (list_id, position);
+CREATE INDEX items_search_idx ON items USING GIN (search);
+CREATE INDEX items_deleted_idx ON items (deleted) WHERE deleted IS NOT NULL;
+
+CREATE TABLE tags(
+  id   SERIAL PRIMARY KEY,
+  name TEXT   NOT NULL UNIQUE
+);
+
+CREATE TABLE item_tags(
+  item_id INT NOT NULL,
+  tag_id  INT NOT NULL,
+  PRIMARY KEY (item_id, tag_id),
+  FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+  FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE INDEX item_tags_tag_id_idx ON item_tags (tag_id);
+
+CREATE TABLE templates(
+  id          SERIAL PRIMARY KEY,
+  owner_id    INT    NOT NULL,
+  description TEXT,
+  due_horizon INTERVAL,
+  FOREIGN KEY (owner_id) REFERENCES users (id)
+);
+
+CREATE INDEX templates_owner_id_idx ON templates (owner_id, id);
+
+CREATE TABLE template_items(
+  id            SERIAL   PRIMARY KEY,
+  template_id   INT      NOT NULL,
+  parent_id     INT,                            -- template item which this is a subtask of
+  description   TEXT     NOT NULL,
+  due_days      INT,                            -- days after the anchor date when due, NULL without a due date
+  due_time      INTERVAL,                       -- time of day when due, on that day
+  priority      SMALLINT NOT NULL DEFAULT 0,
+  recurrence    TEXT,
+  tags          TEXT[]   NOT NULL DEFAULT '{}',
+  auto_complete BOOLEAN  NOT NULL DEFAULT false,
+  position      BIGINT   NOT NULL,
+  FOREIGN KEY (template_id) REFERENCES templates (id) ON DELETE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES template_items (id) ON DELETE CASCADE
 );
+
+CREATE INDEX template_items_template_id_idx ON template_items (template_id);
+
+-- History of every change to lists and items, recorded by triggers so that no change can be missed. Lists and items are
+-- not referenced by foreign keys, so that their history outlives them.
+CREATE TABLE history(
+  id        BIGSERIAL PRIMARY KEY,
+  list_id   INT       NOT NULL,
+  item_id   INT,                              -- NULL for changes of the list itself
+  actor     TEXT,                             -- from the todo.actor setting of the transaction, when set
+  changed   TIMESTAMP NOT NULL DEFAULT now(),
+  operation TEXT      NOT NULL,               -- create, update, delete, tag, untag
+  fields    JSONB     NOT NULL                -- {"column": {"old": value, "new": value}}
+);
+
+CREATE INDEX history_list_id_idx ON history (list_id, id);
+CREATE INDEX history_item_id_idx ON history (item_id, id) WHERE item_id IS NOT NULL;
+
+CREATE FUNCTION history_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'history is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER history_append_only
+  BEFORE UPDATE OR DELETE OR TRUNCATE ON history
+  FOR EACH STATEMENT EXECUTE FUNCTION history_append_only();
+
+-- record_history of a list or item, with the fields which differ between its old and new row
+CREATE FUNCTION record_history(for_list INT, for_item INT, operation TEXT, old_row JSONB, new_row JSONB) RETURNS void AS $$
+DECLARE
+  fields JSONB;
+BEGIN
+  -- Changes made by the system, rather than by the actor, are not recorded
+  IF current_setting('todo.history_paused', true) = 'on' THEN
+    RETURN;
+  END IF;
+
+  SELECT jsonb_object_agg(k, jsonb_build_object('old', old_row -> k, 'new', new_row -> k))
+    INTO fields
+    FROM jsonb_object_keys(COALESCE(old_row, '{}') || COALESCE(new_row, '{}')) k
+   WHERE k NOT IN ('id', 'search')
+     AND (old_row -> k) IS DISTINCT FROM (new_row -> k);
+
+  -- Updates which leave every field unchanged are not recorded
+  IF fields IS NOT NULL THEN
+    INSERT INTO history (list_id, item_id, actor, operation, fields)
+    VALUES (for_list, for_item, NULLIF(current_setting('todo.actor', true), ''), operation, fields);
+  END IF;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION record_list_history() RETURNS trigger AS $$
+BEGIN
+  -- Horizons are recorded in seconds, rather than as interval text
+  IF TG_OP = 'DELETE' THEN
+    PERFORM record_history(OLD.id, NULL, 'delete', to_jsonb(OLD) || jsonb_build_object('due_horizon', EXTRACT(EPOCH FROM OLD.due_horizon)), NULL);
+  ELSIF TG_OP = 'INSERT' THEN
+    PERFORM record_history(NEW.id, NULL, 'create', NULL, to_jsonb(NEW) || jsonb_build_object('due_horizon', EXTRACT(EPOCH FROM NEW.due_horizon)));
+  ELSE
+    PERFORM record_history(NEW.id, NULL, 'update',
+      to_jsonb(OLD) || jsonb_build_object('due_horizon', EXTRACT(EPOCH FROM OLD.due_horizon)),
+      to_jsonb(NEW) || jsonb_build_object('due_horizon', EXTRACT(EPOCH FROM NEW.due_horizon)));
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lists_history
+  AFTER INSERT OR UPDATE OR DELETE ON lists
+  FOR EACH ROW EXECUTE FUNCTION record_list_history();
+
+CREATE FUNCTION record_item_history() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    PERFORM record_history(OLD.list_id, OLD.id, 'delete', to_jsonb(OLD), NULL);
+  ELSIF TG_OP = 'INSERT' THEN
+    PERFORM record_history(NEW.list_id, NEW.id, 'create', NULL, to_jsonb(NEW));
+  ELSE
+    PERFORM record_history(NEW.list_id, NEW.id, 'update', to_jsonb(OLD), to_jsonb(NEW));
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER items_history
+  AFTER INSERT OR UPDATE OR DELETE ON items
+  FOR EACH ROW EXECUTE FUNCTION record_item_history();
+
+CREATE FUNCTION record_item_tag_history() RETURNS trigger AS $$
+DECLARE
+  tagged_item INT;
+  tagged_list INT;
+  tag         TEXT;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    tagged_item := NEW.item_id;
+    SELECT t.name INTO tag FROM tags t WHERE t.id = NEW.tag_id;
+  ELSE
+    tagged_item := OLD.item_id;
+    SELECT t.name INTO tag FROM tags t WHERE t.id = OLD.tag_id;
+  END IF;
+
+  SELECT i.list_id INTO tagged_list FROM items i WHERE i.id = tagged_item;
+
+  -- Tags removed along with their item are part of its deletion
+  IF tagged_list IS NULL THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'INSERT' THEN
+    PERFORM record_history(tagged_list, tagged_item, 'tag', NULL, jsonb_build_object('tags', tag));
+  ELSE
+    PERFORM record_history(tagged_list, tagged_item, 'untag', jsonb_build_object('tags', tag), NULL);
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER item_tags_history
+  AFTER INSERT OR DELETE ON item_tags
+  FOR EACH ROW EXECUTE FUNCTION record_item_tag_history();
@@ -37,8 +37,19 @@ BEGIN
 END
 $$ LANGUAGE plpgsql;
 
-INSERT INTO lists
+-- All lists belong to the demo user, which the simulated UI authenticates as
+INSERT INTO users (id, name)
+VALUES (1, 'demo');
+
+SELECT setval('users_id_seq', (SELECT MAX(id) + 1 FROM users));
+
+INSERT INTO lists (
+       id,
+       owner_id,
+       description
+)
 SELECT id,
+       1 AS owner_id,
        random_choice(array['Chores', 'Golang-Syd Meetup', 'Holiday', 'Moving House']) AS description
   FROM generate_series(1, 5000) AS id
 ;
@@ -50,7 +61,8 @@ INSERT INTO items (
        list_id,
        description,
        due,
-       completed
+       completed,
+       position
 )
 SELECT id,
        1 + ((900 * exp(abs(cos(id))))::int) % 1000 AS list_id,
@@ -62,7 +74,8 @@ SELECT id,
        CASE
            WHEN random() > 0.25 THEN NULL
            ELSE date_trunc('hour', now()) + random_between(-90, 90) * INTERVAL '1 day'
-       END AS completed
+       END AS completed,
+       id * 1024 AS position
   FROM generate_series(1, 50000) WITH ORDINALITY AS t(id, rownum);
 ;
 
@@ -99,7 +99,7 @@ function api_fetch() (
     --kind 'client' \
     --attrs "http.request.method=GET,server.address=${api_host},server.port=${api_port},url.full=${url}" \
     --name "GET" \
-    -- bash -c "curl ${DEBUG+-v} -H \"traceparent: \$TRACEPARENT\" -o /dev/null \"${url}\""
+    -- bash -c "curl ${DEBUG+-v} -H \"traceparent: \$TRACEPARENT\" -H \"X-Forwarded-User: demo\" -o /dev/null \"${url}\""
 )
 
 request_user_homepage "447"